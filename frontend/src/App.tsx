@@ -1,8 +1,23 @@
-import { useState } from 'react';
+import { useState, useEffect } from 'react';
 import Timer from './components/Timer';
 import Statistics from './components/Statistics';
 import EditTimeSlotModal from './components/EditTimeSlotModal';
-import { DeleteTimeSlot } from '../wailsjs/go/app/App';
+import { errorMessage } from './errors';
+import {
+  ConfirmStillWorking,
+  DeleteTimeSlot,
+  GetIdlePeriod,
+  GetRecoveredSlot,
+  IsDatabaseLocked,
+  ResolveIdlePeriod,
+  ResolveRecoveredSlot,
+  SaveWindowState,
+  StartFromSlot,
+  StartTimer,
+  StopTimerConfirmed,
+  UnlockDatabase,
+} from '../wailsjs/go/app/App';
+import { EventsOn, WindowGetPosition, WindowGetSize } from '../wailsjs/runtime/runtime';
 import './App.css';
 
 interface TimeSlot {
@@ -13,10 +28,196 @@ interface TimeSlot {
   duration_seconds: number;
 }
 
+interface RecoveredSlot {
+  slot: TimeSlot;
+  elapsed_seconds: number;
+}
+
+interface IdlePeriod {
+  slot_id: number;
+  task_name: string;
+  start_time: string;
+  end_time: string;
+  seconds: number;
+}
+
+interface RecurringTask {
+  id: number;
+  task_name: string;
+  start: string;
+  end: string;
+}
+
+interface InAppNotification {
+  title: string;
+  message: string;
+}
+
+function formatElapsed(seconds: number): string {
+  const hours = Math.floor(seconds / 3600);
+  const minutes = Math.floor((seconds % 3600) / 60);
+  return hours > 0 ? `${hours}h ${minutes}m` : `${minutes}m`;
+}
+
 function App() {
   const [activeTab, setActiveTab] = useState<'timer' | 'statistics'>('timer');
   const [editingSlot, setEditingSlot] = useState<TimeSlot | null>(null);
   const [refreshKey, setRefreshKey] = useState(0);
+  const [notification, setNotification] = useState<InAppNotification | null>(null);
+  const [stillWorkingSlot, setStillWorkingSlot] = useState<TimeSlot | null>(null);
+  const [recovered, setRecovered] = useState<RecoveredSlot | null>(null);
+  const [idlePeriod, setIdlePeriod] = useState<IdlePeriod | null>(null);
+  const [dueTask, setDueTask] = useState<RecurringTask | null>(null);
+  const [idleTask, setIdleTask] = useState('');
+  const [idleError, setIdleError] = useState('');
+  const [timerKey, setTimerKey] = useState(0);
+  const [locked, setLocked] = useState(false);
+  const [passphrase, setPassphrase] = useState('');
+  const [unlockError, setUnlockError] = useState('');
+
+  useEffect(() => {
+    IsDatabaseLocked().then(setLocked);
+  }, []);
+
+  const handleUnlock = async () => {
+    try {
+      await UnlockDatabase(passphrase);
+      setLocked(false);
+      setPassphrase('');
+      setUnlockError('');
+      setTimerKey(prev => prev + 1);
+      setRefreshKey(prev => prev + 1);
+    } catch (error) {
+      setUnlockError(errorMessage(error));
+    }
+  };
+
+  useEffect(() => {
+    // The event may fire before this component mounts, so also ask directly
+    GetRecoveredSlot()
+      .then((data) => data && setRecovered(data))
+      .catch((error) => console.error('Failed to get recovered timer:', error));
+    return EventsOn('timer:recovered', (data: RecoveredSlot) => {
+      setRecovered(data);
+    });
+  }, []);
+
+  const handleResolveRecovered = async (action: 'keep' | 'stop' | 'discard') => {
+    try {
+      await ResolveRecoveredSlot(action, '');
+      if (action !== 'keep') {
+        setTimerKey(prev => prev + 1);
+        setRefreshKey(prev => prev + 1);
+      }
+    } catch (error) {
+      console.error('Failed to resolve recovered timer:', error);
+    }
+    setRecovered(null);
+  };
+
+  useEffect(() => {
+    // Remember the window bounds once resizing settles
+    let timeout: number | undefined;
+    const handleResize = () => {
+      window.clearTimeout(timeout);
+      timeout = window.setTimeout(async () => {
+        try {
+          const [position, size] = await Promise.all([WindowGetPosition(), WindowGetSize()]);
+          await SaveWindowState(position.x, position.y, size.w, size.h);
+        } catch (error) {
+          console.error('Failed to save window state:', error);
+        }
+      }, 500);
+    };
+    window.addEventListener('resize', handleResize);
+    return () => {
+      window.clearTimeout(timeout);
+      window.removeEventListener('resize', handleResize);
+    };
+  }, []);
+
+  useEffect(() => {
+    // Shown when no desktop notification backend is available
+    return EventsOn('notification:show', (data: InAppNotification) => {
+      setNotification(data);
+    });
+  }, []);
+
+  useEffect(() => {
+    const offPrompt = EventsOn('timer:still-working', (slot: TimeSlot) => {
+      setStillWorkingSlot(slot);
+    });
+    const offAutoStop = EventsOn('timer:auto-stopped', () => {
+      setStillWorkingSlot(null);
+      setRefreshKey(prev => prev + 1);
+    });
+    return () => {
+      offPrompt();
+      offAutoStop();
+    };
+  }, []);
+
+  useEffect(() => {
+    GetIdlePeriod()
+      .then((data) => data && setIdlePeriod(data))
+      .catch((error) => console.error('Failed to get idle period:', error));
+    return EventsOn('timer:idle', (data: IdlePeriod) => {
+      setIdlePeriod(data);
+      setIdleTask('');
+      setIdleError('');
+    });
+  }, []);
+
+  useEffect(() => {
+    // Recurring tasks with the prompt action ask to be started when they come up
+    return EventsOn('recurring:due', (task: RecurringTask) => {
+      setDueTask(task);
+    });
+  }, []);
+
+  const handleStartDueTask = async () => {
+    if (!dueTask) {
+      return;
+    }
+    try {
+      await StartTimer(dueTask.task_name);
+      setTimerKey(prev => prev + 1);
+      setActiveTab('timer');
+    } catch (error) {
+      console.error('Failed to start timer:', error);
+      alert(errorMessage(error));
+    }
+    setDueTask(null);
+  };
+
+  const handleResolveIdle = async (action: 'keep' | 'discard' | 'reassign') => {
+    try {
+      await ResolveIdlePeriod(action, action === 'reassign' ? idleTask : '');
+      if (action !== 'keep') {
+        setTimerKey(prev => prev + 1);
+        setRefreshKey(prev => prev + 1);
+      }
+      setIdlePeriod(null);
+      setIdleError('');
+    } catch (error) {
+      setIdleError(errorMessage(error));
+    }
+  };
+
+  const handleConfirmStillWorking = async () => {
+    await ConfirmStillWorking();
+    setStillWorkingSlot(null);
+  };
+
+  const handleStopFromPrompt = async () => {
+    try {
+      await StopTimerConfirmed();
+      setRefreshKey(prev => prev + 1);
+    } catch (error) {
+      console.error('Failed to stop timer:', error);
+    }
+    setStillWorkingSlot(null);
+  };
 
   const handleEdit = (slot: TimeSlot) => {
     setEditingSlot(slot);
@@ -35,11 +236,42 @@ function App() {
     }
   };
 
+  const handleStartAgain = async (id: number) => {
+    try {
+      await StartFromSlot(id);
+      setTimerKey(prev => prev + 1);
+      setActiveTab('timer');
+    } catch (error) {
+      console.error('Failed to start timer:', error);
+      alert('Failed to start timer');
+    }
+  };
+
   const handleSave = () => {
     // Trigger refresh of statistics
     setRefreshKey(prev => prev + 1);
   };
 
+  if (locked) {
+    return (
+      <div id="App">
+        <div className="unlock-screen">
+          <h2>Database is encrypted</h2>
+          <input
+            type="password"
+            placeholder="Passphrase"
+            value={passphrase}
+            onChange={(e) => setPassphrase(e.target.value)}
+            onKeyDown={(e) => e.key === 'Enter' && handleUnlock()}
+            autoFocus
+          />
+          <button onClick={handleUnlock}>Unlock</button>
+          {unlockError && <p className="unlock-error">{unlockError}</p>}
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div id="App">
       <div className="header">
@@ -60,10 +292,89 @@ function App() {
         </nav>
       </div>
 
+      {notification && (
+        <div className="notification-banner">
+          <div>
+            <strong>{notification.title}</strong>
+            <span>{notification.message}</span>
+          </div>
+          <button onClick={() => setNotification(null)}>Dismiss</button>
+        </div>
+      )}
+
+      {recovered && (
+        <div className="notification-banner">
+          <div>
+            <strong>Recovered running timer</strong>
+            <span>
+              {recovered.slot.task_name} ({formatElapsed(recovered.elapsed_seconds)})
+            </span>
+          </div>
+          <div className="notification-actions">
+            <button onClick={() => handleResolveRecovered('keep')}>Keep</button>
+            <button onClick={() => handleResolveRecovered('stop')}>Stop</button>
+            <button onClick={() => handleResolveRecovered('discard')}>Discard</button>
+          </div>
+        </div>
+      )}
+
+      {idlePeriod && (
+        <div className="notification-banner">
+          <div>
+            <strong>You were away</strong>
+            <span>
+              {formatElapsed(idlePeriod.seconds)} idle while tracking '{idlePeriod.task_name}'
+            </span>
+            {idleError && <span className="unlock-error"> {idleError}</span>}
+          </div>
+          <div className="notification-actions">
+            <button onClick={() => handleResolveIdle('keep')}>Keep</button>
+            <button onClick={() => handleResolveIdle('discard')}>Discard</button>
+            <input
+              placeholder="Other task"
+              value={idleTask}
+              onChange={(e) => setIdleTask(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && handleResolveIdle('reassign')}
+            />
+            <button onClick={() => handleResolveIdle('reassign')} disabled={!idleTask.trim()}>
+              Assign
+            </button>
+          </div>
+        </div>
+      )}
+
+      {dueTask && (
+        <div className="notification-banner">
+          <div>
+            <strong>Scheduled task</strong>
+            <span>
+              '{dueTask.task_name}' is scheduled for {dueTask.start}-{dueTask.end}
+            </span>
+          </div>
+          <div className="notification-actions">
+            <button onClick={handleStartDueTask}>Start</button>
+            <button onClick={() => setDueTask(null)}>Dismiss</button>
+          </div>
+        </div>
+      )}
+
+      {stillWorkingSlot && (
+        <div className="notification-banner">
+          <div>
+            <strong>Still working?</strong>
+            <span>Are you still working on '{stillWorkingSlot.task_name}'?</span>
+          </div>
+          <div className="notification-actions">
+            <button onClick={handleConfirmStillWorking}>Yes</button>
+            <button onClick={handleStopFromPrompt}>Stop timer</button>
+          </div>
+        </div>
+      )}
+
       <div className="content">
-        {activeTab === 'timer' && <Timer />}
+        {activeTab === 'timer' && <Timer key={timerKey} />}
         {activeTab === 'statistics' && (
-          <Statistics key={refreshKey} onEdit={handleEdit} onDelete={handleDelete} />
+          <Statistics key={refreshKey} onEdit={handleEdit} onDelete={handleDelete} onStartAgain={handleStartAgain} />
         )}
       </div>
 
@@ -1,5 +1,6 @@
 import { useState, useEffect } from 'react';
-import { GetTimeSlotsByDate, GetTaskStatistics } from '../../wailsjs/go/app/App';
+import { GetTimeSlotsByDate, GetTaskStatistics, GetTaskColors } from '../../wailsjs/go/app/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import TimeSlotList from './TimeSlotList';
 
 interface TimeSlot {
@@ -10,31 +11,51 @@ interface TimeSlot {
   duration_seconds: number;
 }
 
+interface DataChange {
+  scope: 'today' | 'range' | 'all';
+  start_date?: string;
+  end_date?: string;
+}
+
 interface StatisticsProps {
   onEdit?: (slot: TimeSlot) => void;
   onDelete?: (id: number) => void;
+  onStartAgain?: (id: number) => void;
   key?: number;
 }
 
-function Statistics({ onEdit, onDelete }: StatisticsProps) {
+function Statistics({ onEdit, onDelete, onStartAgain }: StatisticsProps) {
   const [selectedDate, setSelectedDate] = useState(new Date().toISOString().split('T')[0]);
   const [slots, setSlots] = useState<TimeSlot[]>([]);
   const [taskStats, setTaskStats] = useState<Record<string, number>>({});
+  const [taskColors, setTaskColors] = useState<Record<string, string>>({});
   const [loading, setLoading] = useState(false);
 
   useEffect(() => {
     loadStatistics();
   }, [selectedDate]);
 
+  useEffect(() => {
+    // The backend announces writes, so only a change touching the shown date reloads
+    return EventsOn('data:changed', (change: DataChange) => {
+      if (change.scope === 'all' ||
+          (change.start_date! <= selectedDate && selectedDate <= change.end_date!)) {
+        loadStatistics();
+      }
+    });
+  }, [selectedDate]);
+
   const loadStatistics = async () => {
     setLoading(true);
     try {
-      const [timeSlots, stats] = await Promise.all([
+      const [timeSlots, stats, colors] = await Promise.all([
         GetTimeSlotsByDate(selectedDate),
-        GetTaskStatistics(selectedDate),
+        GetTaskStatistics(selectedDate, 0, ''),
+        GetTaskColors(),
       ]);
       setSlots(timeSlots || []);
       setTaskStats(stats || {});
+      setTaskColors(colors || {});
     } catch (error) {
       console.error('Failed to load statistics:', error);
     } finally {
@@ -80,7 +101,10 @@ function Statistics({ onEdit, onDelete }: StatisticsProps) {
                   .sort((a, b) => b[1] - a[1])
                   .map(([task, seconds]) => (
                     <li key={task} className="stat-item">
-                      <span className="stat-task">{task}</span>
+                      <span className="stat-task">
+                        <span className="stat-color" style={{ backgroundColor: taskColors[task] }} />
+                        {task}
+                      </span>
                       <span className="stat-duration">{formatDuration(seconds)}</span>
                     </li>
                   ))}
@@ -88,7 +112,7 @@ function Statistics({ onEdit, onDelete }: StatisticsProps) {
             )}
           </div>
 
-          <TimeSlotList slots={slots} onEdit={onEdit} onDelete={onDelete} />
+          <TimeSlotList slots={slots} onEdit={onEdit} onDelete={onDelete} onStartAgain={onStartAgain} />
         </>
       )}
     </div>
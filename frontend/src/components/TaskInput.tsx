@@ -1,4 +1,5 @@
-import { KeyboardEvent } from 'react';
+import { KeyboardEvent, useEffect, useState } from 'react';
+import { SuggestTasks } from '../../wailsjs/go/app/App';
 
 interface TaskInputProps {
   taskName: string;
@@ -9,6 +10,18 @@ interface TaskInputProps {
 }
 
 function TaskInput({ taskName, onTaskNameChange, onStart, onStop, isRunning }: TaskInputProps) {
+  const [suggestions, setSuggestions] = useState<string[]>([]);
+
+  useEffect(() => {
+    if (isRunning || !taskName.trim()) {
+      setSuggestions([]);
+      return;
+    }
+    SuggestTasks(taskName, 8)
+      .then(setSuggestions)
+      .catch((error) => console.error('Failed to load task suggestions:', error));
+  }, [taskName, isRunning]);
+
   const handleKeyPress = (e: KeyboardEvent<HTMLInputElement>) => {
     if (e.key === 'Enter' && !isRunning) {
       onStart();
@@ -25,7 +38,13 @@ function TaskInput({ taskName, onTaskNameChange, onStart, onStop, isRunning }: T
         onChange={(e) => onTaskNameChange(e.target.value)}
         onKeyPress={handleKeyPress}
         disabled={isRunning}
+        list="task-suggestions"
       />
+      <datalist id="task-suggestions">
+        {suggestions.map((name) => (
+          <option key={name} value={name} />
+        ))}
+      </datalist>
       <div className="button-group">
         <button
           className="btn btn-start"
@@ -4,15 +4,17 @@ interface TimeSlot {
   start_time: string;
   end_time?: string;
   duration_seconds: number;
+  kind?: string;
 }
 
 interface TimeSlotListProps {
   slots: TimeSlot[];
   onEdit?: (slot: TimeSlot) => void;
   onDelete?: (id: number) => void;
+  onStartAgain?: (id: number) => void;
 }
 
-function TimeSlotList({ slots, onEdit, onDelete }: TimeSlotListProps) {
+function TimeSlotList({ slots, onEdit, onDelete, onStartAgain }: TimeSlotListProps) {
   const formatTime = (timeString: string): string => {
     const date = new Date(timeString);
     return date.toLocaleTimeString('en-US', { hour: '2-digit', minute: '2-digit' });
@@ -37,7 +39,7 @@ function TimeSlotList({ slots, onEdit, onDelete }: TimeSlotListProps) {
       <h3>Time Slots</h3>
       <ul className="slot-list">
         {slots.map((slot) => (
-          <li key={slot.id} className="slot-item">
+          <li key={slot.id} className={slot.kind === 'break' ? 'slot-item slot-break' : 'slot-item'}>
             <div className="slot-info">
               <div className="slot-task">{slot.task_name}</div>
               <div className="slot-time">
@@ -46,8 +48,13 @@ function TimeSlotList({ slots, onEdit, onDelete }: TimeSlotListProps) {
               </div>
               <div className="slot-duration">{formatDuration(slot.duration_seconds)}</div>
             </div>
-            {(onEdit || onDelete) && (
+            {(onEdit || onDelete || onStartAgain) && (
               <div className="slot-actions">
+                {onStartAgain && (
+                  <button className="btn-start-again" onClick={() => onStartAgain(slot.id)}>
+                    Start again
+                  </button>
+                )}
                 {onEdit && (
                   <button className="btn-edit" onClick={() => onEdit(slot)}>
                     Edit
@@ -1,26 +1,37 @@
 import { useState, useEffect } from 'react';
-import { StartTimer, StopTimer, GetActiveTimeSlot, IsTimerRunning, GetElapsedTime } from '../../wailsjs/go/app/App';
+import { StartTimer, StopTimer, StopTimerConfirmed, GetActiveTimeSlot, IsTimerRunning, IsTimerPaused, GetElapsedTime, PauseTimer, ResumeTimer, StartBreak, AppendActiveNote, ReopenLastStopped, PredictNextTask, UndoStart, GetSettings, EnterFocusMode } from '../../wailsjs/go/app/App';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import TaskInput from './TaskInput';
+import { errorCode, errorMessage } from '../errors';
 
 function Timer() {
   const [taskName, setTaskName] = useState('');
   const [isRunning, setIsRunning] = useState(false);
   const [elapsedSeconds, setElapsedSeconds] = useState(0);
   const [currentTask, setCurrentTask] = useState<string>('');
+  const [isPaused, setIsPaused] = useState(false);
+  const [note, setNote] = useState('');
+  const [justStopped, setJustStopped] = useState(false);
+  const [justStarted, setJustStarted] = useState(false);
 
   useEffect(() => {
     // Check if timer is already running on mount
     checkTimerStatus();
 
-    // Update elapsed time every second
-    const interval = setInterval(() => {
-      if (isRunning) {
-        updateElapsedTime();
-      }
-    }, 1000);
-
-    return () => clearInterval(interval);
-  }, [isRunning]);
+    // The backend emits the elapsed time while the timer runs
+    const offTick = EventsOn('timer:tick', (seconds: number) => {
+      setElapsedSeconds(seconds);
+    });
+    // Timers started elsewhere (e.g. a reopened slot) refresh the view
+    const offStarted = EventsOn('timer:started', () => {
+      setJustStopped(false);
+      checkTimerStatus();
+    });
+    return () => {
+      offTick();
+      offStarted();
+    };
+  }, []);
 
   const checkTimerStatus = async () => {
     try {
@@ -28,11 +39,18 @@ function Timer() {
       setIsRunning(running);
       
       if (running) {
+        setIsPaused(await IsTimerPaused());
         const activeSlot = await GetActiveTimeSlot();
         if (activeSlot) {
           setCurrentTask(activeSlot.task_name);
           updateElapsedTime();
         }
+      } else {
+        // Prefill the task usually worked on at this time of day
+        const prediction = await PredictNextTask();
+        if (prediction.task_name) {
+          setTaskName((current) => current || prediction.task_name);
+        }
       }
     } catch (error) {
       console.error('Failed to check timer status:', error);
@@ -58,26 +76,113 @@ function Timer() {
       await StartTimer(taskName.trim());
       setCurrentTask(taskName.trim());
       setIsRunning(true);
+      setIsPaused(false);
       setElapsedSeconds(0);
       setTaskName('');
+
+      // Starting the wrong task can be taken back for a few seconds
+      const undoSeconds = (await GetSettings()).undo_start_seconds;
+      if (undoSeconds > 0) {
+        setJustStarted(true);
+        setTimeout(() => setJustStarted(false), undoSeconds * 1000);
+      }
     } catch (error) {
       console.error('Failed to start timer:', error);
-      alert('Failed to start timer');
+      if (errorCode(error) === 'task_name_too_long') {
+        alert(errorMessage(error));
+      } else {
+        alert('Failed to start timer');
+      }
     }
   };
 
   const handleStop = async () => {
     try {
-      await StopTimer();
+      try {
+        await StopTimer();
+      } catch (error) {
+        // Long sessions are only stopped after the user confirms
+        if (errorCode(error) !== 'confirmation_required') {
+          throw error;
+        }
+        if (!confirm(`Stop this ${formatTime(elapsedSeconds)} session?`)) {
+          return;
+        }
+        await StopTimerConfirmed();
+      }
       setIsRunning(false);
+      setIsPaused(false);
       setElapsedSeconds(0);
       setCurrentTask('');
+      setJustStopped(true);
     } catch (error) {
       console.error('Failed to stop timer:', error);
       alert('Failed to stop timer');
     }
   };
 
+  const handleReopen = async () => {
+    try {
+      await ReopenLastStopped();
+    } catch (error) {
+      console.error('Failed to reopen time slot:', error);
+      alert(errorMessage(error));
+    }
+    setJustStopped(false);
+  };
+
+  const handleUndoStart = async () => {
+    try {
+      await UndoStart();
+    } catch (error) {
+      console.error('Failed to undo start:', error);
+      alert(errorMessage(error));
+    }
+    setJustStarted(false);
+    checkTimerStatus();
+  };
+
+  const handleBreak = async () => {
+    try {
+      const slot = await StartBreak();
+      setCurrentTask(slot.task_name);
+      setIsRunning(true);
+      setIsPaused(false);
+      setElapsedSeconds(0);
+    } catch (error) {
+      console.error('Failed to start break:', error);
+      alert('Failed to start break');
+    }
+  };
+
+  const handleAddNote = async () => {
+    if (!note.trim()) {
+      return;
+    }
+    try {
+      await AppendActiveNote(note);
+      setNote('');
+    } catch (error) {
+      console.error('Failed to add note:', error);
+      alert('Failed to add note');
+    }
+  };
+
+  const handlePauseResume = async () => {
+    try {
+      if (isPaused) {
+        await ResumeTimer();
+      } else {
+        await PauseTimer();
+      }
+      setIsPaused(!isPaused);
+      updateElapsedTime();
+    } catch (error) {
+      console.error('Failed to pause or resume timer:', error);
+      alert('Failed to pause or resume timer');
+    }
+  };
+
   const formatTime = (seconds: number): string => {
     const hours = Math.floor(seconds / 3600);
     const minutes = Math.floor((seconds % 3600) / 60);
@@ -89,6 +194,15 @@ function Timer() {
     return `${minutes.toString().padStart(2, '0')}:${secs.toString().padStart(2, '0')}`;
   };
 
+  const handleFocus = async () => {
+    try {
+      await EnterFocusMode();
+    } catch (error) {
+      console.error('Failed to enter focus mode:', error);
+      alert(errorMessage(error));
+    }
+  };
+
   return (
     <div className="timer-container">
       <h2>Time Tracker</h2>
@@ -98,6 +212,21 @@ function Timer() {
           <p>Current task: <strong>{currentTask}</strong></p>
           <div className="elapsed-time">
             {formatTime(elapsedSeconds)}
+            {isPaused && ' (paused)'}
+          </div>
+          <button onClick={handlePauseResume}>
+            {isPaused ? 'Resume' : 'Pause'}
+          </button>
+          <button onClick={handleFocus}>Focus</button>
+          <div className="session-note">
+            <input
+              type="text"
+              placeholder="Quick note"
+              value={note}
+              onChange={(e) => setNote(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && handleAddNote()}
+            />
+            <button onClick={handleAddNote}>Add note</button>
           </div>
         </div>
       )}
@@ -109,6 +238,22 @@ function Timer() {
         onStop={handleStop}
         isRunning={isRunning}
       />
+
+      {isRunning && justStarted && (
+        <button onClick={handleUndoStart}>
+          Undo start
+        </button>
+      )}
+
+      {!isRunning && justStopped && (
+        <button onClick={handleReopen}>
+          Undo stop
+        </button>
+      )}
+
+      <button className="btn-break" onClick={handleBreak}>
+        Take a break
+      </button>
     </div>
   );
 }
@@ -0,0 +1,19 @@
+// Bound Go methods reject with a BackendError ({ code, message }), see internal/app/errors.go
+export interface BackendError {
+  code: string;
+  message: string;
+}
+
+function isBackendError(error: unknown): error is BackendError {
+  return typeof error === 'object' && error !== null && 'code' in error && 'message' in error;
+}
+
+// errorCode returns the machine-readable code of an error, or 'unknown'
+export function errorCode(error: unknown): string {
+  return isBackendError(error) ? error.code : 'unknown';
+}
+
+// errorMessage returns the human-readable message of an error
+export function errorMessage(error: unknown): string {
+  return isBackendError(error) ? error.message : String(error);
+}
@@ -1,23 +1,248 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {app} from '../models';
 import {models} from '../models';
 
+export function AddInterruptionMarker(arg1:string):Promise<void>;
+
+export function AdjustActiveStart(arg1:string):Promise<void>;
+
+export function AppendActiveNote(arg1:string):Promise<void>;
+
+export function ApplyTimelineEdits(arg1:Array<app.TimelineEdit>):Promise<void>;
+
+export function ArchiveBefore(arg1:string,arg2:string):Promise<number>;
+
+export function CanUndoStart():Promise<boolean>;
+
 export function Close():Promise<void>;
 
+export function ConfirmStillWorking():Promise<void>;
+
+export function CreateRecurringTask(arg1:app.RecurringTask):Promise<app.RecurringTask>;
+
+export function DeleteRecurringTask(arg1:number):Promise<void>;
+
 export function DeleteTimeSlot(arg1:number):Promise<void>;
 
+export function DuplicateTimeSlot(arg1:number,arg2:string):Promise<models.TimeSlot>;
+
+export function EnableEncryption(arg1:string):Promise<void>;
+
+export function EnterFocusMode():Promise<void>;
+
+export function ExitFocusMode():Promise<void>;
+
+export function ExportCSV(arg1:string,arg2:string,arg3:boolean):Promise<string>;
+
+export function ExportChartPNG(arg1:string,arg2:string):Promise<Array<number>>;
+
+export function ExportDailyMarkdown(arg1:string):Promise<string>;
+
+export function ExportDatabaseFile(arg1:string):Promise<void>;
+
+export function ExportHTMLReport(arg1:string,arg2:string):Promise<string>;
+
+export function ExportIssueTimeLog(arg1:string,arg2:string):Promise<string>;
+
+export function ExportJSON(arg1:string,arg2:string,arg3:boolean):Promise<string>;
+
+export function FindSimilarTaskNames(arg1:number):Promise<Array<any>>;
+
+export function GetActiveTaskName():Promise<string>;
+
 export function GetActiveTimeSlot():Promise<models.TimeSlot>;
 
+export function GetAnomalousActiveSlot(arg1:number):Promise<models.TimeSlot>;
+
+export function GetAnomalousSlots(arg1:string,arg2:string,arg3:number):Promise<Array<models.TimeSlot>>;
+
+export function GetCurrentContext():Promise<string>;
+
+export function GetDatabaseInfo():Promise<app.DBInfo>;
+
+export function GetEarningsReport(arg1:string,arg2:string):Promise<app.EarningsReport>;
+
 export function GetElapsedTime():Promise<number>;
 
-export function GetTaskStatistics(arg1:string):Promise<Record<string, number>>;
+export function GetEstimateAccuracy(arg1:string,arg2:string,arg3:number,arg4:string):Promise<Array<app.EstimateAccuracy>>;
+
+export function GetFocusScore(arg1:string):Promise<number>;
+
+export function GetGoalStopTime(arg1:string):Promise<app.GoalStop>;
+
+export function GetGroupedSlotsByDate(arg1:string,arg2:number,arg3:number,arg4:string):Promise<Array<app.TaskGroup>>;
+
+export function GetIdlePeriod():Promise<app.IdlePeriod>;
+
+export function GetInterruptions(arg1:number):Promise<Array<app.Interruption>>;
+
+export function GetLifetimeStats(arg1:number,arg2:string):Promise<app.LifetimeStats>;
+
+export function GetMonthlyReport(arg1:number,arg2:number,arg3:number,arg4:string):Promise<app.MonthlyReport>;
+
+export function GetNetDailyTotal(arg1:string,arg2:number,arg3:string):Promise<number>;
+
+export function GetPeriodComparison(arg1:string,arg2:string,arg3:number,arg4:string):Promise<app.Comparison>;
+
+export function GetPlannedFinishTime():Promise<app.PlannedFinish>;
+
+export function GetPomodoroStats(arg1:string,arg2:number,arg3:string):Promise<app.PomodoroStats>;
+
+export function GetRecoveredSlot():Promise<app.RecoveredSlot>;
+
+export function GetSecondsInLast(arg1:number,arg2:number,arg3:string):Promise<number>;
+
+export function GetSessionBreakdown(arg1:number):Promise<app.SessionBreakdown>;
+
+export function GetSettings():Promise<app.Settings>;
+
+export function GetStatisticsByContext(arg1:string,arg2:string,arg3:number,arg4:string):Promise<Record<string, number>>;
+
+export function GetStatisticsForWindow(arg1:string,arg2:string,arg3:number,arg4:string):Promise<Record<string, number>>;
+
+export function GetTaskColors():Promise<Record<string, string>>;
+
+export function GetTaskProjects():Promise<Record<string, string>>;
+
+export function GetTaskRates():Promise<Record<string, app.TaskRate>>;
+
+export function GetTaskStatistics(arg1:string,arg2:number,arg3:string):Promise<Record<string, number>>;
+
+export function GetTaskTrend(arg1:string,arg2:string,arg3:string,arg4:number,arg5:string):Promise<Record<string, number>>;
+
+export function GetTasksOftenLeftRunning(arg1:string,arg2:string):Promise<Array<app.LeftRunningTask>>;
+
+export function GetTimeByRef(arg1:string,arg2:string,arg3:string,arg4:number,arg5:string):Promise<Record<string, number>>;
+
+export function GetTimeSinceLastActivity():Promise<number>;
 
 export function GetTimeSlotsByDate(arg1:string):Promise<Array<models.TimeSlot>>;
 
+export function GetTimelineByDate(arg1:string,arg2:number):Promise<Array<models.TimeSlot>>;
+
+export function GetTimerState():Promise<app.TimerState>;
+
+export function GetTopTask(arg1:string,arg2:string,arg3:number,arg4:string):Promise<app.TopTask>;
+
+export function GetTrackedDates(arg1:string,arg2:string):Promise<Array<string>>;
+
+export function GetTreemapData(arg1:string,arg2:string,arg3:number,arg4:string):Promise<app.TreemapNode>;
+
+export function GetTrend(arg1:string,arg2:string,arg3:string,arg4:string,arg5:number,arg6:string):Promise<Array<app.TrendPoint>>;
+
+export function GetUntrackedGaps(arg1:string,arg2:number):Promise<Array<app.Gap>>;
+
+export function GetWeekdayTotals(arg1:string,arg2:string,arg3:number,arg4:string):Promise<any>;
+
+export function GetWeeklyPomodoroStats(arg1:string,arg2:number,arg3:string):Promise<app.PomodoroStats>;
+
+export function GetWindowState():Promise<app.WindowState>;
+
+export function GetWorkStatistics(arg1:string,arg2:string,arg3:number,arg4:string):Promise<app.WorkStatistics>;
+
+export function GetYearlyWeeklyTotals(arg1:number,arg2:number,arg3:string):Promise<Array<app.WeekTotal>>;
+
+export function ImportGeneric(arg1:string,arg2:string):Promise<number>;
+
+export function InitialWindowSize():Promise<app.WindowState>;
+
+export function IsDatabaseLocked():Promise<boolean>;
+
+export function IsFocusMode():Promise<boolean>;
+
+export function IsTimerPaused():Promise<boolean>;
+
 export function IsTimerRunning():Promise<boolean>;
 
+export function ListRecurringTasks():Promise<Array<app.RecurringTask>>;
+
+export function MergeWithPrevious():Promise<models.TimeSlot>;
+
+export function NotificationBackendStatus():Promise<app.NotificationBackendStatus>;
+
+export function PauseTimer():Promise<void>;
+
+export function PredictNextTask():Promise<app.TaskPrediction>;
+
+export function QueryArchive(arg1:string,arg2:string,arg3:string):Promise<Array<models.TimeSlot>>;
+
+export function QuickToggle():Promise<models.TimeSlot>;
+
+export function RecalculateDurations():Promise<number>;
+
+export function ReconcileWithCalendar(arg1:string,arg2:string):Promise<app.CalendarReconciliation>;
+
+export function RemoveTaskRate(arg1:string):Promise<void>;
+
+export function RenameActiveSlot(arg1:string):Promise<void>;
+
+export function ReopenLastStopped():Promise<models.TimeSlot>;
+
+export function ResolveIdlePeriod(arg1:string,arg2:string):Promise<models.TimeSlot>;
+
+export function ResolveRecoveredSlot(arg1:string,arg2:string):Promise<void>;
+
+export function ResumeTimer():Promise<void>;
+
+export function SaveWindowState(arg1:number,arg2:number,arg3:number,arg4:number):Promise<void>;
+
+export function SetActivityCheckEnabled(arg1:boolean):Promise<void>;
+
+export function SetCurrentContext(arg1:string):Promise<void>;
+
+export function SetDefaultTaskName(arg1:string):Promise<void>;
+
+export function SetExportTimeZone(arg1:string):Promise<void>;
+
+export function SetFocusOnStart(arg1:boolean):Promise<void>;
+
+export function SetMaxHistoryDays(arg1:number):Promise<void>;
+
+export function SetPlannedDuration(arg1:number):Promise<void>;
+
+export function SetSystrayEnabled(arg1:boolean):Promise<void>;
+
+export function SetTaskColor(arg1:string,arg2:string):Promise<void>;
+
+export function SetTaskProject(arg1:string,arg2:string):Promise<void>;
+
+export function SetTaskRate(arg1:string,arg2:number,arg3:string):Promise<void>;
+
+export function SetTickInterval(arg1:number):Promise<void>;
+
+export function SetTimeSlotRef(arg1:number,arg2:string):Promise<void>;
+
+export function StartBreak():Promise<models.TimeSlot>;
+
+export function StartFromSlot(arg1:number):Promise<models.TimeSlot>;
+
+export function StartPomodoro(arg1:string,arg2:number):Promise<models.TimeSlot>;
+
 export function StartTimer(arg1:string):Promise<models.TimeSlot>;
 
+export function StartTimerWithEstimate(arg1:string,arg2:number):Promise<models.TimeSlot>;
+
+export function StartTimerWithRef(arg1:string,arg2:string):Promise<models.TimeSlot>;
+
 export function StopTimer():Promise<models.TimeSlot>;
 
+export function StopTimerAndGetTodayStats(arg1:number,arg2:string):Promise<app.StopResult>;
+
+export function StopTimerAndGetTodayStatsConfirmed(arg1:number,arg2:string):Promise<app.StopResult>;
+
+export function StopTimerConfirmed():Promise<models.TimeSlot>;
+
+export function SuggestTasks(arg1:string,arg2:number):Promise<Array<string>>;
+
+export function UndoStart():Promise<models.TimeSlot>;
+
+export function UnlockDatabase(arg1:string):Promise<void>;
+
+export function UpdateRecurringTask(arg1:app.RecurringTask):Promise<void>;
+
+export function UpdateSettings(arg1:app.Settings):Promise<void>;
+
 export function UpdateTimeSlot(arg1:number,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function ValidateTimeSlotEdit(arg1:number,arg2:string,arg3:string):Promise<app.ValidationResult>;
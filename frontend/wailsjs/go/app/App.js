@@ -2,42 +2,490 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddInterruptionMarker(arg1) {
+  return window['go']['app']['App']['AddInterruptionMarker'](arg1);
+}
+
+export function AdjustActiveStart(arg1) {
+  return window['go']['app']['App']['AdjustActiveStart'](arg1);
+}
+
+export function AppendActiveNote(arg1) {
+  return window['go']['app']['App']['AppendActiveNote'](arg1);
+}
+
+export function ApplyTimelineEdits(arg1) {
+  return window['go']['app']['App']['ApplyTimelineEdits'](arg1);
+}
+
+export function ArchiveBefore(arg1, arg2) {
+  return window['go']['app']['App']['ArchiveBefore'](arg1, arg2);
+}
+
+export function CanUndoStart() {
+  return window['go']['app']['App']['CanUndoStart']();
+}
+
 export function Close() {
   return window['go']['app']['App']['Close']();
 }
 
+export function ConfirmStillWorking() {
+  return window['go']['app']['App']['ConfirmStillWorking']();
+}
+
+export function CreateRecurringTask(arg1) {
+  return window['go']['app']['App']['CreateRecurringTask'](arg1);
+}
+
+export function DeleteRecurringTask(arg1) {
+  return window['go']['app']['App']['DeleteRecurringTask'](arg1);
+}
+
 export function DeleteTimeSlot(arg1) {
   return window['go']['app']['App']['DeleteTimeSlot'](arg1);
 }
 
+export function DuplicateTimeSlot(arg1, arg2) {
+  return window['go']['app']['App']['DuplicateTimeSlot'](arg1, arg2);
+}
+
+export function EnableEncryption(arg1) {
+  return window['go']['app']['App']['EnableEncryption'](arg1);
+}
+
+export function EnterFocusMode() {
+  return window['go']['app']['App']['EnterFocusMode']();
+}
+
+export function ExitFocusMode() {
+  return window['go']['app']['App']['ExitFocusMode']();
+}
+
+export function ExportCSV(arg1, arg2, arg3) {
+  return window['go']['app']['App']['ExportCSV'](arg1, arg2, arg3);
+}
+
+export function ExportChartPNG(arg1, arg2) {
+  return window['go']['app']['App']['ExportChartPNG'](arg1, arg2);
+}
+
+export function ExportDailyMarkdown(arg1) {
+  return window['go']['app']['App']['ExportDailyMarkdown'](arg1);
+}
+
+export function ExportDatabaseFile(arg1) {
+  return window['go']['app']['App']['ExportDatabaseFile'](arg1);
+}
+
+export function ExportHTMLReport(arg1, arg2) {
+  return window['go']['app']['App']['ExportHTMLReport'](arg1, arg2);
+}
+
+export function ExportIssueTimeLog(arg1, arg2) {
+  return window['go']['app']['App']['ExportIssueTimeLog'](arg1, arg2);
+}
+
+export function ExportJSON(arg1, arg2, arg3) {
+  return window['go']['app']['App']['ExportJSON'](arg1, arg2, arg3);
+}
+
+export function FindSimilarTaskNames(arg1) {
+  return window['go']['app']['App']['FindSimilarTaskNames'](arg1);
+}
+
+export function GetActiveTaskName() {
+  return window['go']['app']['App']['GetActiveTaskName']();
+}
+
 export function GetActiveTimeSlot() {
   return window['go']['app']['App']['GetActiveTimeSlot']();
 }
 
+export function GetAnomalousActiveSlot(arg1) {
+  return window['go']['app']['App']['GetAnomalousActiveSlot'](arg1);
+}
+
+export function GetAnomalousSlots(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetAnomalousSlots'](arg1, arg2, arg3);
+}
+
+export function GetCurrentContext() {
+  return window['go']['app']['App']['GetCurrentContext']();
+}
+
+export function GetDatabaseInfo() {
+  return window['go']['app']['App']['GetDatabaseInfo']();
+}
+
+export function GetEarningsReport(arg1, arg2) {
+  return window['go']['app']['App']['GetEarningsReport'](arg1, arg2);
+}
+
 export function GetElapsedTime() {
   return window['go']['app']['App']['GetElapsedTime']();
 }
 
-export function GetTaskStatistics(arg1) {
-  return window['go']['app']['App']['GetTaskStatistics'](arg1);
+export function GetEstimateAccuracy(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetEstimateAccuracy'](arg1, arg2, arg3, arg4);
+}
+
+export function GetFocusScore(arg1) {
+  return window['go']['app']['App']['GetFocusScore'](arg1);
+}
+
+export function GetGoalStopTime(arg1) {
+  return window['go']['app']['App']['GetGoalStopTime'](arg1);
+}
+
+export function GetGroupedSlotsByDate(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetGroupedSlotsByDate'](arg1, arg2, arg3, arg4);
+}
+
+export function GetIdlePeriod() {
+  return window['go']['app']['App']['GetIdlePeriod']();
+}
+
+export function GetInterruptions(arg1) {
+  return window['go']['app']['App']['GetInterruptions'](arg1);
+}
+
+export function GetLifetimeStats(arg1, arg2) {
+  return window['go']['app']['App']['GetLifetimeStats'](arg1, arg2);
+}
+
+export function GetMonthlyReport(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetMonthlyReport'](arg1, arg2, arg3, arg4);
+}
+
+export function GetNetDailyTotal(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetNetDailyTotal'](arg1, arg2, arg3);
+}
+
+export function GetPeriodComparison(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetPeriodComparison'](arg1, arg2, arg3, arg4);
+}
+
+export function GetPlannedFinishTime() {
+  return window['go']['app']['App']['GetPlannedFinishTime']();
+}
+
+export function GetPomodoroStats(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetPomodoroStats'](arg1, arg2, arg3);
+}
+
+export function GetRecoveredSlot() {
+  return window['go']['app']['App']['GetRecoveredSlot']();
+}
+
+export function GetSecondsInLast(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetSecondsInLast'](arg1, arg2, arg3);
+}
+
+export function GetSessionBreakdown(arg1) {
+  return window['go']['app']['App']['GetSessionBreakdown'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['app']['App']['GetSettings']();
+}
+
+export function GetStatisticsByContext(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetStatisticsByContext'](arg1, arg2, arg3, arg4);
+}
+
+export function GetStatisticsForWindow(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetStatisticsForWindow'](arg1, arg2, arg3, arg4);
+}
+
+export function GetTaskColors() {
+  return window['go']['app']['App']['GetTaskColors']();
+}
+
+export function GetTaskProjects() {
+  return window['go']['app']['App']['GetTaskProjects']();
+}
+
+export function GetTaskRates() {
+  return window['go']['app']['App']['GetTaskRates']();
+}
+
+export function GetTaskStatistics(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetTaskStatistics'](arg1, arg2, arg3);
+}
+
+export function GetTaskTrend(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['GetTaskTrend'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GetTasksOftenLeftRunning(arg1, arg2) {
+  return window['go']['app']['App']['GetTasksOftenLeftRunning'](arg1, arg2);
+}
+
+export function GetTimeByRef(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['app']['App']['GetTimeByRef'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function GetTimeSinceLastActivity() {
+  return window['go']['app']['App']['GetTimeSinceLastActivity']();
 }
 
 export function GetTimeSlotsByDate(arg1) {
   return window['go']['app']['App']['GetTimeSlotsByDate'](arg1);
 }
 
+export function GetTimelineByDate(arg1, arg2) {
+  return window['go']['app']['App']['GetTimelineByDate'](arg1, arg2);
+}
+
+export function GetTimerState() {
+  return window['go']['app']['App']['GetTimerState']();
+}
+
+export function GetTopTask(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetTopTask'](arg1, arg2, arg3, arg4);
+}
+
+export function GetTrackedDates(arg1, arg2) {
+  return window['go']['app']['App']['GetTrackedDates'](arg1, arg2);
+}
+
+export function GetTreemapData(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetTreemapData'](arg1, arg2, arg3, arg4);
+}
+
+export function GetTrend(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['app']['App']['GetTrend'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function GetUntrackedGaps(arg1, arg2) {
+  return window['go']['app']['App']['GetUntrackedGaps'](arg1, arg2);
+}
+
+export function GetWeekdayTotals(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetWeekdayTotals'](arg1, arg2, arg3, arg4);
+}
+
+export function GetWeeklyPomodoroStats(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetWeeklyPomodoroStats'](arg1, arg2, arg3);
+}
+
+export function GetWindowState() {
+  return window['go']['app']['App']['GetWindowState']();
+}
+
+export function GetWorkStatistics(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['GetWorkStatistics'](arg1, arg2, arg3, arg4);
+}
+
+export function GetYearlyWeeklyTotals(arg1, arg2, arg3) {
+  return window['go']['app']['App']['GetYearlyWeeklyTotals'](arg1, arg2, arg3);
+}
+
+export function ImportGeneric(arg1, arg2) {
+  return window['go']['app']['App']['ImportGeneric'](arg1, arg2);
+}
+
+export function InitialWindowSize() {
+  return window['go']['app']['App']['InitialWindowSize']();
+}
+
+export function IsDatabaseLocked() {
+  return window['go']['app']['App']['IsDatabaseLocked']();
+}
+
+export function IsFocusMode() {
+  return window['go']['app']['App']['IsFocusMode']();
+}
+
+export function IsTimerPaused() {
+  return window['go']['app']['App']['IsTimerPaused']();
+}
+
 export function IsTimerRunning() {
   return window['go']['app']['App']['IsTimerRunning']();
 }
 
+export function ListRecurringTasks() {
+  return window['go']['app']['App']['ListRecurringTasks']();
+}
+
+export function MergeWithPrevious() {
+  return window['go']['app']['App']['MergeWithPrevious']();
+}
+
+export function NotificationBackendStatus() {
+  return window['go']['app']['App']['NotificationBackendStatus']();
+}
+
+export function PauseTimer() {
+  return window['go']['app']['App']['PauseTimer']();
+}
+
+export function PredictNextTask() {
+  return window['go']['app']['App']['PredictNextTask']();
+}
+
+export function QueryArchive(arg1, arg2, arg3) {
+  return window['go']['app']['App']['QueryArchive'](arg1, arg2, arg3);
+}
+
+export function QuickToggle() {
+  return window['go']['app']['App']['QuickToggle']();
+}
+
+export function RecalculateDurations() {
+  return window['go']['app']['App']['RecalculateDurations']();
+}
+
+export function ReconcileWithCalendar(arg1, arg2) {
+  return window['go']['app']['App']['ReconcileWithCalendar'](arg1, arg2);
+}
+
+export function RemoveTaskRate(arg1) {
+  return window['go']['app']['App']['RemoveTaskRate'](arg1);
+}
+
+export function RenameActiveSlot(arg1) {
+  return window['go']['app']['App']['RenameActiveSlot'](arg1);
+}
+
+export function ReopenLastStopped() {
+  return window['go']['app']['App']['ReopenLastStopped']();
+}
+
+export function ResolveIdlePeriod(arg1, arg2) {
+  return window['go']['app']['App']['ResolveIdlePeriod'](arg1, arg2);
+}
+
+export function ResolveRecoveredSlot(arg1, arg2) {
+  return window['go']['app']['App']['ResolveRecoveredSlot'](arg1, arg2);
+}
+
+export function ResumeTimer() {
+  return window['go']['app']['App']['ResumeTimer']();
+}
+
+export function SaveWindowState(arg1, arg2, arg3, arg4) {
+  return window['go']['app']['App']['SaveWindowState'](arg1, arg2, arg3, arg4);
+}
+
+export function SetActivityCheckEnabled(arg1) {
+  return window['go']['app']['App']['SetActivityCheckEnabled'](arg1);
+}
+
+export function SetCurrentContext(arg1) {
+  return window['go']['app']['App']['SetCurrentContext'](arg1);
+}
+
+export function SetDefaultTaskName(arg1) {
+  return window['go']['app']['App']['SetDefaultTaskName'](arg1);
+}
+
+export function SetExportTimeZone(arg1) {
+  return window['go']['app']['App']['SetExportTimeZone'](arg1);
+}
+
+export function SetFocusOnStart(arg1) {
+  return window['go']['app']['App']['SetFocusOnStart'](arg1);
+}
+
+export function SetMaxHistoryDays(arg1) {
+  return window['go']['app']['App']['SetMaxHistoryDays'](arg1);
+}
+
+export function SetPlannedDuration(arg1) {
+  return window['go']['app']['App']['SetPlannedDuration'](arg1);
+}
+
+export function SetSystrayEnabled(arg1) {
+  return window['go']['app']['App']['SetSystrayEnabled'](arg1);
+}
+
+export function SetTaskColor(arg1, arg2) {
+  return window['go']['app']['App']['SetTaskColor'](arg1, arg2);
+}
+
+export function SetTaskProject(arg1, arg2) {
+  return window['go']['app']['App']['SetTaskProject'](arg1, arg2);
+}
+
+export function SetTaskRate(arg1, arg2, arg3) {
+  return window['go']['app']['App']['SetTaskRate'](arg1, arg2, arg3);
+}
+
+export function SetTickInterval(arg1) {
+  return window['go']['app']['App']['SetTickInterval'](arg1);
+}
+
+export function SetTimeSlotRef(arg1, arg2) {
+  return window['go']['app']['App']['SetTimeSlotRef'](arg1, arg2);
+}
+
+export function StartBreak() {
+  return window['go']['app']['App']['StartBreak']();
+}
+
+export function StartFromSlot(arg1) {
+  return window['go']['app']['App']['StartFromSlot'](arg1);
+}
+
+export function StartPomodoro(arg1, arg2) {
+  return window['go']['app']['App']['StartPomodoro'](arg1, arg2);
+}
+
 export function StartTimer(arg1) {
   return window['go']['app']['App']['StartTimer'](arg1);
 }
 
+export function StartTimerWithEstimate(arg1, arg2) {
+  return window['go']['app']['App']['StartTimerWithEstimate'](arg1, arg2);
+}
+
+export function StartTimerWithRef(arg1, arg2) {
+  return window['go']['app']['App']['StartTimerWithRef'](arg1, arg2);
+}
+
 export function StopTimer() {
   return window['go']['app']['App']['StopTimer']();
 }
 
+export function StopTimerAndGetTodayStats(arg1, arg2) {
+  return window['go']['app']['App']['StopTimerAndGetTodayStats'](arg1, arg2);
+}
+
+export function StopTimerAndGetTodayStatsConfirmed(arg1, arg2) {
+  return window['go']['app']['App']['StopTimerAndGetTodayStatsConfirmed'](arg1, arg2);
+}
+
+export function StopTimerConfirmed() {
+  return window['go']['app']['App']['StopTimerConfirmed']();
+}
+
+export function SuggestTasks(arg1, arg2) {
+  return window['go']['app']['App']['SuggestTasks'](arg1, arg2);
+}
+
+export function UndoStart() {
+  return window['go']['app']['App']['UndoStart']();
+}
+
+export function UnlockDatabase(arg1) {
+  return window['go']['app']['App']['UnlockDatabase'](arg1);
+}
+
+export function UpdateRecurringTask(arg1) {
+  return window['go']['app']['App']['UpdateRecurringTask'](arg1);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['app']['App']['UpdateSettings'](arg1);
+}
+
 export function UpdateTimeSlot(arg1, arg2, arg3, arg4) {
   return window['go']['app']['App']['UpdateTimeSlot'](arg1, arg2, arg3, arg4);
 }
+
+export function ValidateTimeSlotEdit(arg1, arg2, arg3) {
+  return window['go']['app']['App']['ValidateTimeSlotEdit'](arg1, arg2, arg3);
+}
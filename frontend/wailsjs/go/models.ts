@@ -1,3 +1,1117 @@
+export namespace app {
+	
+	export class CalendarEvent {
+	    summary: string;
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CalendarEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.summary = source["summary"];
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CalendarMatch {
+	    event: CalendarEvent;
+	    slot_ids: number[];
+	    overlap_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CalendarMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.event = this.convertValues(source["event"], CalendarEvent);
+	        this.slot_ids = source["slot_ids"];
+	        this.overlap_seconds = source["overlap_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CalendarReconciliation {
+	    date: string;
+	    matched: CalendarMatch[];
+	    unlogged_events: CalendarEvent[];
+	    unplanned_slots: models.TimeSlot[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CalendarReconciliation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.matched = this.convertValues(source["matched"], CalendarMatch);
+	        this.unlogged_events = this.convertValues(source["unlogged_events"], CalendarEvent);
+	        this.unplanned_slots = this.convertValues(source["unplanned_slots"], models.TimeSlot);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskDelta {
+	    task_name: string;
+	    current_seconds: number;
+	    previous_seconds: number;
+	    delta_seconds: number;
+	    percent_change: number;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskDelta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.current_seconds = source["current_seconds"];
+	        this.previous_seconds = source["previous_seconds"];
+	        this.delta_seconds = source["delta_seconds"];
+	        this.percent_change = source["percent_change"];
+	        this.status = source["status"];
+	    }
+	}
+	export class Comparison {
+	    period: string;
+	    // Go type: time
+	    current_start: any;
+	    // Go type: time
+	    previous_start: any;
+	    current_total: number;
+	    previous_total: number;
+	    delta_seconds: number;
+	    percent_change: number;
+	    tasks: TaskDelta[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Comparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.period = source["period"];
+	        this.current_start = this.convertValues(source["current_start"], null);
+	        this.previous_start = this.convertValues(source["previous_start"], null);
+	        this.current_total = source["current_total"];
+	        this.previous_total = source["previous_total"];
+	        this.delta_seconds = source["delta_seconds"];
+	        this.percent_change = source["percent_change"];
+	        this.tasks = this.convertValues(source["tasks"], TaskDelta);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CurrencyTotal {
+	    currency: string;
+	    amount: number;
+	    formatted: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrencyTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.currency = source["currency"];
+	        this.amount = source["amount"];
+	        this.formatted = source["formatted"];
+	    }
+	}
+	export class DBInfo {
+	    path: string;
+	    size_bytes: number;
+	    row_count: number;
+	    has_active_slot: boolean;
+	    first_date: string;
+	    last_date: string;
+	    fallback: boolean;
+	    temporary: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DBInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size_bytes = source["size_bytes"];
+	        this.row_count = source["row_count"];
+	        this.has_active_slot = source["has_active_slot"];
+	        this.first_date = source["first_date"];
+	        this.last_date = source["last_date"];
+	        this.fallback = source["fallback"];
+	        this.temporary = source["temporary"];
+	    }
+	}
+	export class DayTotal {
+	    date: string;
+	    total_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DayTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.total_seconds = source["total_seconds"];
+	    }
+	}
+	export class TaskTotal {
+	    task_name: string;
+	    total_seconds: number;
+	    sessions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.total_seconds = source["total_seconds"];
+	        this.sessions = source["sessions"];
+	    }
+	}
+	export class TaskEarnings {
+	    task_name: string;
+	    seconds: number;
+	    rate_per_hour: number;
+	    currency: string;
+	    amount: number;
+	    formatted: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskEarnings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.seconds = source["seconds"];
+	        this.rate_per_hour = source["rate_per_hour"];
+	        this.currency = source["currency"];
+	        this.amount = source["amount"];
+	        this.formatted = source["formatted"];
+	    }
+	}
+	export class EarningsReport {
+	    tasks: TaskEarnings[];
+	    totals: CurrencyTotal[];
+	    no_rate: TaskTotal[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EarningsReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = this.convertValues(source["tasks"], TaskEarnings);
+	        this.totals = this.convertValues(source["totals"], CurrencyTotal);
+	        this.no_rate = this.convertValues(source["no_rate"], TaskTotal);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EstimateAccuracy {
+	    task_name: string;
+	    sessions: number;
+	    estimated_seconds: number;
+	    actual_seconds: number;
+	    variance_seconds: number;
+	    variance_percent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EstimateAccuracy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.sessions = source["sessions"];
+	        this.estimated_seconds = source["estimated_seconds"];
+	        this.actual_seconds = source["actual_seconds"];
+	        this.variance_seconds = source["variance_seconds"];
+	        this.variance_percent = source["variance_percent"];
+	    }
+	}
+	export class Gap {
+	    // Go type: time
+	    start: any;
+	    // Go type: time
+	    end: any;
+	    duration_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Gap(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = this.convertValues(source["start"], null);
+	        this.end = this.convertValues(source["end"], null);
+	        this.duration_seconds = source["duration_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GoalStop {
+	    // Go type: time
+	    stop_time: any;
+	    goal_seconds: number;
+	    tracked_seconds: number;
+	    remaining_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GoalStop(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stop_time = this.convertValues(source["stop_time"], null);
+	        this.goal_seconds = source["goal_seconds"];
+	        this.tracked_seconds = source["tracked_seconds"];
+	        this.remaining_seconds = source["remaining_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IdlePeriod {
+	    slot_id: number;
+	    task_name: string;
+	    // Go type: time
+	    start_time: any;
+	    // Go type: time
+	    end_time: any;
+	    seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IdlePeriod(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slot_id = source["slot_id"];
+	        this.task_name = source["task_name"];
+	        this.start_time = this.convertValues(source["start_time"], null);
+	        this.end_time = this.convertValues(source["end_time"], null);
+	        this.seconds = source["seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Interruption {
+	    id: number;
+	    slot_id: number;
+	    // Go type: time
+	    time: any;
+	    note: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Interruption(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.slot_id = source["slot_id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.note = source["note"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LeftRunningTask {
+	    task_name: string;
+	    sessions: number;
+	    auto_stopped: number;
+	    long_sessions: number;
+	    flagged: number;
+	    flagged_percent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LeftRunningTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.sessions = source["sessions"];
+	        this.auto_stopped = source["auto_stopped"];
+	        this.long_sessions = source["long_sessions"];
+	        this.flagged = source["flagged"];
+	        this.flagged_percent = source["flagged_percent"];
+	    }
+	}
+	export class LifetimeStats {
+	    total_seconds: number;
+	    slot_count: number;
+	    tracking_since: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LifetimeStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.total_seconds = source["total_seconds"];
+	        this.slot_count = source["slot_count"];
+	        this.tracking_since = source["tracking_since"];
+	    }
+	}
+	export class MonthlyReport {
+	    year: number;
+	    month: number;
+	    tasks: TaskTotal[];
+	    days: DayTotal[];
+	    session_count: number;
+	    total_seconds: number;
+	    break_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonthlyReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.month = source["month"];
+	        this.tasks = this.convertValues(source["tasks"], TaskTotal);
+	        this.days = this.convertValues(source["days"], DayTotal);
+	        this.session_count = source["session_count"];
+	        this.total_seconds = source["total_seconds"];
+	        this.break_seconds = source["break_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotificationBackendStatus {
+	    available: boolean;
+	    backend: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationBackendStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.available = source["available"];
+	        this.backend = source["backend"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class PlannedFinish {
+	    // Go type: time
+	    finish_time: any;
+	    remaining_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlannedFinish(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.finish_time = this.convertValues(source["finish_time"], null);
+	        this.remaining_seconds = source["remaining_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PomodoroStats {
+	    start_date: string;
+	    end_date: string;
+	    completed: number;
+	    interrupted: number;
+	    focus_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PomodoroStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start_date = source["start_date"];
+	        this.end_date = source["end_date"];
+	        this.completed = source["completed"];
+	        this.interrupted = source["interrupted"];
+	        this.focus_seconds = source["focus_seconds"];
+	    }
+	}
+	export class RecoveredSlot {
+	    slot?: models.TimeSlot;
+	    elapsed_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecoveredSlot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slot = this.convertValues(source["slot"], models.TimeSlot);
+	        this.elapsed_seconds = source["elapsed_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RecurringTask {
+	    id: number;
+	    task_name: string;
+	    start: string;
+	    end: string;
+	    weekdays: number[];
+	    action: string;
+	    last_run: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RecurringTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.task_name = source["task_name"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.weekdays = source["weekdays"];
+	        this.action = source["action"];
+	        this.last_run = source["last_run"];
+	    }
+	}
+	export class WorkWindow {
+	    enabled: boolean;
+	    start: string;
+	    end: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkWindow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.start = source["start"];
+	        this.end = source["end"];
+	    }
+	}
+	export class Schedule {
+	    enabled: boolean;
+	    days: WorkWindow[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Schedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.days = this.convertValues(source["days"], WorkWindow);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionBreakdown {
+	    active_seconds: number;
+	    paused_seconds: number;
+	    wall_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionBreakdown(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active_seconds = source["active_seconds"];
+	        this.paused_seconds = source["paused_seconds"];
+	        this.wall_seconds = source["wall_seconds"];
+	    }
+	}
+	export class WindowState {
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WindowState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	    }
+	}
+	export class Settings {
+	    stop_timer_on_quit: boolean;
+	    tray_click_action: string;
+	    schedule: Schedule;
+	    still_working_interval_minutes: number;
+	    still_working_grace_minutes: number;
+	    auto_stop_unconfirmed: boolean;
+	    merge_gap_minutes: number;
+	    round_stop_to_minutes: number;
+	    notification_urgency: string;
+	    max_history_days: number;
+	    max_task_name_length: number;
+	    default_task_name: string;
+	    remember_window: boolean;
+	    window?: WindowState;
+	    disable_systray: boolean;
+	    reopen_window_minutes: number;
+	    idle_threshold_minutes: number;
+	    count_sleep_time: boolean;
+	    confirm_stop_after_minutes: number;
+	    undo_start_seconds: number;
+	    toggle_debounce_ms: number;
+	    daily_goal_minutes: number;
+	    tray_tooltip: string;
+	    tray_tooltip_idle: string;
+	    focus_on_start: boolean;
+	    focus_mute_notifications: boolean;
+	    current_context: string;
+	    network_contexts?: Record<string, string>;
+	    export_time_zone: string;
+	    activity_check_enabled: boolean;
+	    activity_check_minutes: number;
+	    activity_keywords?: Record<string, Array<string>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stop_timer_on_quit = source["stop_timer_on_quit"];
+	        this.tray_click_action = source["tray_click_action"];
+	        this.schedule = this.convertValues(source["schedule"], Schedule);
+	        this.still_working_interval_minutes = source["still_working_interval_minutes"];
+	        this.still_working_grace_minutes = source["still_working_grace_minutes"];
+	        this.auto_stop_unconfirmed = source["auto_stop_unconfirmed"];
+	        this.merge_gap_minutes = source["merge_gap_minutes"];
+	        this.round_stop_to_minutes = source["round_stop_to_minutes"];
+	        this.notification_urgency = source["notification_urgency"];
+	        this.max_history_days = source["max_history_days"];
+	        this.max_task_name_length = source["max_task_name_length"];
+	        this.default_task_name = source["default_task_name"];
+	        this.remember_window = source["remember_window"];
+	        this.window = this.convertValues(source["window"], WindowState);
+	        this.disable_systray = source["disable_systray"];
+	        this.reopen_window_minutes = source["reopen_window_minutes"];
+	        this.idle_threshold_minutes = source["idle_threshold_minutes"];
+	        this.count_sleep_time = source["count_sleep_time"];
+	        this.confirm_stop_after_minutes = source["confirm_stop_after_minutes"];
+	        this.undo_start_seconds = source["undo_start_seconds"];
+	        this.toggle_debounce_ms = source["toggle_debounce_ms"];
+	        this.daily_goal_minutes = source["daily_goal_minutes"];
+	        this.tray_tooltip = source["tray_tooltip"];
+	        this.tray_tooltip_idle = source["tray_tooltip_idle"];
+	        this.focus_on_start = source["focus_on_start"];
+	        this.focus_mute_notifications = source["focus_mute_notifications"];
+	        this.current_context = source["current_context"];
+	        this.network_contexts = source["network_contexts"];
+	        this.export_time_zone = source["export_time_zone"];
+	        this.activity_check_enabled = source["activity_check_enabled"];
+	        this.activity_check_minutes = source["activity_check_minutes"];
+	        this.activity_keywords = source["activity_keywords"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StopResult {
+	    slot?: models.TimeSlot;
+	    statistics: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new StopResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.slot = this.convertValues(source["slot"], models.TimeSlot);
+	        this.statistics = source["statistics"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class TaskGroup {
+	    task_name: string;
+	    slots: models.TimeSlot[];
+	    total_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.slots = this.convertValues(source["slots"], models.TimeSlot);
+	        this.total_seconds = source["total_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TaskPrediction {
+	    task_name: string;
+	    confidence: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskPrediction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.confidence = source["confidence"];
+	    }
+	}
+	
+	export class TimelineEdit {
+	    id: number;
+	    start_time: string;
+	    end_time: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimelineEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.start_time = source["start_time"];
+	        this.end_time = source["end_time"];
+	    }
+	}
+	export class TimerState {
+	    running: boolean;
+	    paused: boolean;
+	    slot?: models.TimeSlot;
+	    elapsed_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TimerState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.paused = source["paused"];
+	        this.slot = this.convertValues(source["slot"], models.TimeSlot);
+	        this.elapsed_seconds = source["elapsed_seconds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TopTask {
+	    task_name: string;
+	    total_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TopTask(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.task_name = source["task_name"];
+	        this.total_seconds = source["total_seconds"];
+	    }
+	}
+	export class TreemapNode {
+	    name: string;
+	    seconds: number;
+	    percent: number;
+	    children?: TreemapNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TreemapNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.seconds = source["seconds"];
+	        this.percent = source["percent"];
+	        this.children = this.convertValues(source["children"], TreemapNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrendPoint {
+	    bucket: string;
+	    seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrendPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.seconds = source["seconds"];
+	    }
+	}
+	export class ValidationResult {
+	    valid: boolean;
+	    errors: string[];
+	    warnings: string[];
+	    overlapping_ids: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ValidationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.valid = source["valid"];
+	        this.errors = source["errors"];
+	        this.warnings = source["warnings"];
+	        this.overlapping_ids = source["overlapping_ids"];
+	    }
+	}
+	export class WeekTotal {
+	    year: number;
+	    week: number;
+	    start_date: string;
+	    total_seconds: number;
+	    top_task: string;
+	    top_task_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WeekTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.week = source["week"];
+	        this.start_date = source["start_date"];
+	        this.total_seconds = source["total_seconds"];
+	        this.top_task = source["top_task"];
+	        this.top_task_seconds = source["top_task_seconds"];
+	    }
+	}
+	
+	export class WorkStatistics {
+	    tasks: Record<string, number>;
+	    work_seconds: number;
+	    break_seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkStatistics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tasks = source["tasks"];
+	        this.work_seconds = source["work_seconds"];
+	        this.break_seconds = source["break_seconds"];
+	    }
+	}
+
+}
+
 export namespace models {
 	
 	export class TimeSlot {
@@ -8,6 +1122,15 @@ export namespace models {
 	    // Go type: time
 	    end_time?: any;
 	    duration_seconds: number;
+	    paused_seconds: number;
+	    // Go type: time
+	    paused_at?: any;
+	    kind: string;
+	    notes: string;
+	    external_ref: string;
+	    context: string;
+	    estimate_seconds: number;
+	    auto_stopped: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new TimeSlot(source);
@@ -20,6 +1143,14 @@ export namespace models {
 	        this.start_time = this.convertValues(source["start_time"], null);
 	        this.end_time = this.convertValues(source["end_time"], null);
 	        this.duration_seconds = source["duration_seconds"];
+	        this.paused_seconds = source["paused_seconds"];
+	        this.paused_at = this.convertValues(source["paused_at"], null);
+	        this.kind = source["kind"];
+	        this.notes = source["notes"];
+	        this.external_ref = source["external_ref"];
+	        this.context = source["context"];
+	        this.estimate_seconds = source["estimate_seconds"];
+	        this.auto_stopped = source["auto_stopped"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
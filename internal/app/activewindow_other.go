@@ -0,0 +1,49 @@
+//go:build !windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// focusedWindow returns the application and title of the focused window
+// macOS asks System Events for the frontmost app (no title, which would need
+// accessibility access); Linux needs xdotool, which only works under X11
+func focusedWindow() (activeWindow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), activityWindowTimeout)
+	defer cancel()
+
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.CommandContext(ctx, "osascript", "-e",
+			`tell application "System Events" to get name of first application process whose frontmost is true`).Output()
+		if err != nil {
+			return activeWindow{}, fmt.Errorf("failed to run osascript: %w", err)
+		}
+		return activeWindow{App: strings.TrimSpace(string(out))}, nil
+	case "linux":
+		out, err := exec.CommandContext(ctx, "xdotool", "getactivewindow", "getwindowpid", "getwindowname").Output()
+		if err != nil {
+			return activeWindow{}, fmt.Errorf("failed to run xdotool: %w", err)
+		}
+		return parseXdotoolWindow(string(out)), nil
+	default:
+		return activeWindow{}, fmt.Errorf("detecting the focused window is not supported on %s", runtime.GOOS)
+	}
+}
+
+// parseXdotoolWindow reads the "pid" and "title" lines printed by xdotool and
+// looks up the process name of pid
+func parseXdotoolWindow(out string) activeWindow {
+	pid, title, _ := strings.Cut(strings.TrimRight(out, "\n"), "\n")
+	window := activeWindow{Title: strings.TrimSpace(title)}
+	if comm, err := os.ReadFile("/proc/" + strings.TrimSpace(pid) + "/comm"); err == nil {
+		window.App = strings.TrimSpace(string(comm))
+	}
+	return window
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// processQueryLimitedInformation is the PROCESS_QUERY_LIMITED_INFORMATION access right
+const processQueryLimitedInformation = 0x1000
+
+var (
+	procGetForegroundWindow        = user32.NewProc("GetForegroundWindow")
+	procGetWindowTextW             = user32.NewProc("GetWindowTextW")
+	procGetWindowThreadProcessId   = user32.NewProc("GetWindowThreadProcessId")
+	procOpenProcess                = kernel32.NewProc("OpenProcess")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+	procCloseHandle                = kernel32.NewProc("CloseHandle")
+)
+
+// focusedWindow returns the executable name and title of the foreground window
+func focusedWindow() (activeWindow, error) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return activeWindow{}, fmt.Errorf("no foreground window")
+	}
+
+	title := make([]uint16, 512)
+	procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&title[0])), uintptr(len(title)))
+	window := activeWindow{Title: syscall.UTF16ToString(title)}
+
+	var pid uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	process, _, err := procOpenProcess.Call(processQueryLimitedInformation, 0, uintptr(pid))
+	if process == 0 {
+		// Elevated processes can't be opened; the title is still useful
+		if window.Title == "" {
+			return activeWindow{}, fmt.Errorf("failed to open foreground process: %w", err)
+		}
+		return window, nil
+	}
+	defer procCloseHandle.Call(process)
+
+	path := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(path))
+	if ret, _, _ := procQueryFullProcessImageNameW.Call(process, 0, uintptr(unsafe.Pointer(&path[0])), uintptr(unsafe.Pointer(&size))); ret != 0 {
+		name := filepath.Base(syscall.UTF16ToString(path[:size]))
+		window.App = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return window, nil
+}
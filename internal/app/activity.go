@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"light-tracking/internal/models"
+)
+
+const (
+	// activityCheckInterval is how often the focused window is checked while the timer runs
+	activityCheckInterval = 30 * time.Second
+	// activityWindowTimeout bounds the tool run to find the focused window
+	activityWindowTimeout = 2 * time.Second
+	// minActivityKeywordLength is the shortest task name word used as a keyword
+	minActivityKeywordLength = 3
+)
+
+// activeWindow is the focused application window
+// Either field may be empty where the platform doesn't report it
+type activeWindow struct {
+	App   string
+	Title string
+}
+
+// ownWindow reports whether the window belongs to this app, which is neither
+// a match nor a mismatch: starting the timer often happens right here
+func (w activeWindow) ownWindow() bool {
+	app := strings.ToLower(w.App)
+	return app == "light-tracking" || app == "light tracking" ||
+		strings.Contains(strings.ToLower(w.Title), "light tracking")
+}
+
+// activityKeywords returns the words expected in the focused window while a
+// task is tracked: the ActivityKeywords setting for the task, or else the words
+// of the task name itself
+func activityKeywords(taskName string, configured map[string][]string) []string {
+	if keywords := configured[taskName]; len(keywords) > 0 {
+		return keywords
+	}
+	keywords := []string{}
+	for _, word := range strings.FieldsFunc(taskName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(word) >= minActivityKeywordLength {
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// windowMatches reports whether the window's app or title mentions a keyword
+// Without keywords there is nothing to compare, so every window matches
+func windowMatches(window activeWindow, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	text := strings.ToLower(window.App + " " + window.Title)
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActivityChecker watches the focused window while the timer runs and sends a
+// gentle reminder when it hasn't matched the tracked task for the
+// ActivityCheckMinutes setting, e.g. "coding" is tracked but a browser is in front
+// It is best-effort: where the focused window can't be read it does nothing.
+type ActivityChecker struct {
+	app          *App
+	ctx          context.Context
+	activeWindow func() (activeWindow, error)
+
+	mu            sync.Mutex
+	slotID        int64     // active slot the mismatch state belongs to
+	mismatchSince time.Time // first check of the current mismatch, zero while matching
+	reminded      bool      // the reminder for slotID was sent
+	warned        bool      // window detection unavailability was logged
+}
+
+// NewActivityChecker creates an activity checker reading the focused window
+func NewActivityChecker(app *App) *ActivityChecker {
+	return &ActivityChecker{
+		app:          app,
+		activeWindow: focusedWindow,
+	}
+}
+
+// Start begins checking the focused window until ctx is cancelled
+func (c *ActivityChecker) Start(ctx context.Context) {
+	c.ctx = ctx
+	c.app.goWorker(c.monitor)
+}
+
+// monitor checks the focused window periodically
+func (c *ActivityChecker) monitor() {
+	ticker := time.NewTicker(activityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.check(c.app.now())
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// check compares the focused window with the running work slot and reminds
+// once per slot when they have not matched for long enough
+func (c *ActivityChecker) check(now time.Time) {
+	settings := c.app.settings.Get()
+	activeSlot := c.app.GetActiveTimeSlot()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !settings.ActivityCheckEnabled || activeSlot == nil || activeSlot.IsPaused() || activeSlot.Kind == models.KindBreak {
+		c.mismatchSince = time.Time{}
+		return
+	}
+	if activeSlot.ID != c.slotID {
+		c.slotID = activeSlot.ID
+		c.mismatchSince = time.Time{}
+		c.reminded = false
+	}
+	if c.reminded {
+		return
+	}
+
+	window, err := c.activeWindow()
+	if err != nil {
+		if !c.warned {
+			log.Println("Activity check unavailable:", err)
+			c.warned = true
+		}
+		return
+	}
+	if window.ownWindow() {
+		return
+	}
+	if windowMatches(window, activityKeywords(activeSlot.TaskName, settings.ActivityKeywords)) {
+		c.mismatchSince = time.Time{}
+		return
+	}
+
+	if c.mismatchSince.IsZero() {
+		c.mismatchSince = now
+		return
+	}
+	elapsed := now.Sub(c.mismatchSince)
+	if elapsed < time.Duration(settings.ActivityCheckMinutes)*time.Minute {
+		return
+	}
+
+	c.reminded = true
+	if c.app.notificationManager == nil {
+		return
+	}
+	where := window.App
+	if where == "" {
+		where = window.Title
+	}
+	c.app.notificationManager.SendNotificationWithUrgency(
+		"Still on '"+activeSlot.TaskName+"'?",
+		fmt.Sprintf("You've been in %s for %s while tracking '%s'", truncateRunes(where, trayTaskNameLength), formatShortDuration(elapsed), activeSlot.TaskName),
+		UrgencyLow,
+	)
+}
+
+// SetActivityCheckEnabled turns on the reminder sent when the focused window
+// doesn't seem to match the tracked task for a while
+func (a *App) SetActivityCheckEnabled(enabled bool) error {
+	return a.settings.Update(func(s *Settings) {
+		s.ActivityCheckEnabled = enabled
+	})
+}
@@ -2,7 +2,14 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"light-tracking/internal/models"
 )
@@ -10,12 +17,36 @@ import (
 // App struct holds the application state
 type App struct {
 	ctx                context.Context
-	database           *Database
+	now                func() time.Time // clock shared with timer, see setClock
+	database           Store
 	timer              *Timer
 	systrayManager     *SystrayManager
 	notificationManager *NotificationManager
+	tickEmitter         *TickEmitter
+	idleDetector        *IdleDetector
+	recurringScheduler  *RecurringScheduler
+	pomodoroManager     *PomodoroManager
+	activityChecker     *ActivityChecker
+	settings            *SettingsManager
+
+	recoveryMu    sync.Mutex
+	recoveredSlot *models.TimeSlot // running slot found at startup, until resolved
+	statePath     string           // recovery file, empty disables it
+
+	toggleMu   sync.Mutex
+	lastToggle time.Time // last start or stop, see debounced
+
+	focusMu   sync.Mutex
+	focusMode bool // see EnterFocusMode
+
+	// Background goroutines started by Startup, see goWorker
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
 }
 
+// workerShutdownTimeout bounds how long shutdown waits for background goroutines
+const workerShutdownTimeout = 5 * time.Second
+
 // NewApp creates a new App application struct
 func NewApp() (*App, error) {
 	db, err := NewDatabase()
@@ -23,112 +54,2250 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
+	settings, err := NewSettingsManager()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	statePath, err := recoveryStatePath()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	app, err := NewAppWithStore(db, settings)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	app.statePath = statePath
+	app.recoverState()
+	return app, nil
+}
+
+// NewAppWithStore creates an App on top of the given store and settings
+func NewAppWithStore(db Store, settings *SettingsManager) (*App, error) {
 	app := &App{
+		now:                time.Now,
 		database:           db,
-		timer:              NewTimer(),
+		timer:              NewTimer(db),
 		systrayManager:     nil, // Will be set in Startup
 		notificationManager: nil, // Will be set in Startup
+		settings:            settings,
 	}
 
+	app.timer.SetStopRounding(time.Duration(settings.Get().RoundStopToMinutes) * time.Minute)
+
 	// Load active slot from database on startup
-	if err := app.timer.LoadActiveSlot(db); err != nil {
-		return nil, err
+	// An encrypted database can't be read until UnlockDatabase is called
+	if !db.IsLocked() {
+		if err := app.loadActiveSlot(); err != nil {
+			return nil, err
+		}
 	}
 
 	return app, nil
 }
 
+// setClock replaces the clock of the app and its timer, for tests
+// It must be called before the app is used from other goroutines
+func (a *App) setClock(now func() time.Time) {
+	a.now = now
+	a.timer.setClock(now)
+}
+
 // Startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
+	a.pruneHistory()
+	a.startWorkers(ctx)
+}
+
+// startWorkers starts the tray, notifications and other background goroutines
+// They get their own context so shutdown can stop them before the database
+// closes; it keeps the Wails runtime values of ctx
+func (a *App) startWorkers(ctx context.Context) {
+	workerCtx, cancel := context.WithCancel(ctx)
+	a.cancelWorkers = cancel
 	// Initialize systray with delay to let Wails/GTK fully initialize
-	go func() {
-		time.Sleep(500 * time.Millisecond) // Wait for Wails/GTK to fully initialize
-		a.systrayManager = NewSystrayManager(a)
-		a.systrayManager.Run(ctx)
-	}()
+	// Without it the window is controlled and closed through the normal window controls
+	if !a.settings.Get().DisableSystray {
+		a.goWorker(func() {
+			// Wait for Wails/GTK to fully initialize
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-workerCtx.Done():
+				return
+			}
+			a.systrayManager = NewSystrayManager(a)
+			a.systrayManager.Run(workerCtx)
+		})
+	}
 	// Initialize notifications
 	a.notificationManager = NewNotificationManager(a)
-	a.notificationManager.Start(ctx)
+	a.notificationManager.Start(workerCtx)
+	// Emit timer ticks for the frontend counter
+	a.tickEmitter = NewTickEmitter(a)
+	a.tickEmitter.Start(workerCtx)
+	// Ask what to do with time spent away from the keyboard
+	a.idleDetector = NewIdleDetector(a)
+	a.idleDetector.Start(workerCtx)
+	// Log or prompt for recurring tasks such as a daily standup
+	a.recurringScheduler = NewRecurringScheduler(a)
+	a.recurringScheduler.Start(workerCtx)
+	// Record completed and interrupted pomodoros
+	a.pomodoroManager = NewPomodoroManager(a)
+	a.pomodoroManager.Start(workerCtx)
+	// Remind when the focused window doesn't match the tracked task
+	a.activityChecker = NewActivityChecker(a)
+	a.activityChecker.Start(workerCtx)
+	// Keep the crash recovery file up to date
+	a.goWorker(func() { a.persistState(workerCtx) })
+}
+
+// goWorker runs fn in a background goroutine that stopWorkers waits for
+// fn must return once the context passed to the managers is cancelled
+func (a *App) goWorker(fn func()) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		fn()
+	}()
+}
+
+// stopWorkers cancels the background goroutines and waits for them to exit,
+// so none of them touches the timer or database after shutdown
+// A goroutine stuck in a slow call (e.g. a notification command) is given up on
+// after workerShutdownTimeout rather than blocking the quit.
+func (a *App) stopWorkers() {
+	if a.cancelWorkers == nil {
+		return
+	}
+	a.cancelWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(workerShutdownTimeout):
+		log.Println("Timed out waiting for background tasks to stop")
+	}
+}
+
+// loadActiveSlot restores the running timer from the database
+// The slot is kept for recovery so the user can decide what to do with it
+func (a *App) loadActiveSlot() error {
+	if err := a.timer.LoadActiveSlot(); err != nil {
+		return err
+	}
+
+	a.recoveryMu.Lock()
+	a.recoveredSlot = a.timer.GetActiveSlot()
+	a.recoveryMu.Unlock()
+	return nil
+}
+
+// pruneHistory deletes completed slots older than the MaxHistoryDays setting
+func (a *App) pruneHistory() {
+	days := a.settings.Get().MaxHistoryDays
+	if days <= 0 || a.database.IsLocked() {
+		return
+	}
+
+	pruned, err := a.database.PruneOlderThan(a.now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Println("Failed to prune history:", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("Pruned %d time slots older than %d days", pruned, days)
+		a.dataChanged()
+	}
+}
+
+// DomReady is called once the frontend has loaded
+// A running timer left over from the previous session is announced so the user can resolve it
+func (a *App) DomReady(ctx context.Context) {
+	a.restoreWindowState()
+	if recovered := a.GetRecoveredSlot(); recovered != nil {
+		a.emit(EventTimerRecovered, recovered)
+	}
+}
+
+// Shutdown is called when the app is about to quit
+// The running timer is finalized unless the user chose to keep tracking across sessions
+func (a *App) Shutdown(ctx context.Context) {
+	a.stopWorkers()
+	defer a.saveState()
+	if !a.settings.Get().StopTimerOnQuit {
+		return
+	}
+	if _, err := a.timer.Stop(); err != nil {
+		log.Println("Failed to stop timer on quit:", err)
+	}
+}
+
+// ErrTaskNameTooLong is returned for task names longer than the MaxTaskNameLength setting
+var ErrTaskNameTooLong = errors.New("task name is too long")
+
+// normalizeTaskName trims a task name and collapses inner whitespace
+func normalizeTaskName(taskName string) string {
+	return strings.Join(strings.Fields(taskName), " ")
+}
+
+// checkTaskNameLength rejects task names longer than the configured maximum
+func (a *App) checkTaskNameLength(taskName string) error {
+	maxLength := a.settings.Get().MaxTaskNameLength
+	if length := utf8.RuneCountInString(taskName); maxLength > 0 && length > maxLength {
+		return fmt.Errorf("%w: %d characters, at most %d allowed", ErrTaskNameTooLong, length, maxLength)
+	}
+	return nil
 }
 
 // StartTimer starts tracking time for a task
+// An empty task name falls back to the DefaultTaskName setting
 func (a *App) StartTimer(taskName string) (*models.TimeSlot, error) {
+	slot, _, err := a.startWork(taskName, "")
+	return slot, err
+}
+
+// StartTimerWithRef starts tracking time for a task linked to an external
+// issue or ticket, e.g. "PROJ-123" or "owner/repo#42"
+func (a *App) StartTimerWithRef(taskName string, externalRef string) (*models.TimeSlot, error) {
+	externalRef, err := normalizeExternalRef(externalRef)
+	if err != nil {
+		return nil, err
+	}
+	slot, _, err := a.startWork(taskName, externalRef)
+	return slot, err
+}
+
+// StartTimerWithEstimate starts tracking time for a task expected to take
+// estimateMinutes; a notification is sent when the running slot exceeds it
+// GetEstimateAccuracy compares estimates with the time actually tracked
+// A debounced start returns the running slot with its estimate unchanged
+func (a *App) StartTimerWithEstimate(taskName string, estimateMinutes int) (*models.TimeSlot, error) {
+	if estimateMinutes <= 0 || estimateMinutes > maxEstimateMinutes {
+		return nil, fmt.Errorf("estimate must be between 1 and %d minutes", maxEstimateMinutes)
+	}
+	slot, started, err := a.startWork(taskName, "")
+	if err != nil || !started {
+		return slot, err
+	}
+	if err := a.timer.SetEstimate(slot.ID, time.Duration(estimateMinutes)*time.Minute); err != nil {
+		return nil, err
+	}
+
+	slot = a.timer.GetActiveSlot()
+	a.slotsChanged(slot)
+	return slot, nil
+}
+
+// startWork starts a work slot, falling back to the default task name
+// It reports whether a new slot was started, see startSlot
+func (a *App) startWork(taskName string, externalRef string) (*models.TimeSlot, bool, error) {
+	taskName = normalizeTaskName(taskName)
 	if taskName == "" {
-		return nil, nil
+		taskName = a.settings.Get().DefaultTaskName
+	}
+	if taskName == "" {
+		return nil, false, ErrEmptyTaskName
+	}
+	if err := a.checkTaskNameLength(taskName); err != nil {
+		return nil, false, err
 	}
-	return a.timer.Start(taskName, a.database)
+	return a.startSlot(taskName, models.KindWork, externalRef)
 }
 
-// StopTimer stops the current timer
-func (a *App) StopTimer() (*models.TimeSlot, error) {
-	return a.timer.Stop(a.database)
+// debounced reports whether a start or stop follows the previous one within the
+// ToggleDebounceMs setting, e.g. a double click or key repeat, and should be
+// ignored; otherwise the toggle is recorded
+func (a *App) debounced() bool {
+	window := time.Duration(a.settings.Get().ToggleDebounceMs) * time.Millisecond
+
+	a.toggleMu.Lock()
+	defer a.toggleMu.Unlock()
+
+	now := a.now()
+	if window > 0 && now.Sub(a.lastToggle) < window {
+		log.Println("Ignoring a repeated start or stop")
+		return true
+	}
+	a.lastToggle = now
+	return false
 }
 
-// GetActiveTimeSlot returns the currently active time slot
-func (a *App) GetActiveTimeSlot() *models.TimeSlot {
-	return a.timer.GetActiveSlot()
+// startSlot starts the timer, stopping the running slot first, and reports
+// whether a new slot was started
+// A start right after another start or stop is ignored and returns the running
+// slot, which may be nil, without starting a new one
+func (a *App) startSlot(taskName string, kind string, externalRef string) (*models.TimeSlot, bool, error) {
+	if a.debounced() {
+		return a.timer.GetActiveSlot(), false, nil
+	}
+
+	previous := a.timer.GetActiveSlot()
+	slot, err := a.timer.Start(taskName, kind, externalRef, a.resolveContext())
+	if err != nil {
+		return nil, false, err
+	}
+
+	a.slotsChanged(previous, slot)
+	if kind == models.KindWork {
+		a.autoFocus()
+	}
+	return slot, true, nil
 }
 
-// IsTimerRunning returns whether the timer is currently running
-func (a *App) IsTimerRunning() bool {
-	return a.timer.IsRunning()
+// maxExternalRefLength is the longest accepted external reference in characters
+const maxExternalRefLength = 255
+
+// normalizeExternalRef trims an external reference and checks its length
+func normalizeExternalRef(externalRef string) (string, error) {
+	externalRef = strings.TrimSpace(externalRef)
+	if utf8.RuneCountInString(externalRef) > maxExternalRefLength {
+		return "", fmt.Errorf("external reference must be at most %d characters", maxExternalRefLength)
+	}
+	return externalRef, nil
 }
 
-// GetElapsedTime returns the elapsed time for the current session
-func (a *App) GetElapsedTime() int64 {
-	return int64(a.timer.GetElapsedTime().Seconds())
+// SetTimeSlotRef links a time slot to an external issue or ticket; an empty ref removes the link
+func (a *App) SetTimeSlotRef(id int64, externalRef string) error {
+	externalRef, err := normalizeExternalRef(externalRef)
+	if err != nil {
+		return err
+	}
+	if err := a.timer.SetExternalRef(id, externalRef); err != nil {
+		return err
+	}
+
+	if slot, err := a.database.GetTimeSlot(id); err == nil {
+		a.slotsChanged(slot)
+	}
+	return nil
 }
 
-// GetTimeSlotsByDate returns all time slots for a specific date
-// date should be in format "2006-01-02" (YYYY-MM-DD)
-func (a *App) GetTimeSlotsByDate(dateStr string) ([]*models.TimeSlot, error) {
-	date, err := time.Parse("2006-01-02", dateStr)
+// SetCurrentContext sets where new slots are tracked, e.g. "home" or "office"
+// A Wi-Fi network listed in the NetworkContexts setting overrides it; an empty
+// label clears it. Running slots keep the context they started with.
+func (a *App) SetCurrentContext(label string) error {
+	label, err := normalizeContext(label)
+	if err != nil {
+		return err
+	}
+	return a.settings.Update(func(s *Settings) {
+		s.CurrentContext = label
+	})
+}
+
+// GetCurrentContext returns the context a slot started now would be recorded with
+func (a *App) GetCurrentContext() string {
+	return a.resolveContext()
+}
+
+// GetStatisticsByContext returns the work seconds per context between two dates
+// (inclusive); time tracked without a context is listed as "No context"
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetStatisticsByContext(startStr string, endStr string, roundToMinutes int, mode string) (map[string]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
 	if err != nil {
 		return nil, err
 	}
-	return a.database.GetTimeSlotsByDate(date)
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := a.database.GetContextTotals(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if seconds, ok := totals[""]; ok {
+		delete(totals, "")
+		totals[noContextLabel] += seconds
+	}
+	return rounding.totals(totals), nil
 }
 
-// GetTaskStatistics returns aggregated statistics by task name for a specific date
-// date should be in format "2006-01-02" (YYYY-MM-DD)
-func (a *App) GetTaskStatistics(dateStr string) (map[string]int64, error) {
-	date, err := time.Parse("2006-01-02", dateStr)
+// GetTimeByRef returns the seconds tracked per external reference between two
+// dates (inclusive); an empty ref returns the totals of every reference
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTimeByRef(externalRef string, startStr string, endStr string, roundToMinutes int, mode string) (map[string]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := a.database.GetTimeByRef(strings.TrimSpace(externalRef), start, end)
 	if err != nil {
 		return nil, err
 	}
-	return a.database.GetTaskStatistics(date)
+	return rounding.totals(totals), nil
 }
 
-// UpdateTimeSlot updates a time slot
-// startTime and endTime should be in RFC3339 format (ISO 8601)
-// endTime can be empty string for active slots
-func (a *App) UpdateTimeSlot(id int64, taskName string, startTimeStr string, endTimeStr string) error {
-	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+// GetEstimateAccuracy returns, per task, the estimated and actual time of completed
+// slots started with an estimate between the given dates (inclusive)
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the actual time, see newReportRounding; the
+// estimates stay as entered and the variance compares them to the rounded time
+func (a *App) GetEstimateAccuracy(startStr string, endStr string, roundToMinutes int, mode string) ([]EstimateAccuracy, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := a.database.GetEstimateTotals(start, end)
+	if err != nil {
+		return nil, err
+	}
+	for i := range totals {
+		totals[i].ActualSeconds = rounding.seconds(totals[i].ActualSeconds)
+	}
+	return withVariance(totals), nil
+}
+
+// breakTaskName is the task name of slots started with StartBreak
+const breakTaskName = "Break"
+
+// StartBreak starts tracking a break; like StartTimer it stops any running slot
+// Break time is excluded from work statistics and the monthly report
+func (a *App) StartBreak() (*models.TimeSlot, error) {
+	slot, _, err := a.startSlot(breakTaskName, models.KindBreak, "")
+	return slot, err
+}
+
+// StartFromSlot starts a new timer for the task, kind and external reference of an existing time slot
+// Any running timer is stopped first, as with StartTimer
+func (a *App) StartFromSlot(id int64) (*models.TimeSlot, error) {
+	slot, err := a.database.GetTimeSlot(id)
 	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, fmt.Errorf("time slot %d %w", id, ErrNotFound)
+	}
+	slot, _, err = a.startSlot(slot.TaskName, slot.Kind, slot.ExternalRef)
+	return slot, err
+}
+
+// RenameActiveSlot changes the task name of the running timer without stopping it
+func (a *App) RenameActiveSlot(newName string) error {
+	newName = normalizeTaskName(newName)
+	if newName == "" {
+		return ErrEmptyTaskName
+	}
+	if err := a.checkTaskNameLength(newName); err != nil {
 		return err
 	}
 
-	var endTime *time.Time
-	if endTimeStr != "" {
-		et, err := time.Parse(time.RFC3339, endTimeStr)
+	slot, err := a.timer.Rename(newName)
+	if err != nil {
+		return err
+	}
+
+	a.emit(EventTimerRenamed, slot)
+	a.slotsChanged(slot)
+	return nil
+}
+
+// StopTimer stops the current timer
+// A session longer than the ConfirmStopAfterMinutes setting is left running and
+// ErrConfirmationRequired is returned; StopTimerConfirmed stops it
+func (a *App) StopTimer() (*models.TimeSlot, error) {
+	if err := a.checkStopConfirmation(); err != nil {
+		return nil, err
+	}
+	return a.StopTimerConfirmed()
+}
+
+// StopTimerConfirmed stops the current timer without asking for confirmation
+// A stop right after a start or stop is ignored and returns the running slot
+func (a *App) StopTimerConfirmed() (*models.TimeSlot, error) {
+	if a.debounced() {
+		return a.timer.GetActiveSlot(), nil
+	}
+
+	slot, err := a.timer.Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	a.slotsChanged(slot)
+	return slot, nil
+}
+
+// checkStopConfirmation returns ErrConfirmationRequired when the running session
+// is longer than the ConfirmStopAfterMinutes setting
+func (a *App) checkStopConfirmation() error {
+	threshold := time.Duration(a.settings.Get().ConfirmStopAfterMinutes) * time.Minute
+	if threshold <= 0 {
+		return nil
+	}
+	if elapsed := a.timer.GetElapsedTime(); elapsed > threshold {
+		return fmt.Errorf("%w: stop this %s session?", ErrConfirmationRequired, formatShortDuration(elapsed))
+	}
+	return nil
+}
+
+// StopResult is the outcome of stopping the timer together with today's statistics
+type StopResult struct {
+	Slot       *models.TimeSlot `json:"slot"`
+	Statistics map[string]int64 `json:"statistics"`
+}
+
+// StopTimerAndGetTodayStats stops the current timer and returns the stopped slot
+// with today's statistics read in the same transaction
+// Slot is nil when no timer was running and the running slot when the stop was
+// ignored as a repeat, see debounced
+// A session longer than the ConfirmStopAfterMinutes setting is left running and
+// ErrConfirmationRequired is returned; StopTimerAndGetTodayStatsConfirmed stops it
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) StopTimerAndGetTodayStats(roundToMinutes int, mode string) (*StopResult, error) {
+	if _, err := newReportRounding(roundToMinutes, mode); err != nil {
+		return nil, err
+	}
+	if err := a.checkStopConfirmation(); err != nil {
+		return nil, err
+	}
+	return a.StopTimerAndGetTodayStatsConfirmed(roundToMinutes, mode)
+}
+
+// StopTimerAndGetTodayStatsConfirmed is StopTimerAndGetTodayStats without asking
+// for confirmation
+func (a *App) StopTimerAndGetTodayStatsConfirmed(roundToMinutes int, mode string) (*StopResult, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	if a.debounced() {
+		stats, err := a.database.GetTaskStatisticsForRange(dayStart, dayEnd)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		return &StopResult{Slot: a.timer.GetActiveSlot(), Statistics: rounding.totals(stats)}, nil
+	}
+
+	var stats map[string]int64
+	slot, err := a.timer.StopWith(now, func(id int64, endTime time.Time) error {
+		var err error
+		stats, err = a.database.StopTimeSlotAndGetStatistics(id, endTime, dayStart, dayEnd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if slot == nil {
+		stats, err = a.database.GetTaskStatisticsForRange(dayStart, dayEnd)
+		if err != nil {
+			return nil, err
 		}
-		endTime = &et
 	}
+	a.slotsChanged(slot)
 
-	return a.database.UpdateTimeSlot(id, taskName, startTime, endTime)
+	return &StopResult{Slot: slot, Statistics: rounding.totals(stats)}, nil
 }
 
-// DeleteTimeSlot deletes a time slot
-func (a *App) DeleteTimeSlot(id int64) error {
-	return a.database.DeleteTimeSlot(id)
+// QuickToggle stops the running timer, or restarts the last tracked task when stopped
+// The DefaultTaskName setting takes precedence over the last task; "Untitled"
+// is used when neither exists. Stopping from the tray menu is deliberate and
+// can't show a dialog, so long sessions stop without confirmation.
+func (a *App) QuickToggle() (*models.TimeSlot, error) {
+	if a.timer.IsRunning() {
+		return a.StopTimerConfirmed()
+	}
+	if defaultName := a.settings.Get().DefaultTaskName; defaultName != "" {
+		return a.StartTimer(defaultName)
+	}
+
+	taskName := "Untitled"
+	last, err := a.database.GetLastCompletedSlot()
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		taskName = last.TaskName
+	}
+	return a.StartTimer(taskName)
+}
+
+// AdjustActiveStart moves the start time of the running timer, e.g. to drop time spent away
+// newStart should be in RFC3339 format (ISO 8601) and must not be in the future
+func (a *App) AdjustActiveStart(newStartStr string) error {
+	newStart, err := time.Parse(time.RFC3339, newStartStr)
+	if err != nil {
+		return err
+	}
+	if newStart.After(a.now()) {
+		return fmt.Errorf("start time cannot be in the future")
+	}
+
+	previous := a.timer.GetActiveSlot()
+	slot, err := a.timer.AdjustStart(newStart)
+	if err != nil {
+		return err
+	}
+
+	a.emit(EventTimerAdjusted, slot)
+	a.slotsChanged(previous, slot)
+	return nil
+}
+
+// AppendActiveNote adds a timestamped line such as "[14:05] blocked on API key"
+// to the notes of the running slot; ErrTimerNotRunning is returned when the timer is stopped
+func (a *App) AppendActiveNote(note string) error {
+	note = strings.Join(strings.Fields(note), " ")
+	if note == "" {
+		return fmt.Errorf("note cannot be empty")
+	}
+
+	slot, err := a.timer.AppendNote(fmt.Sprintf("[%s] %s", a.now().Format("15:04"), note))
+	if err != nil {
+		return err
+	}
+
+	a.slotsChanged(slot)
+	return nil
+}
+
+// PauseTimer pauses the running timer without ending its slot
+func (a *App) PauseTimer() error {
+	slot, err := a.timer.Pause()
+	if err != nil {
+		return err
+	}
+
+	a.emit(EventTimerPaused, slot)
+	a.slotsChanged(slot)
+	return nil
+}
+
+// ResumeTimer continues a paused timer
+func (a *App) ResumeTimer() error {
+	slot, err := a.timer.Resume()
+	if err != nil {
+		return err
+	}
+
+	a.emit(EventTimerResumed, slot)
+	a.slotsChanged(slot)
+	return nil
+}
+
+// IsTimerPaused returns whether the running timer is paused
+func (a *App) IsTimerPaused() bool {
+	return a.timer.IsPaused()
+}
+
+// TimerState is a consistent snapshot of the timer
+type TimerState struct {
+	Running        bool             `json:"running"`
+	Paused         bool             `json:"paused"`
+	Slot           *models.TimeSlot `json:"slot,omitempty"`
+	ElapsedSeconds int64            `json:"elapsed_seconds"`
+}
+
+// GetTimerState returns whether the timer runs or is paused, its slot and elapsed time
+// Reading everything at once avoids mixing states when the timer changes in between
+func (a *App) GetTimerState() *TimerState {
+	slot := a.timer.GetActiveSlot()
+	if slot == nil {
+		return &TimerState{}
+	}
+
+	now := a.now()
+	elapsed := now.Sub(slot.StartTime) - slot.PausedUntil(now)
+	return &TimerState{
+		Running:        true,
+		Paused:         slot.IsPaused(),
+		Slot:           slot,
+		ElapsedSeconds: int64(elapsed.Seconds()),
+	}
+}
+
+// SessionBreakdown splits the wall-clock span of a slot into active and paused time
+type SessionBreakdown struct {
+	ActiveSeconds int64 `json:"active_seconds"`
+	PausedSeconds int64 `json:"paused_seconds"`
+	WallSeconds   int64 `json:"wall_seconds"`
+}
+
+// GetSessionBreakdown returns the active and paused time of a slot
+// The running slot is measured up to now
+func (a *App) GetSessionBreakdown(id int64) (*SessionBreakdown, error) {
+	slot := a.timer.GetActiveSlot()
+	if slot == nil || slot.ID != id {
+		var err error
+		slot, err = a.database.GetTimeSlot(id)
+		if err != nil {
+			return nil, err
+		}
+		if slot == nil {
+			return nil, fmt.Errorf("time slot %d %w", id, ErrNotFound)
+		}
+	}
+
+	end := a.now()
+	if slot.EndTime != nil {
+		end = *slot.EndTime
+	}
+	wall := int64(end.Sub(slot.StartTime).Seconds())
+	paused := int64(slot.PausedUntil(end).Seconds())
+
+	return &SessionBreakdown{
+		ActiveSeconds: max(wall-paused, 0),
+		PausedSeconds: paused,
+		WallSeconds:   wall,
+	}, nil
+}
+
+// GetRecoveredSlot returns the running timer found at startup, or nil if there
+// was none or it has already been resolved
+func (a *App) GetRecoveredSlot() *RecoveredSlot {
+	a.recoveryMu.Lock()
+	defer a.recoveryMu.Unlock()
+
+	if a.recoveredSlot == nil {
+		return nil
+	}
+	return &RecoveredSlot{
+		Slot:           a.recoveredSlot,
+		ElapsedSeconds: int64((a.now().Sub(a.recoveredSlot.StartTime) - a.recoveredSlot.PausedUntil(a.now())).Seconds()),
+	}
+}
+
+// ResolveRecoveredSlot handles the user's choice for the timer recovered at startup:
+// "keep" continues tracking, "stop" ends the slot at endStr (RFC3339, empty for now)
+// and "discard" deletes it
+func (a *App) ResolveRecoveredSlot(action string, endStr string) error {
+	if err := validateRecoveryAction(action); err != nil {
+		return err
+	}
+
+	a.recoveryMu.Lock()
+	defer a.recoveryMu.Unlock()
+
+	recovered := a.recoveredSlot
+	if recovered == nil {
+		return fmt.Errorf("no recovered timer to resolve")
+	}
+	if active := a.timer.GetActiveSlot(); active == nil || active.ID != recovered.ID {
+		// The user already started or stopped the timer some other way
+		a.recoveredSlot = nil
+		return fmt.Errorf("recovered timer is no longer running")
+	}
+
+	switch action {
+	case RecoveryStop:
+		endTime := a.now()
+		if endStr != "" {
+			et, err := time.Parse(time.RFC3339, endStr)
+			if err != nil {
+				return err
+			}
+			if !et.After(recovered.StartTime) {
+				return fmt.Errorf("end time must be after start time")
+			}
+			if et.After(endTime) {
+				return fmt.Errorf("end time is in the future")
+			}
+			endTime = et
+		}
+		slot, err := a.timer.StopAt(endTime)
+		if err != nil {
+			return err
+		}
+		a.slotsChanged(slot)
+	case RecoveryDiscard:
+		slot, err := a.timer.Discard()
+		if err != nil {
+			return err
+		}
+		a.slotsChanged(slot)
+	}
+
+	a.recoveredSlot = nil
+	return nil
+}
+
+// MergeWithPrevious undoes an accidental stop: if the previous slot has the same task
+// and ended within the configured gap, it is removed and the running timer starts
+// where the previous slot started
+func (a *App) MergeWithPrevious() (*models.TimeSlot, error) {
+	maxGap := time.Duration(a.settings.Get().MergeGapMinutes) * time.Minute
+
+	slot, err := a.timer.MergeWithPrevious(maxGap)
+	if err != nil {
+		return nil, err
+	}
+
+	a.emit(EventTimerAdjusted, slot)
+	a.slotsChanged(slot)
+	return slot, nil
+}
+
+// ReopenLastStopped undoes an accidental stop by resuming the most recently
+// stopped slot with its original start time; the time since the stop counts as tracked.
+// Only slots stopped within the ReopenWindowMinutes setting can be reopened.
+func (a *App) ReopenLastStopped() (*models.TimeSlot, error) {
+	maxAge := time.Duration(a.settings.Get().ReopenWindowMinutes) * time.Minute
+
+	slot, err := a.timer.Reopen(maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.emit(EventTimerStarted, slot)
+	a.slotsChanged(slot)
+	return slot, nil
+}
+
+// UndoStart takes back a timer started by mistake, e.g. the wrong task from the tray
+// The slot is deleted and a slot the start stopped keeps running as if nothing
+// happened. Only starts within the UndoStartSeconds setting can be undone.
+// Returns the running slot afterwards, nil if no timer ran before.
+func (a *App) UndoStart() (*models.TimeSlot, error) {
+	grace := time.Duration(a.settings.Get().UndoStartSeconds) * time.Second
+
+	slot, err := a.timer.UndoStart(grace)
+	if err != nil {
+		return nil, err
+	}
+
+	if slot != nil {
+		a.emit(EventTimerStarted, slot)
+		a.slotsChanged(slot)
+	} else {
+		a.dataChanged(a.now())
+	}
+	return slot, nil
+}
+
+// CanUndoStart reports whether UndoStart would take back the running timer
+func (a *App) CanUndoStart() bool {
+	return a.timer.CanUndoStart(time.Duration(a.settings.Get().UndoStartSeconds) * time.Second)
+}
+
+// PlannedFinish is when the running slot reaches its planned duration
+type PlannedFinish struct {
+	FinishTime       time.Time `json:"finish_time"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+}
+
+// SetPlannedDuration time-boxes the running timer; 0 clears the plan
+func (a *App) SetPlannedDuration(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("planned duration must not be negative")
+	}
+	return a.timer.SetPlannedDuration(time.Duration(minutes) * time.Minute)
+}
+
+// GetPlannedFinishTime returns when the running slot reaches its planned duration,
+// or nil when the timer isn't running or has no plan
+// RemainingSeconds is negative once the plan is exceeded
+func (a *App) GetPlannedFinishTime() *PlannedFinish {
+	planned := a.timer.GetPlannedDuration()
+	activeSlot := a.timer.GetActiveSlot()
+	if planned == 0 || activeSlot == nil {
+		return nil
+	}
+
+	// Pauses push the finish back
+	finish := activeSlot.StartTime.Add(planned + activeSlot.PausedUntil(a.now()))
+	return &PlannedFinish{
+		FinishTime:       finish,
+		RemainingSeconds: int64(finish.Sub(a.now()).Seconds()),
+	}
+}
+
+// GetActiveTimeSlot returns the currently active time slot
+func (a *App) GetActiveTimeSlot() *models.TimeSlot {
+	return a.timer.GetActiveSlot()
+}
+
+// GetActiveTaskName returns the name of the running task, or "" when the timer is stopped
+func (a *App) GetActiveTaskName() string {
+	return a.timer.GetActiveTaskName()
+}
+
+// IsTimerRunning returns whether the timer is currently running
+func (a *App) IsTimerRunning() bool {
+	return a.timer.IsRunning()
+}
+
+// GetElapsedTime returns the elapsed time for the current session
+func (a *App) GetElapsedTime() int64 {
+	return int64(a.timer.GetElapsedTime().Seconds())
+}
+
+// ErrNoHistory is returned when nothing has been tracked yet
+var ErrNoHistory = errors.New("no time has been tracked yet")
+
+// GetTimeSinceLastActivity returns the seconds since the most recent slot ended,
+// 0 while the timer is running and ErrNoHistory if nothing was ever tracked
+func (a *App) GetTimeSinceLastActivity() (int64, error) {
+	if a.timer.IsRunning() {
+		return 0, nil
+	}
+
+	last, err := a.database.GetLastCompletedSlot()
+	if err != nil {
+		return 0, err
+	}
+	if last == nil {
+		return 0, ErrNoHistory
+	}
+
+	// An end time in the future, e.g. from a manual entry, counts as no gap
+	return max(int64(a.now().Sub(*last.EndTime).Seconds()), 0), nil
+}
+
+// SetTickInterval sets how often timer:tick events are emitted while the timer runs
+// Coarser ticks (e.g. every 5 seconds) save power when the window is in the background
+func (a *App) SetTickInterval(seconds int) error {
+	interval, err := validateTickInterval(seconds)
+	if err != nil {
+		return err
+	}
+	if a.tickEmitter == nil {
+		return fmt.Errorf("tick emitter is not started")
+	}
+	a.tickEmitter.SetInterval(interval)
+	return nil
+}
+
+// GetTimeSlotsByDate returns all time slots for a specific date
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetTimeSlotsByDate(dateStr string) ([]*models.TimeSlot, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	return a.database.GetTimeSlotsByDate(date)
+}
+
+// GetTimelineByDate returns a specific date's time slots for display, with
+// consecutive slots of a task at most mergeGapSeconds apart shown as one
+// Nothing is changed in the database; a shown slot keeps the id of the first slot
+// it joins, so edit slots from GetTimeSlotsByDate. 0 shows every slot
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetTimelineByDate(dateStr string, mergeGapSeconds int) ([]*models.TimeSlot, error) {
+	if mergeGapSeconds < 0 {
+		return nil, fmt.Errorf("merge gap must not be negative")
+	}
+	slots, err := a.GetTimeSlotsByDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	return coalesceSlots(slots, time.Duration(mergeGapSeconds)*time.Second), nil
+}
+
+// GetGroupedSlotsByDate returns a specific date's time slots grouped by task,
+// most tracked task first; slots are joined as in GetTimelineByDate
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the group totals, the slots keep exact durations
+func (a *App) GetGroupedSlotsByDate(dateStr string, mergeGapSeconds int, roundToMinutes int, mode string) ([]TaskGroup, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := a.GetTimelineByDate(dateStr, mergeGapSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return groupSlotsByTask(slots, rounding), nil
+}
+
+// GetTaskStatistics returns aggregated statistics by task name for a specific date
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTaskStatistics(dateStr string, roundToMinutes int, mode string) (map[string]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := a.database.GetTaskStatistics(date)
+	if err != nil {
+		return nil, err
+	}
+	return rounding.totals(stats), nil
+}
+
+// GetStatisticsForWindow returns per-task totals for an exact time window, e.g. a
+// morning or afternoon; slots reaching past the window count pro rata
+// startStr and endStr should be in RFC3339 format (ISO 8601)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetStatisticsForWindow(startStr string, endStr string, roundToMinutes int, mode string) (map[string]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window start: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window end: %w", err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("window end must be after its start")
+	}
+
+	slots, err := a.database.GetOverlappingTimeSlots(0, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return rounding.totals(windowTotals(slots, start, end)), nil
+}
+
+// GetSecondsInLast returns the seconds tracked in the trailing minutes, e.g. for a
+// live dashboard; a running slot counts up to now and partial slots count pro rata
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetSecondsInLast(minutes int, roundToMinutes int, mode string) (int64, error) {
+	if minutes <= 0 {
+		return 0, fmt.Errorf("minutes must be positive")
+	}
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return 0, err
+	}
+	now := a.now()
+	start := now.Add(-time.Duration(minutes) * time.Minute)
+
+	slots, err := a.database.GetOverlappingTimeSlots(0, start, now)
+	if err != nil {
+		return 0, err
+	}
+	return rounding.seconds(trailingSeconds(slots, start, now)), nil
+}
+
+// ExportChartPNG renders the task breakdown of a date as a PNG chart
+// chartType is "bar" or "pie"; a day without tracked time gives a placeholder image
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportChartPNG(dateStr string, chartType string) ([]byte, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := a.database.GetTaskStatistics(date)
+	if err != nil {
+		return nil, err
+	}
+	colors, err := a.database.GetTaskColors()
+	if err != nil {
+		return nil, err
+	}
+
+	return renderChart(stats, colors, chartType)
+}
+
+// GetFocusScore returns a 0-100 focus score for a specific date
+// Fewer, longer sessions score higher than fragmented ones, and interruption
+// markers count against it; days without tracking score 0
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetFocusScore(dateStr string) (int, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return 0, err
+	}
+	slots, err := a.database.GetTimeSlotsByDate(date)
+	if err != nil {
+		return 0, err
+	}
+	interruptions, err := a.database.CountInterruptions(date, date.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	return computeFocusScore(slots, interruptions), nil
+}
+
+// GetNetDailyTotal returns the seconds of a day covered by at least one completed slot
+// Unlike summing durations, overlapping slots are counted once
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetNetDailyTotal(dateStr string, roundToMinutes int, mode string) (int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return 0, err
+	}
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return 0, err
+	}
+	slots, err := a.database.GetTimeSlotsByDate(date)
+	if err != nil {
+		return 0, err
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	return rounding.seconds(netCoverage(slots, dayStart, dayEnd)), nil
+}
+
+// GetGoalStopTime returns when continued tracking reaches the DailyGoalMinutes
+// setting, counting today's work including the running timer; when the timer is
+// stopped it is the time if tracking started now
+// It returns nil without a goal, once the goal is met and for dates other than today
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetGoalStopTime(dateStr string) (*GoalStop, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	goal := time.Duration(a.settings.Get().DailyGoalMinutes) * time.Minute
+	now := a.now()
+	if goal <= 0 || date.Format("2006-01-02") != now.Format("2006-01-02") {
+		return nil, nil
+	}
+
+	slots, err := a.database.GetOverlappingTimeSlots(0, date, now)
+	if err != nil {
+		return nil, err
+	}
+	return goalStop(slots, date, now, goal), nil
+}
+
+// completedWorkToday returns the work seconds of completed slots started today
+func (a *App) completedWorkToday() (int64, error) {
+	now := a.now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stats, err := a.database.GetTaskStatisticsForKind(dayStart, dayStart.AddDate(0, 0, 1), models.KindWork)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, seconds := range stats {
+		total += seconds
+	}
+	return total, nil
+}
+
+// GetUntrackedGaps returns untracked periods of at least minGapMinutes, up to now
+// The running slot counts as tracked. With the schedule enabled the day is its
+// working hours, so time before the first and after the last slot of the window
+// is reported too; a day without working hours has no gaps. Otherwise only time
+// between slots is reported.
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetUntrackedGaps(dateStr string, minGapMinutes int) ([]Gap, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	until := from.AddDate(0, 0, 1)
+	schedule := a.settings.Get().Schedule
+	if schedule.Enabled {
+		var ok bool
+		if from, until, ok = schedule.windowOn(date); !ok {
+			return []Gap{}, nil
+		}
+	}
+	slots, err := a.database.GetOverlappingTimeSlots(0, from, until)
+	if err != nil {
+		return nil, err
+	}
+	return findGaps(slots, from, until, a.now(), time.Duration(minGapMinutes)*time.Minute, schedule.Enabled), nil
+}
+
+// defaultAnomalyHours is the slot length GetAnomalousSlots flags when no threshold is given
+const defaultAnomalyHours = 8
+
+// anomalyThreshold converts thresholdHours to a duration, 0 meaning defaultAnomalyHours
+func anomalyThreshold(thresholdHours float64) (time.Duration, error) {
+	if thresholdHours < 0 || math.IsNaN(thresholdHours) {
+		return 0, fmt.Errorf("threshold must not be negative")
+	}
+	if thresholdHours == 0 {
+		thresholdHours = defaultAnomalyHours
+	}
+	return time.Duration(thresholdHours * float64(time.Hour)), nil
+}
+
+// GetAnomalousSlots returns completed slots starting between the given dates
+// (inclusive) that lasted longer than thresholdHours, longest first, so forgotten
+// timers can be reviewed and corrected; 0 uses the default of 8 hours
+// The running slot is checked separately by GetAnomalousActiveSlot
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetAnomalousSlots(startStr string, endStr string, thresholdHours float64) ([]*models.TimeSlot, error) {
+	threshold, err := anomalyThreshold(thresholdHours)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := a.database.GetTimeSlotsInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return longSlots(slots, threshold), nil
+}
+
+// GetAnomalousActiveSlot returns the running slot if it has been running longer
+// than thresholdHours, nil otherwise; 0 uses the default of 8 hours
+func (a *App) GetAnomalousActiveSlot(thresholdHours float64) (*models.TimeSlot, error) {
+	threshold, err := anomalyThreshold(thresholdHours)
+	if err != nil {
+		return nil, err
+	}
+	if a.timer.GetElapsedTime() <= threshold {
+		return nil, nil
+	}
+	return a.timer.GetActiveSlot(), nil
+}
+
+// GetTasksOftenLeftRunning ranks the tasks whose work slots between the given
+// dates (inclusive) were auto-stopped or lasted longer than 8 hours, most often
+// first, to find tasks where the timer is regularly forgotten
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetTasksOftenLeftRunning(startStr string, endStr string) ([]LeftRunningTask, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := a.database.GetLeftRunningCounts(start, end, defaultAnomalyHours*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return rankLeftRunning(tasks), nil
+}
+
+// GetPeriodComparison compares the week or month containing a date with the previous one
+// date should be in format "2006-01-02" (YYYY-MM-DD), period should be "week" or "month"
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetPeriodComparison(dateStr string, period string, roundToMinutes int, mode string) (*Comparison, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	start, next, previous, err := periodBounds(date, period)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := a.database.GetTaskStatisticsForRange(start, next)
+	if err != nil {
+		return nil, err
+	}
+	before, err := a.database.GetTaskStatisticsForRange(previous, start)
+	if err != nil {
+		return nil, err
+	}
+	rounding.totals(current)
+	rounding.totals(before)
+
+	comparison := &Comparison{
+		Period:        period,
+		CurrentStart:  start,
+		PreviousStart: previous,
+		CurrentTotal:  sumStatistics(current),
+		PreviousTotal: sumStatistics(before),
+		Tasks:         compareTaskStatistics(current, before),
+	}
+	comparison.DeltaSeconds = comparison.CurrentTotal - comparison.PreviousTotal
+	comparison.PercentChange = percentChange(comparison.PreviousTotal, comparison.CurrentTotal)
+
+	return comparison, nil
+}
+
+// ExportDailyMarkdown returns a Markdown summary of a day for pasting into a standup
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportDailyMarkdown(dateStr string) (string, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return "", err
+	}
+	slots, err := a.database.GetTimeSlotsByDate(date)
+	if err != nil {
+		return "", err
+	}
+	return buildDailyMarkdown(date, slots), nil
+}
+
+// ExportIssueTimeLog returns the completed time between two dates (inclusive)
+// summed per external reference, one "#123: 2h 30m" line each, for logging
+// time back to issue trackers. Slots without a reference are listed as "unassigned".
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportIssueTimeLog(startStr string, endStr string) (string, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return "", err
+	}
+	slots, err := a.database.GetTimeSlotsInRange(start, end)
+	if err != nil {
+		return "", err
+	}
+	return buildIssueTimeLog(slots), nil
+}
+
+// ExportCSV returns the time slots between two dates (inclusive) as CSV
+// With snapshotActive, a running slot is exported with end = now and its live duration;
+// otherwise it keeps an empty end time. Either way it is marked in_progress.
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportCSV(startStr string, endStr string, snapshotActive bool) (string, error) {
+	rows, err := a.exportRows(startStr, endStr, snapshotActive)
+	if err != nil {
+		return "", err
+	}
+	return buildCSV(rows)
+}
+
+// ExportJSON returns the time slots between two dates (inclusive) as a JSON array
+// Active slots are handled as in ExportCSV
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportJSON(startStr string, endStr string, snapshotActive bool) (string, error) {
+	rows, err := a.exportRows(startStr, endStr, snapshotActive)
+	if err != nil {
+		return "", err
+	}
+	return buildJSON(rows)
+}
+
+// exportRows loads the slots of a date range for export
+// Dates are local; timestamps are written in the ExportTimeZone setting's zone
+func (a *App) exportRows(startStr string, endStr string, snapshotActive bool) ([]ExportRow, error) {
+	location, err := exportLocation(a.settings.Get().ExportTimeZone)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := a.database.GetTimeSlotsInRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return exportRows(slots, a.now(), snapshotActive, location), nil
+}
+
+// GetWeekdayTotals returns the seconds tracked on each day of the week, Monday first,
+// between two dates (inclusive)
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetWeekdayTotals(startStr string, endStr string, roundToMinutes int, mode string) ([7]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return [7]int64{}, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return [7]int64{}, err
+	}
+
+	slots, err := a.database.GetOverlappingTimeSlots(0, start, end)
+	if err != nil {
+		return [7]int64{}, err
+	}
+	totals := weekdayTotals(slots, start, end)
+	for i := range totals {
+		totals[i] = rounding.seconds(totals[i])
+	}
+	return totals, nil
+}
+
+// GetMonthlyReport returns per-task and per-day totals for a calendar month
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetMonthlyReport(year int, month int, roundToMinutes int, mode string) (*MonthlyReport, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("invalid month %d", month)
+	}
+
+	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	tasks, err := a.database.GetTaskTotals(monthStart, monthEnd, models.KindWork)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := a.database.GetDailyTotals(monthStart, monthEnd, models.KindWork)
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := a.database.GetTaskStatisticsForKind(monthStart, monthEnd, models.KindBreak)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		tasks[i].TotalSeconds = rounding.seconds(tasks[i].TotalSeconds)
+	}
+	report := buildMonthlyReport(monthStart, tasks, rounding.totals(daily))
+	report.BreakSeconds = sumStatistics(rounding.totals(breaks))
+	return report, nil
+}
+
+// GetYearlyWeeklyTotals returns the work time and top task of every ISO week of a
+// year, e.g. for a year in review; weeks without tracking are zero entries
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetYearlyWeeklyTotals(year int, roundToMinutes int, mode string) ([]WeekTotal, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	if year < 1 || year > 9999 {
+		return nil, fmt.Errorf("invalid year %d", year)
+	}
+
+	slots, err := a.database.GetTimeSlotsInRange(isoWeekStart(year), isoWeekStart(year+1))
+	if err != nil {
+		return nil, err
+	}
+
+	weeks := buildYearlyWeeklyTotals(year, slots)
+	for i := range weeks {
+		weeks[i].TotalSeconds = rounding.seconds(weeks[i].TotalSeconds)
+		weeks[i].TopTaskSeconds = rounding.seconds(weeks[i].TopTaskSeconds)
+	}
+	return weeks, nil
+}
+
+// GetTaskTrend returns the seconds tracked on one task per day ("2006-01-02")
+// between two dates (inclusive); days without time on the task are left out
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTaskTrend(taskName string, startStr string, endStr string, roundToMinutes int, mode string) (map[string]int64, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	taskName = normalizeTaskName(taskName)
+	if taskName == "" {
+		return nil, ErrEmptyTaskName
+	}
+
+	totals, err := a.database.GetTaskDailyTotals(taskName, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return rounding.totals(totals), nil
+}
+
+// GetWorkStatistics returns work time per task between two dates (inclusive)
+// with the work and break totals, so breaks don't inflate the work time
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetWorkStatistics(startStr string, endStr string, roundToMinutes int, mode string) (*WorkStatistics, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.database.GetTaskStatisticsForKind(start, end, models.KindWork)
+	if err != nil {
+		return nil, err
+	}
+	breaks, err := a.database.GetTaskStatisticsForKind(start, end, models.KindBreak)
+	if err != nil {
+		return nil, err
+	}
+	rounding.totals(tasks)
+	rounding.totals(breaks)
+
+	return &WorkStatistics{
+		Tasks:        tasks,
+		WorkSeconds:  sumStatistics(tasks),
+		BreakSeconds: sumStatistics(breaks),
+	}, nil
+}
+
+// GetTrackedDates returns the dates between two dates (inclusive) that have tracked time,
+// e.g. to mark days in a calendar
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetTrackedDates(startStr string, endStr string) ([]string, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	return a.database.GetTrackedDates(start, end)
+}
+
+// GetLifetimeStats returns the total tracked time and slot count across all history
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetLifetimeStats(roundToMinutes int, mode string) (*LifetimeStats, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	totalSeconds, slotCount, err := a.database.GetGrandTotal()
+	if err != nil {
+		return nil, err
+	}
+	firstStart, err := a.database.GetFirstStartTime()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &LifetimeStats{TotalSeconds: rounding.seconds(totalSeconds), SlotCount: slotCount}
+	if firstStart != nil {
+		stats.TrackingSince = firstStart.Format("2006-01-02")
+	}
+	return stats, nil
+}
+
+// GetTopTask returns the most tracked task and its total seconds in a date range
+// The task name is empty and the total zero when nothing was tracked
+// (Wails bound methods can only return one value besides the error, hence the struct)
+// dates should be in format "2006-01-02" (YYYY-MM-DD), both ends inclusive
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTopTask(startStr string, endStr string, roundToMinutes int, mode string) (*TopTask, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	taskName, totalSeconds, err := a.database.GetTopTask(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &TopTask{TaskName: taskName, TotalSeconds: rounding.seconds(totalSeconds)}, nil
+}
+
+// UpdateTimeSlot updates a time slot
+// startTime and endTime should be in RFC3339 format (ISO 8601)
+// endTime can be empty string for active slots
+func (a *App) UpdateTimeSlot(id int64, taskName string, startTimeStr string, endTimeStr string) error {
+	if err := a.checkTaskNameLength(taskName); err != nil {
+		return err
+	}
+	startTime, endTime, err := parseSlotTimes(startTimeStr, endTimeStr)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.validateTimeSlotEdit(id, startTime, endTime)
+	if err != nil {
+		return err
+	}
+	if !result.Valid {
+		return fmt.Errorf("invalid time slot: %s", strings.Join(result.Errors, "; "))
+	}
+
+	previous, err := a.database.GetTimeSlot(id)
+	if err != nil {
+		return err
+	}
+
+	// Editing the running slot goes through the timer so its elapsed time stays correct
+	activeSlot, err := a.timer.UpdateSlot(id, taskName, startTime, endTime)
+	if err != nil {
+		return err
+	}
+	if activeSlot != nil {
+		a.emit(EventTimerAdjusted, activeSlot)
+	}
+	a.slotsChanged(previous, &models.TimeSlot{StartTime: startTime, EndTime: endTime})
+	return nil
+}
+
+// TimelineEdit is a new start and end time for a slot dragged on the timeline
+// Times are in RFC3339 format; end_time is empty to keep the running slot active
+type TimelineEdit struct {
+	ID        int64  `json:"id"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// ApplyTimelineEdits moves and resizes several slots at once, recomputing their
+// durations. Either all edits are applied or none: any invalid edit or resulting
+// overlap rolls the whole batch back.
+func (a *App) ApplyTimelineEdits(edits []TimelineEdit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+
+	now := a.now()
+	seen := make(map[int64]bool, len(edits))
+	retimes := make([]SlotRetime, 0, len(edits))
+	changed := make([]*models.TimeSlot, 0, 2*len(edits))
+	for _, edit := range edits {
+		if seen[edit.ID] {
+			return fmt.Errorf("time slot %d is edited more than once", edit.ID)
+		}
+		seen[edit.ID] = true
+
+		startTime, endTime, err := parseSlotTimes(edit.StartTime, edit.EndTime)
+		if err != nil {
+			return fmt.Errorf("invalid times for time slot %d: %w", edit.ID, err)
+		}
+		if startTime.After(now) {
+			return fmt.Errorf("time slot %d: start time is in the future", edit.ID)
+		}
+		if endTime != nil {
+			if !endTime.After(startTime) {
+				return fmt.Errorf("time slot %d: end time must be after start time", edit.ID)
+			}
+			if endTime.After(now) {
+				return fmt.Errorf("time slot %d: end time is in the future", edit.ID)
+			}
+		}
+		retimes = append(retimes, SlotRetime{ID: edit.ID, StartTime: startTime, EndTime: endTime})
+
+		previous, err := a.database.GetTimeSlot(edit.ID)
+		if err != nil {
+			return err
+		}
+		changed = append(changed, previous, &models.TimeSlot{StartTime: startTime, EndTime: endTime})
+	}
+
+	activeSlot, err := a.timer.RetimeSlots(retimes)
+	if err != nil {
+		return err
+	}
+	if activeSlot != nil {
+		a.emit(EventTimerAdjusted, activeSlot)
+	}
+	a.slotsChanged(changed...)
+	return nil
+}
+
+// ValidateTimeSlotEdit checks an edit the way UpdateTimeSlot would, without writing
+// startTime and endTime should be in RFC3339 format (ISO 8601)
+// endTime can be empty string for active slots
+func (a *App) ValidateTimeSlotEdit(id int64, startTimeStr string, endTimeStr string) (*ValidationResult, error) {
+	startTime, endTime, err := parseSlotTimes(startTimeStr, endTimeStr)
+	if err != nil {
+		result := &ValidationResult{Errors: []string{err.Error()}, Warnings: []string{}, OverlappingIDs: []int64{}}
+		return result, nil
+	}
+	return a.validateTimeSlotEdit(id, startTime, endTime)
+}
+
+// parseDate parses a "2006-01-02" date as local midnight
+// Slots are bucketed by local day, so a UTC date would shift the day boundaries
+// by the UTC offset and attribute slots near midnight to the wrong day
+func parseDate(dateStr string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", dateStr, time.Local)
+}
+
+// parseDateRange parses an inclusive "2006-01-02" date range into [start, end)
+func parseDateRange(startStr string, endStr string) (time.Time, time.Time, error) {
+	start, err := parseDate(startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parseDate(endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", endStr, startStr)
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// parseSlotTimes parses RFC3339 start and optional end times
+func parseSlotTimes(startTimeStr string, endTimeStr string) (time.Time, *time.Time, error) {
+	startTime, err := time.Parse(time.RFC3339, startTimeStr)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	var endTime *time.Time
+	if endTimeStr != "" {
+		et, err := time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		endTime = &et
+	}
+
+	return startTime, endTime, nil
+}
+
+// SetTaskColor sets the display color for a task
+// color should be in format "#RRGGBB"; an empty string resets it to the default color
+func (a *App) SetTaskColor(taskName string, color string) error {
+	if color != "" && !isValidColor(color) {
+		return fmt.Errorf("invalid color %q, expected #RRGGBB", color)
+	}
+	if err := a.database.SetTaskColor(taskName, color); err != nil {
+		return err
+	}
+
+	// Colors, projects and rates show up in views of any date
+	a.dataChanged()
+	return nil
+}
+
+// GetTaskColors returns the display color for every known task
+// Tasks without a user-chosen color get a deterministic default color
+func (a *App) GetTaskColors() (map[string]string, error) {
+	taskNames, err := a.database.GetTaskNames()
+	if err != nil {
+		return nil, err
+	}
+	colors, err := a.database.GetTaskColors()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, taskName := range taskNames {
+		if _, ok := colors[taskName]; !ok {
+			colors[taskName] = defaultTaskColor(taskName)
+		}
+	}
+	return colors, nil
+}
+
+// SetTaskProject assigns a task to a project for grouping, e.g. in GetTreemapData
+// An empty project removes the assignment
+func (a *App) SetTaskProject(taskName string, project string) error {
+	taskName = normalizeTaskName(taskName)
+	if taskName == "" {
+		return ErrEmptyTaskName
+	}
+	if err := a.database.SetTaskProject(taskName, normalizeTaskName(project)); err != nil {
+		return err
+	}
+
+	a.dataChanged()
+	return nil
+}
+
+// GetTaskProjects returns the project of every task assigned to one
+func (a *App) GetTaskProjects() (map[string]string, error) {
+	return a.database.GetTaskProjects()
+}
+
+// SetTaskRate sets the hourly rate of a task for the earnings report
+// currency is an ISO 4217 code such as "USD" or "EUR"
+func (a *App) SetTaskRate(taskName string, ratePerHour float64, currency string) error {
+	taskName = normalizeTaskName(taskName)
+	if taskName == "" {
+		return ErrEmptyTaskName
+	}
+	if ratePerHour <= 0 || math.IsInf(ratePerHour, 0) || math.IsNaN(ratePerHour) {
+		return fmt.Errorf("rate must be a positive number")
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isValidCurrency(currency) {
+		return fmt.Errorf("invalid currency %q, expected a three-letter code such as USD", currency)
+	}
+	if err := a.database.SetTaskRate(taskName, ratePerHour, currency); err != nil {
+		return err
+	}
+
+	a.dataChanged()
+	return nil
+}
+
+// RemoveTaskRate removes the hourly rate of a task
+func (a *App) RemoveTaskRate(taskName string) error {
+	if err := a.database.DeleteTaskRate(normalizeTaskName(taskName)); err != nil {
+		return err
+	}
+
+	a.dataChanged()
+	return nil
+}
+
+// GetTaskRates returns the hourly rate of every task that has one
+func (a *App) GetTaskRates() (map[string]TaskRate, error) {
+	return a.database.GetTaskRates()
+}
+
+// CreateRecurringTask adds a task that happens at a fixed time, e.g. a daily
+// standup from "09:15" to "09:30". Weekdays are time.Weekday numbers (Sunday = 0),
+// empty for every day. The "log" action records a completed slot once the end
+// time has passed, "prompt" asks to start the task when it comes up.
+// An occurrence already over today is skipped rather than logged retroactively
+func (a *App) CreateRecurringTask(task RecurringTask) (*RecurringTask, error) {
+	if err := task.normalize(); err != nil {
+		return nil, err
+	}
+	if err := a.checkTaskNameLength(task.TaskName); err != nil {
+		return nil, err
+	}
+
+	now := a.now()
+	task.LastRun = ""
+	if task.missed(now) {
+		task.LastRun = now.Format("2006-01-02")
+	}
+
+	id, err := a.database.CreateRecurringTask(task)
+	if err != nil {
+		return nil, err
+	}
+	task.ID = id
+	return &task, nil
+}
+
+// UpdateRecurringTask changes the name, times, weekdays or action of a recurring task
+func (a *App) UpdateRecurringTask(task RecurringTask) error {
+	if err := task.normalize(); err != nil {
+		return err
+	}
+	if err := a.checkTaskNameLength(task.TaskName); err != nil {
+		return err
+	}
+	return a.database.UpdateRecurringTask(task)
+}
+
+// DeleteRecurringTask stops a recurring task; slots it already logged are kept
+func (a *App) DeleteRecurringTask(id int64) error {
+	return a.database.DeleteRecurringTask(id)
+}
+
+// ListRecurringTasks returns all recurring tasks ordered by start time
+func (a *App) ListRecurringTasks() ([]RecurringTask, error) {
+	return a.database.GetRecurringTasks()
+}
+
+// GetEarningsReport returns what the work between two dates (inclusive) earned
+// per task at its hourly rate, with a total per currency; breaks aren't billed
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetEarningsReport(startStr string, endStr string) (*EarningsReport, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := a.database.GetTaskTotals(start, end, models.KindWork)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := a.database.GetTaskRates()
+	if err != nil {
+		return nil, err
+	}
+	return buildEarningsReport(tasks, rates), nil
+}
+
+// GetTreemapData returns the completed time between two dates (inclusive) as a
+// project → task hierarchy for treemap or sunburst charts
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTreemapData(startStr string, endStr string, roundToMinutes int, mode string) (*TreemapNode, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := a.database.GetTaskTotals(start, end, "")
+	if err != nil {
+		return nil, err
+	}
+	projects, err := a.database.GetTaskProjects()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].TotalSeconds = rounding.seconds(tasks[i].TotalSeconds)
+	}
+	return buildTreemap(tasks, projects), nil
+}
+
+// NotificationBackendStatus reports whether desktop notifications work and why not
+func (a *App) NotificationBackendStatus() NotificationBackendStatus {
+	if a.notificationManager == nil {
+		return detectNotificationBackend()
+	}
+	return a.notificationManager.BackendStatus()
+}
+
+// GetSettings returns the current application settings
+func (a *App) GetSettings() Settings {
+	return a.settings.Get()
+}
+
+// UpdateSettings replaces the application settings and saves them to disk
+func (a *App) UpdateSettings(settings Settings) error {
+	switch settings.TrayClickAction {
+	case TrayClickNone, TrayClickToggleWindow, TrayClickToggleTimer:
+	default:
+		return fmt.Errorf("unknown tray click action %q", settings.TrayClickAction)
+	}
+	if err := settings.Schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	if settings.StillWorkingIntervalMinutes < 0 || settings.StillWorkingGraceMinutes < 0 {
+		return fmt.Errorf("still working interval and grace period must not be negative")
+	}
+	if settings.MergeGapMinutes < 0 {
+		return fmt.Errorf("merge gap must not be negative")
+	}
+	if settings.RoundStopToMinutes < 0 {
+		return fmt.Errorf("stop rounding must not be negative")
+	}
+	if settings.ReopenWindowMinutes < 0 {
+		return fmt.Errorf("reopen window must not be negative")
+	}
+	if settings.IdleThresholdMinutes < 0 {
+		return fmt.Errorf("idle threshold must not be negative")
+	}
+	if settings.ConfirmStopAfterMinutes < 0 {
+		return fmt.Errorf("stop confirmation threshold must not be negative")
+	}
+	if settings.UndoStartSeconds < 0 {
+		return fmt.Errorf("undo start window must not be negative")
+	}
+	if settings.ToggleDebounceMs < 0 {
+		return fmt.Errorf("toggle debounce window must not be negative")
+	}
+	if settings.DailyGoalMinutes < 0 || settings.DailyGoalMinutes > 24*60 {
+		return fmt.Errorf("daily goal must be between 0 and 24 hours")
+	}
+	if err := validateContextSettings(&settings); err != nil {
+		return err
+	}
+	if !isValidUrgency(settings.NotificationUrgency) {
+		return fmt.Errorf("unknown notification urgency %q", settings.NotificationUrgency)
+	}
+	if settings.MaxHistoryDays < 0 {
+		return fmt.Errorf("max history days must not be negative")
+	}
+	if _, err := exportLocation(settings.ExportTimeZone); err != nil {
+		return err
+	}
+	if settings.ActivityCheckMinutes < 1 {
+		return fmt.Errorf("activity check minutes must be at least 1")
+	}
+	if settings.MaxTaskNameLength < 0 {
+		return fmt.Errorf("max task name length must not be negative")
+	}
+	settings.DefaultTaskName = normalizeTaskName(settings.DefaultTaskName)
+	if settings.MaxTaskNameLength > 0 && utf8.RuneCountInString(settings.DefaultTaskName) > settings.MaxTaskNameLength {
+		return fmt.Errorf("default task name: %w", ErrTaskNameTooLong)
+	}
+	if settings.Window != nil {
+		if err := validateWindowState(*settings.Window); err != nil {
+			return fmt.Errorf("invalid window state: %w", err)
+		}
+	}
+	if err := a.settings.Set(settings); err != nil {
+		return err
+	}
+
+	a.timer.SetStopRounding(time.Duration(settings.RoundStopToMinutes) * time.Minute)
+	return nil
+}
+
+// SuggestTasks returns up to limit existing task names matching the typed prefix,
+// best matches first
+func (a *App) SuggestTasks(prefix string, limit int) ([]string, error) {
+	query := strings.TrimSpace(prefix)
+	if query == "" {
+		return []string{}, nil
+	}
+
+	taskNames, err := a.database.GetRecentTaskNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return rankTaskSuggestions(taskNames, query, limit), nil
+}
+
+// PredictNextTask suggests the task probably worked on now, based on which task was
+// most often tracked around the current time of day over the past weeks
+// The task name is empty when history doesn't point at one task clearly enough
+func (a *App) PredictNextTask() (*TaskPrediction, error) {
+	now := a.now()
+	slots, err := a.database.GetOverlappingTimeSlots(0, now.AddDate(0, 0, -predictionDays).Add(-predictionWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	prediction := predictTask(slots, now)
+	return &prediction, nil
+}
+
+// FindSimilarTaskNames groups task names that look like variants of each other,
+// e.g. "Code review" and "code-review", so they can be merged
+// threshold is the minimum similarity between 0 and 1, where 1 only matches names
+// differing in case; 0.8 is a reasonable start. Only groups of two or more are returned.
+func (a *App) FindSimilarTaskNames(threshold float64) ([][]string, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("threshold must be greater than 0 and at most 1")
+	}
+
+	taskNames, err := a.database.GetTaskNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return groupSimilarNames(taskNames, threshold), nil
+}
+
+// ConfirmStillWorking answers a pending "are you still working?" prompt
+func (a *App) ConfirmStillWorking() {
+	if a.notificationManager == nil {
+		return
+	}
+	a.notificationManager.ConfirmStillWorking()
+}
+
+// GetIdlePeriod returns the idle period waiting for ResolveIdlePeriod, or nil
+func (a *App) GetIdlePeriod() *IdlePeriod {
+	if a.idleDetector == nil {
+		return nil
+	}
+	return a.idleDetector.Pending()
+}
+
+// ResolveIdlePeriod decides what happens to the time spent idle while the timer ran:
+// "keep" leaves it tracked, "discard" excludes it from the running slot and
+// "reassign" records it for reassignTask, e.g. "Meeting away from desk", while the
+// running task continues afterwards. The running slot is returned.
+func (a *App) ResolveIdlePeriod(action string, reassignTask string) (*models.TimeSlot, error) {
+	if a.idleDetector == nil {
+		return nil, fmt.Errorf("no idle period to resolve")
+	}
+	if action == IdleReassign {
+		reassignTask = normalizeTaskName(reassignTask)
+		if reassignTask == "" {
+			return nil, ErrEmptyTaskName
+		}
+		if err := a.checkTaskNameLength(reassignTask); err != nil {
+			return nil, err
+		}
+	}
+
+	slot, err := a.idleDetector.Resolve(action, reassignTask)
+	if err != nil {
+		return nil, err
+	}
+	if action != IdleKeep {
+		a.emit(EventTimerAdjusted, slot)
+		a.slotsChanged(slot)
+	}
+	return slot, nil
+}
+
+// ArchiveBefore moves completed time slots that started before the given date
+// into a separate SQLite file and returns how many were moved
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ArchiveBefore(dateStr string, archivePath string) (int, error) {
+	cutoff, err := parseDate(dateStr)
+	if err != nil {
+		return 0, err
+	}
+	moved, err := a.database.ArchiveBefore(cutoff, archivePath)
+	if err != nil {
+		return 0, err
+	}
+
+	if moved > 0 {
+		a.dataChanged()
+	}
+	return moved, nil
+}
+
+// ExportDatabaseFile saves a consistent copy of the whole SQLite database to destPath,
+// e.g. to open it in external tools; an existing file at destPath is replaced
+// Encrypted fields stay encrypted in the copy
+func (a *App) ExportDatabaseFile(destPath string) error {
+	return a.database.ExportFile(destPath)
+}
+
+// GetDatabaseInfo returns the database file path and size, how many time slots it
+// holds, whether one is running, the first and last tracked dates and whether the
+// file is in a fallback or temporary data directory
+func (a *App) GetDatabaseInfo() (*DBInfo, error) {
+	return a.database.Info()
+}
+
+// RecalculateDurations repairs completed slots whose stored duration doesn't match
+// their start, end and paused time, e.g. legacy rows with a zero duration, and
+// returns how many were fixed
+func (a *App) RecalculateDurations() (int64, error) {
+	fixed, err := a.database.RecalculateDurations()
+	if err != nil {
+		return 0, err
+	}
+
+	if fixed > 0 {
+		a.dataChanged()
+	}
+	return fixed, nil
+}
+
+// QueryArchive returns time slots from an archive file between two dates (inclusive)
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) QueryArchive(archivePath string, startStr string, endStr string) ([]*models.TimeSlot, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	return a.database.QueryArchive(archivePath, start, end)
+}
+
+// IsDatabaseLocked reports whether the database is encrypted and waiting for the passphrase
+func (a *App) IsDatabaseLocked() bool {
+	return a.database.IsLocked()
+}
+
+// UnlockDatabase unlocks an encrypted database; tracking doesn't work until it succeeds
+func (a *App) UnlockDatabase(passphrase string) error {
+	if !a.database.IsLocked() {
+		return nil
+	}
+	if err := a.database.Unlock(passphrase); err != nil {
+		return err
+	}
+	if err := a.loadActiveSlot(); err != nil {
+		return err
+	}
+	a.recoverState()
+
+	if recovered := a.GetRecoveredSlot(); recovered != nil {
+		a.emit(EventTimerRecovered, recovered)
+	}
+	// Views loaded while locked had nothing to show
+	a.dataChanged()
+	return nil
+}
+
+// EnableEncryption encrypts task names with a key derived from passphrase
+// The passphrase will be required on every start and can't be recovered
+func (a *App) EnableEncryption(passphrase string) error {
+	return a.database.EnableEncryption(passphrase)
+}
+
+// SetMaxHistoryDays limits the kept history to the given number of days, 0 keeps everything
+// Older completed slots are deleted on the next start
+func (a *App) SetMaxHistoryDays(days int) error {
+	if days < 0 {
+		return fmt.Errorf("max history days must not be negative")
+	}
+	return a.settings.Update(func(s *Settings) {
+		s.MaxHistoryDays = days
+	})
+}
+
+// SetSystrayEnabled turns the tray icon on or off
+// The change takes effect after the app is restarted
+func (a *App) SetSystrayEnabled(enabled bool) error {
+	return a.settings.Update(func(s *Settings) {
+		s.DisableSystray = !enabled
+	})
+}
+
+// SetDefaultTaskName sets the task name used when the timer starts without one
+// An empty name clears the default
+func (a *App) SetDefaultTaskName(name string) error {
+	name = normalizeTaskName(name)
+	if err := a.checkTaskNameLength(name); err != nil {
+		return err
+	}
+	return a.settings.Update(func(s *Settings) {
+		s.DefaultTaskName = name
+	})
+}
+
+// SetExportTimeZone sets the time zone of CSV and JSON export timestamps:
+// "local" (the default), "UTC" or an IANA name such as "America/New_York"
+// Stored times don't change, only how exports write them
+func (a *App) SetExportTimeZone(timeZone string) error {
+	timeZone = strings.TrimSpace(timeZone)
+	if _, err := exportLocation(timeZone); err != nil {
+		return err
+	}
+	return a.settings.Update(func(s *Settings) {
+		s.ExportTimeZone = timeZone
+	})
+}
+
+// DuplicateTimeSlot copies a completed slot's task and duration to a new start time
+// newStart should be in RFC3339 format (ISO 8601)
+func (a *App) DuplicateTimeSlot(id int64, newStartStr string) (*models.TimeSlot, error) {
+	newStart, err := time.Parse(time.RFC3339, newStartStr)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := a.database.GetTimeSlot(id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("time slot %d %w", id, ErrNotFound)
+	}
+	if source.IsActive() {
+		return nil, fmt.Errorf("cannot duplicate an active time slot")
+	}
+
+	newEnd := newStart.Add(time.Duration(source.DurationSeconds) * time.Second)
+	if newEnd.After(a.now()) {
+		return nil, fmt.Errorf("duplicated slot would end in the future")
+	}
+
+	slot, err := a.database.CreateCompletedTimeSlot(source.TaskName, newStart, newEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	a.slotsChanged(slot)
+	return slot, nil
+}
+
+// DeleteTimeSlot deletes a time slot
+func (a *App) DeleteTimeSlot(id int64) error {
+	slot, err := a.database.GetTimeSlot(id)
+	if err != nil {
+		return err
+	}
+	if err := a.database.DeleteTimeSlot(id); err != nil {
+		return err
+	}
+
+	a.slotsChanged(slot)
+	return nil
 }
 
-// Close closes the database connection
+// Close stops the background goroutines and the timer and closes the database connection
 func (a *App) Close() error {
+	a.stopWorkers()
+	a.timer.Close()
 	return a.database.Close()
 }
 
@@ -0,0 +1,640 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for App.setClock
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestApp returns an App backed by an in-memory database and default settings
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+
+	db, err := NewDatabaseAt(":memory:")
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	settings := &SettingsManager{
+		settings: DefaultSettings(),
+		path:     filepath.Join(t.TempDir(), "settings.json"),
+	}
+	a, err := NewAppWithStore(db, settings)
+	if err != nil {
+		db.Close()
+		t.Fatalf("NewAppWithStore: %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+// newClockedTestApp is newTestApp running on a fake clock set to now
+func newClockedTestApp(t *testing.T, now time.Time) (*App, *fakeClock) {
+	t.Helper()
+
+	a := newTestApp(t)
+	clock := newFakeClock(now)
+	a.setClock(clock.Now)
+	return a, clock
+}
+
+// fakeAppStore runs the slots and statistics of an App on a fakeStore; the
+// task settings and maintenance parts come from an in-memory database
+type fakeAppStore struct {
+	*fakeStore
+	TaskSettingsStore
+	MaintenanceStore
+}
+
+// newFakeTestApp is newClockedTestApp with the slots and statistics on a fakeStore
+func newFakeTestApp(t *testing.T, now time.Time) (*App, *fakeClock, *fakeStore) {
+	t.Helper()
+
+	db, err := NewDatabaseAt(":memory:")
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	store := newFakeStore()
+	settings := &SettingsManager{
+		settings: DefaultSettings(),
+		path:     filepath.Join(t.TempDir(), "settings.json"),
+	}
+	a, err := NewAppWithStore(fakeAppStore{store, db, db}, settings)
+	if err != nil {
+		db.Close()
+		t.Fatalf("NewAppWithStore: %v", err)
+	}
+	t.Cleanup(func() { a.Close() })
+	clock := newFakeClock(now)
+	a.setClock(clock.Now)
+	return a, clock, store
+}
+
+func TestTrackingDayIntegration(t *testing.T) {
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a, clock := newClockedTestApp(t, day)
+
+	design, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(25 * time.Minute)
+	if got := a.GetElapsedTime(); got != int64((25 * time.Minute).Seconds()) {
+		t.Errorf("elapsed = %ds, want %ds", got, int64((25 * time.Minute).Seconds()))
+	}
+
+	// Switching tasks stops the running slot at the current time
+	if _, err := a.StartTimer("Review"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	stopped, err := a.StopTimer()
+	if err != nil {
+		t.Fatalf("StopTimer: %v", err)
+	}
+	if stopped == nil || stopped.TaskName != "Review" {
+		t.Fatalf("stopped slot = %+v, want Review", stopped)
+	}
+	if a.timer.IsRunning() {
+		t.Error("timer is still running after StopTimer")
+	}
+
+	stats, err := a.GetTaskStatistics("2026-03-10", 0, "")
+	if err != nil {
+		t.Fatalf("GetTaskStatistics: %v", err)
+	}
+	want := map[string]int64{"Design": 25 * 60, "Review": 10 * 60}
+	if len(stats) != len(want) {
+		t.Errorf("statistics = %v, want %v", stats, want)
+	}
+	for task, seconds := range want {
+		if stats[task] != seconds {
+			t.Errorf("%s = %ds, want %ds", task, stats[task], seconds)
+		}
+	}
+
+	slots, err := a.GetTimeSlotsByDate("2026-03-10")
+	if err != nil {
+		t.Fatalf("GetTimeSlotsByDate: %v", err)
+	}
+	if len(slots) != 2 {
+		t.Fatalf("got %d slots, want 2", len(slots))
+	}
+	byID := make(map[int64]int)
+	for i, slot := range slots {
+		byID[slot.ID] = i
+	}
+	first := slots[byID[design.ID]]
+	if !first.StartTime.Equal(day) || first.EndTime == nil || !first.EndTime.Equal(day.Add(25*time.Minute)) {
+		t.Errorf("Design slot = %v - %v, want %v - %v", first.StartTime, first.EndTime, day, day.Add(25*time.Minute))
+	}
+	second := slots[byID[stopped.ID]]
+	if !second.StartTime.Equal(day.Add(25*time.Minute)) || second.DurationSeconds != 10*60 {
+		t.Errorf("Review slot starts %v and lasts %ds", second.StartTime, second.DurationSeconds)
+	}
+
+	if other, err := a.GetTaskStatistics("2026-03-11", 0, ""); err != nil || len(other) != 0 {
+		t.Errorf("next day statistics = %v, %v, want none", other, err)
+	}
+}
+
+func TestStartTimerWithEstimateDebounced(t *testing.T) {
+	a, _, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	first, err := a.StartTimerWithEstimate("Design", 30)
+	if err != nil {
+		t.Fatalf("StartTimerWithEstimate: %v", err)
+	}
+
+	// A second start within the debounce window must not re-estimate the running slot
+	second, err := a.StartTimerWithEstimate("Review", 90)
+	if err != nil {
+		t.Fatalf("debounced StartTimerWithEstimate: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Fatalf("debounced start returned slot %d, want running slot %d", second.ID, first.ID)
+	}
+
+	active := a.timer.GetActiveSlot()
+	if active.TaskName != "Design" {
+		t.Errorf("task name = %q, want %q", active.TaskName, "Design")
+	}
+	if want := int64((30 * time.Minute).Seconds()); active.EstimateSeconds != want {
+		t.Errorf("estimate = %ds, want %ds", active.EstimateSeconds, want)
+	}
+}
+
+func TestStartTimerWithEstimateAfterDebounce(t *testing.T) {
+	a, _, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	if _, err := a.StartTimerWithEstimate("Design", 30); err != nil {
+		t.Fatalf("StartTimerWithEstimate: %v", err)
+	}
+	a.lastToggle = time.Time{}
+
+	slot, err := a.StartTimerWithEstimate("Review", 90)
+	if err != nil {
+		t.Fatalf("StartTimerWithEstimate: %v", err)
+	}
+	if slot.TaskName != "Review" {
+		t.Errorf("task name = %q, want %q", slot.TaskName, "Review")
+	}
+	if want := int64((90 * time.Minute).Seconds()); slot.EstimateSeconds != want {
+		t.Errorf("estimate = %ds, want %ds", slot.EstimateSeconds, want)
+	}
+}
+
+func TestStopRoundingSetting(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	settings := a.GetSettings()
+	settings.RoundStopToMinutes = 15
+	if err := a.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	slot, err := a.StartTimer("Client call")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(38 * time.Minute)
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+
+	stored := store.get(slot.ID)
+	if !stored.StartTime.Equal(slot.StartTime) {
+		t.Errorf("start = %v, want %v", stored.StartTime, slot.StartTime)
+	}
+	if stored.DurationSeconds != 45*60 || !stored.EndTime.Equal(slot.StartTime.Add(45*time.Minute)) {
+		t.Errorf("stored slot ends %v after %ds, want 45 minutes", stored.EndTime, stored.DurationSeconds)
+	}
+
+	settings.RoundStopToMinutes = -5
+	if err := a.UpdateSettings(settings); err == nil {
+		t.Error("UpdateSettings accepted a negative stop rounding")
+	}
+}
+
+func TestStopTimerAndGetTodayStatsAsksForConfirmation(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	if err := a.settings.Update(func(s *Settings) { s.ConfirmStopAfterMinutes = 60 }); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+
+	slot, err := a.StartTimer("Report")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(90 * time.Minute)
+	if _, err := a.StopTimerAndGetTodayStats(0, ""); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("StopTimerAndGetTodayStats = %v, want ErrConfirmationRequired", err)
+	}
+	if !a.GetTimerState().Running || !store.get(slot.ID).IsActive() {
+		t.Fatal("an unconfirmed stop stopped the timer")
+	}
+
+	result, err := a.StopTimerAndGetTodayStatsConfirmed(0, "")
+	if err != nil {
+		t.Fatalf("StopTimerAndGetTodayStatsConfirmed: %v", err)
+	}
+	if result.Slot == nil || result.Slot.ID != slot.ID || result.Statistics["Report"] != 90*60 {
+		t.Errorf("result = %+v, want the 90m Report slot in the statistics", result)
+	}
+	if a.GetTimerState().Running {
+		t.Error("timer still runs after a confirmed stop")
+	}
+}
+
+func TestUpdateActiveSlotStartRecomputesElapsed(t *testing.T) {
+	a, clock, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	slot, err := a.StartTimer("Desgin")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+
+	// Fix the typo and say the work really began 20 minutes earlier
+	newStart := slot.StartTime.Add(-20 * time.Minute)
+	if err := a.UpdateTimeSlot(slot.ID, "Design", newStart.Format(time.RFC3339), ""); err != nil {
+		t.Fatalf("UpdateTimeSlot: %v", err)
+	}
+
+	state := a.GetTimerState()
+	if !state.Running || state.Slot.ID != slot.ID {
+		t.Fatalf("timer state = %+v, want the edited slot still running", state)
+	}
+	if state.Slot.TaskName != "Design" || !state.Slot.StartTime.Equal(newStart) {
+		t.Errorf("active slot = %q from %v, want %q from %v", state.Slot.TaskName, state.Slot.StartTime, "Design", newStart)
+	}
+	if want := int64((30 * time.Minute).Seconds()); state.ElapsedSeconds != want || a.GetElapsedTime() != want {
+		t.Errorf("elapsed = %ds (GetElapsedTime %ds), want %ds", state.ElapsedSeconds, a.GetElapsedTime(), want)
+	}
+
+	clock.Advance(5 * time.Minute)
+	stopped, err := a.StopTimerConfirmed()
+	if err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	if stopped.DurationSeconds != int64((35 * time.Minute).Seconds()) {
+		t.Errorf("stopped duration = %ds, want %ds", stopped.DurationSeconds, int64((35 * time.Minute).Seconds()))
+	}
+}
+
+func TestUpdateActiveSlotWithEndStopsTimer(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	slot, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(30 * time.Minute)
+
+	end := slot.StartTime.Add(20 * time.Minute)
+	if err := a.UpdateTimeSlot(slot.ID, "Design", slot.StartTime.Format(time.RFC3339), end.Format(time.RFC3339)); err != nil {
+		t.Fatalf("UpdateTimeSlot: %v", err)
+	}
+	if state := a.GetTimerState(); state.Running {
+		t.Errorf("timer still runs %+v after the slot got an end time", state.Slot)
+	}
+	stored := store.get(slot.ID)
+	if stored.DurationSeconds != 1200 {
+		t.Errorf("duration = %ds, want 1200s", stored.DurationSeconds)
+	}
+}
+
+func TestTaskNameLengthLimit(t *testing.T) {
+	a, clock := newClockedTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	// Multi-byte characters: the limit counts characters, not bytes
+	atLimit := strings.Repeat("é", 255)
+	overLimit := atLimit + "x"
+
+	if _, err := a.StartTimer(overLimit); !errors.Is(err, ErrTaskNameTooLong) {
+		t.Fatalf("StartTimer with 256 characters = %v, want ErrTaskNameTooLong", err)
+	}
+	if state := a.GetTimerState(); state.Running {
+		t.Fatal("a rejected name started the timer")
+	}
+
+	slot, err := a.StartTimer(atLimit)
+	if err != nil {
+		t.Fatalf("StartTimer with 255 characters: %v", err)
+	}
+	if err := a.RenameActiveSlot(overLimit); !errors.Is(err, ErrTaskNameTooLong) {
+		t.Errorf("RenameActiveSlot with 256 characters = %v, want ErrTaskNameTooLong", err)
+	}
+	if got := a.GetTimerState().Slot.TaskName; got != atLimit {
+		t.Errorf("task name changed to %d characters after a rejected rename", len([]rune(got)))
+	}
+
+	clock.Advance(time.Hour)
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	start := slot.StartTime.Format(time.RFC3339)
+	end := slot.StartTime.Add(time.Hour).Format(time.RFC3339)
+	if err := a.UpdateTimeSlot(slot.ID, overLimit, start, end); !errors.Is(err, ErrTaskNameTooLong) {
+		t.Errorf("UpdateTimeSlot with 256 characters = %v, want ErrTaskNameTooLong", err)
+	}
+
+	// Imported entries over the limit are skipped, the rest imported
+	data := fmt.Sprintf(`[
+		{"task": %q, "start": "2026-03-09T09:00:00Z", "end": "2026-03-09T10:00:00Z"},
+		{"task": %q, "start": "2026-03-09T11:00:00Z", "end": "2026-03-09T12:00:00Z"}
+	]`, atLimit, overLimit)
+	if n, err := a.ImportGeneric(data, ImportFormatGeneric); err != nil || n != 1 {
+		t.Errorf("ImportGeneric = %d, %v, want 1 entry imported", n, err)
+	}
+}
+
+func TestTaskNameLengthLimitDisabled(t *testing.T) {
+	a := newTestApp(t)
+	settings := a.GetSettings()
+	settings.MaxTaskNameLength = 0
+	if err := a.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	if _, err := a.StartTimer(strings.Repeat("a", 1000)); err != nil {
+		t.Errorf("StartTimer without a limit: %v", err)
+	}
+}
+
+func TestSessionBreakdownAcrossPauses(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	slot, err := a.StartTimer("Write")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	// Work 10m, pause 5m, work 15m, pause 3m, work 7m
+	for _, step := range []struct {
+		work, pause time.Duration
+	}{{10 * time.Minute, 5 * time.Minute}, {15 * time.Minute, 3 * time.Minute}} {
+		clock.Advance(step.work)
+		if err := a.PauseTimer(); err != nil {
+			t.Fatalf("PauseTimer: %v", err)
+		}
+		clock.Advance(step.pause)
+		if err := a.ResumeTimer(); err != nil {
+			t.Fatalf("ResumeTimer: %v", err)
+		}
+	}
+	clock.Advance(7 * time.Minute)
+
+	checkBreakdown := func(when string, active, paused, wall time.Duration) {
+		t.Helper()
+		got, err := a.GetSessionBreakdown(slot.ID)
+		if err != nil {
+			t.Fatalf("GetSessionBreakdown: %v", err)
+		}
+		want := SessionBreakdown{
+			ActiveSeconds: int64(active.Seconds()),
+			PausedSeconds: int64(paused.Seconds()),
+			WallSeconds:   int64(wall.Seconds()),
+		}
+		if *got != want {
+			t.Errorf("%s: breakdown = %+v, want %+v", when, *got, want)
+		}
+	}
+	checkBreakdown("running", 32*time.Minute, 8*time.Minute, 40*time.Minute)
+	if got := a.GetElapsedTime(); got != int64((32 * time.Minute).Seconds()) {
+		t.Errorf("elapsed = %ds, want only the active 32m", got)
+	}
+
+	// A pause in progress counts as paused time
+	if err := a.PauseTimer(); err != nil {
+		t.Fatalf("PauseTimer: %v", err)
+	}
+	clock.Advance(4 * time.Minute)
+	checkBreakdown("paused", 32*time.Minute, 12*time.Minute, 44*time.Minute)
+
+	// Stopping while paused ends the slot when the pause began
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	checkBreakdown("stopped", 32*time.Minute, 8*time.Minute, 40*time.Minute)
+	stored := store.get(slot.ID)
+	if stored.DurationSeconds != 32*60 || stored.PausedSeconds != 8*60 {
+		t.Errorf("stored duration and pause = %ds, %ds, want 1920s, 480s", stored.DurationSeconds, stored.PausedSeconds)
+	}
+
+	if _, err := a.GetSessionBreakdown(slot.ID + 100); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetSessionBreakdown for a missing slot = %v, want ErrNotFound", err)
+	}
+}
+
+// TestCloseStopsWorkers starts the background goroutines and checks that Close
+// waits for all of them, including the tray still waiting for the UI to start
+func TestCloseStopsWorkers(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	a := newTestApp(t)
+	if _, err := a.StartTimer("Task"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+
+	a.startWorkers(context.Background())
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("background workers are still running after Close")
+	}
+
+	// Nothing started by the app outlives it, whether or not it went through goWorker
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, %d before the app started", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// countSlots returns how many slots start on date
+func countSlots(t *testing.T, a *App, date string) int {
+	t.Helper()
+
+	slots, err := a.GetTimeSlotsByDate(date)
+	if err != nil {
+		t.Fatalf("GetTimeSlotsByDate: %v", err)
+	}
+	return len(slots)
+}
+
+func TestRapidDoubleToggleCreatesOneSlot(t *testing.T) {
+	a, clock, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	if got := a.GetSettings().ToggleDebounceMs; got != 300 {
+		t.Fatalf("default debounce = %dms, want 300ms", got)
+	}
+
+	// A double click on start
+	first, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(80 * time.Millisecond)
+	second, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("second start returned slot %d, want the running %d", second.ID, first.ID)
+	}
+
+	// A key repeat turning the start into a stop
+	clock.Advance(150 * time.Millisecond)
+	slot, err := a.StopTimerConfirmed()
+	if err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	if slot == nil || slot.ID != first.ID || !a.GetTimerState().Running {
+		t.Errorf("a stop 150ms after the start returned %v and stopped the timer", slot)
+	}
+	if n := countSlots(t, a, "2026-03-10"); n != 1 {
+		t.Fatalf("%d slots after rapid toggles, want 1", n)
+	}
+
+	// The window counts from the last accepted toggle, so this stop goes through
+	clock.Advance(150 * time.Millisecond)
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	if a.GetTimerState().Running {
+		t.Error("a stop 300ms after the start was ignored")
+	}
+}
+
+func TestConcurrentDoubleToggleCreatesOneSlot(t *testing.T) {
+	a, _, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+
+	// The tray and a hotkey firing at the same moment
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.StartTimer("Design"); err != nil {
+				t.Errorf("StartTimer: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := countSlots(t, a, "2026-03-10"); n != 1 {
+		t.Errorf("%d slots after simultaneous starts, want 1", n)
+	}
+}
+
+func TestToggleDebounceWindowSetting(t *testing.T) {
+	a, clock, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	setDebounce := func(ms int) {
+		t.Helper()
+		if err := a.settings.Update(func(s *Settings) { s.ToggleDebounceMs = ms }); err != nil {
+			t.Fatalf("Update settings: %v", err)
+		}
+	}
+
+	setDebounce(1000)
+	if _, err := a.StartTimer("Design"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(600 * time.Millisecond)
+	if _, err := a.StartTimer("Review"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if got := a.GetActiveTaskName(); got != "Design" {
+		t.Errorf("a start inside a 1s window switched the task to %q", got)
+	}
+
+	// 0 turns debouncing off
+	setDebounce(0)
+	if _, err := a.StartTimer("Review"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if got := a.GetActiveTaskName(); got != "Review" {
+		t.Errorf("task = %q without debouncing, want %q", got, "Review")
+	}
+}
+
+func TestGetEstimateAccuracyRounding(t *testing.T) {
+	a, clock, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	if _, err := a.StartTimerWithEstimate("Design", 60); err != nil {
+		t.Fatalf("StartTimerWithEstimate: %v", err)
+	}
+	clock.Advance(68 * time.Minute)
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+
+	accuracy, err := a.GetEstimateAccuracy("2026-03-10", "2026-03-10", 15, RoundNearest)
+	if err != nil {
+		t.Fatalf("GetEstimateAccuracy: %v", err)
+	}
+	// 68 minutes round to 75, the 60 minute estimate stays
+	want := EstimateAccuracy{TaskName: "Design", Sessions: 1, EstimatedSeconds: 3600, ActualSeconds: 4500, VarianceSeconds: 900, VariancePercent: 25}
+	if len(accuracy) != 1 || accuracy[0] != want {
+		t.Errorf("GetEstimateAccuracy = %+v, want %+v", accuracy, want)
+	}
+
+	if _, err := a.GetEstimateAccuracy("2026-03-10", "2026-03-10", 15, "sideways"); err == nil {
+		t.Error("GetEstimateAccuracy accepted an unknown rounding mode")
+	}
+}
+
+func TestPlannedFinishUsesAppClock(t *testing.T) {
+	a, clock, _ := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	slot, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if err := a.SetPlannedDuration(45); err != nil {
+		t.Fatalf("SetPlannedDuration: %v", err)
+	}
+
+	clock.Advance(30 * time.Minute)
+	finish := a.GetPlannedFinishTime()
+	if finish == nil || !finish.FinishTime.Equal(slot.StartTime.Add(45*time.Minute)) || finish.RemainingSeconds != 15*60 {
+		t.Errorf("planned finish = %+v, want 15 minutes left", finish)
+	}
+	clock.Advance(20 * time.Minute)
+	if finish := a.GetPlannedFinishTime(); finish == nil || finish.RemainingSeconds != -5*60 {
+		t.Errorf("planned finish = %+v, want 5 minutes over", finish)
+	}
+}
@@ -0,0 +1,307 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// archiveSchema creates the time_slots and interruptions tables in an attached archive database
+// Archived slots keep their ids, which AUTOINCREMENT never reuses in the live database
+const archiveSchema = `
+CREATE TABLE IF NOT EXISTS archive.time_slots (
+	id INTEGER PRIMARY KEY,
+	task_name TEXT NOT NULL,
+	start_time DATETIME NOT NULL,
+	end_time DATETIME,
+	duration_seconds INTEGER DEFAULT 0,
+	paused_seconds INTEGER NOT NULL DEFAULT 0,
+	paused_at DATETIME,
+	kind TEXT NOT NULL DEFAULT 'work',
+	notes TEXT NOT NULL DEFAULT '',
+	external_ref TEXT NOT NULL DEFAULT '',
+	context TEXT NOT NULL DEFAULT '',
+	estimate_seconds INTEGER NOT NULL DEFAULT 0,
+	auto_stopped BOOLEAN NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS archive.idx_start_time ON time_slots(start_time);
+
+CREATE TABLE IF NOT EXISTS archive.interruptions (
+	id INTEGER PRIMARY KEY,
+	slot_id INTEGER NOT NULL,
+	time DATETIME NOT NULL,
+	note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS archive.idx_interruptions_slot_id ON interruptions(slot_id);
+`
+
+// archivedSlotIDs selects the ids of the slots ArchiveBefore and PruneOlderThan remove
+const archivedSlotIDs = `SELECT id FROM main.time_slots WHERE end_time IS NOT NULL AND start_time < ?`
+
+// ArchiveBefore moves completed slots starting before cutoff and their interruptions
+// into the SQLite file at archivePath, creating it if needed, and returns the number
+// of slots moved. The copy and the delete happen in one transaction; the active slot
+// is never moved.
+func (d *Database) ArchiveBefore(cutoff time.Time, archivePath string) (int, error) {
+	if err := d.checkArchivePath(archivePath); err != nil {
+		return 0, err
+	}
+
+	// ATTACH applies to a single connection, so keep one for the whole operation
+	ctx := context.Background()
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS archive`, archivePath); err != nil {
+		return 0, fmt.Errorf("failed to attach archive: %w", err)
+	}
+	defer conn.ExecContext(ctx, `DETACH DATABASE archive`)
+
+	if _, err := conn.ExecContext(ctx, archiveSchema); err != nil {
+		return 0, fmt.Errorf("failed to initialize archive schema: %w", err)
+	}
+	if err := upgradeArchiveSchema(ctx, conn); err != nil {
+		return 0, fmt.Errorf("failed to upgrade archive schema: %w", err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO archive.time_slots (`+timeSlotColumns+`)
+	                  SELECT `+timeSlotColumns+` FROM main.time_slots
+	                  WHERE end_time IS NOT NULL AND start_time < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy time slots to archive: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO archive.interruptions (id, slot_id, time, note)
+	                  SELECT id, slot_id, time, note FROM main.interruptions
+	                  WHERE slot_id IN (`+archivedSlotIDs+`)`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy interruptions to archive: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM main.interruptions WHERE slot_id IN (`+archivedSlotIDs+`)`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived interruptions: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM main.time_slots
+	                        WHERE end_time IS NOT NULL AND start_time < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived time slots: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(moved), nil
+}
+
+// archiveAddedColumn is a time_slots column added after archives were introduced
+type archiveAddedColumn struct {
+	name       string
+	definition string // column definition for ALTER TABLE
+	fallback   string // value selected from archives that lack the column
+}
+
+// archiveAddedColumns lists the columns older archives may lack, in timeSlotColumns order
+var archiveAddedColumns = []archiveAddedColumn{
+	{"paused_seconds", "INTEGER NOT NULL DEFAULT 0", "0"},
+	{"paused_at", "DATETIME", "NULL"},
+	{"kind", "TEXT NOT NULL DEFAULT 'work'", "'work'"},
+	{"notes", "TEXT NOT NULL DEFAULT ''", "''"},
+	{"external_ref", "TEXT NOT NULL DEFAULT ''", "''"},
+	{"context", "TEXT NOT NULL DEFAULT ''", "''"},
+	{"estimate_seconds", "INTEGER NOT NULL DEFAULT 0", "0"},
+	{"auto_stopped", "BOOLEAN NOT NULL DEFAULT 0", "0"},
+}
+
+// archiveColumnSet returns the names of the columns of time_slots in schema
+func archiveColumnSet(q func(query string, args ...any) (*sql.Rows, error), schema string) (map[string]bool, error) {
+	rows, err := q(`SELECT name FROM pragma_table_info('time_slots', ?)`, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// upgradeArchiveSchema adds the columns missing from archives created by older versions
+func upgradeArchiveSchema(ctx context.Context, conn *sql.Conn) error {
+	columns, err := archiveColumnSet(func(query string, args ...any) (*sql.Rows, error) {
+		return conn.QueryContext(ctx, query, args...)
+	}, "archive")
+	if err != nil {
+		return err
+	}
+
+	for _, column := range archiveAddedColumns {
+		if columns[column.name] {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, `ALTER TABLE archive.time_slots ADD COLUMN `+
+			column.name+` `+column.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkArchivePath rejects archive paths that point at the live database
+func (d *Database) checkArchivePath(archivePath string) error {
+	if archivePath == "" {
+		return fmt.Errorf("archive path is empty")
+	}
+
+	mainPath, err := d.mainPath()
+	if err != nil {
+		return err
+	}
+
+	archiveAbs, err := filepath.Abs(archivePath)
+	if err != nil {
+		return fmt.Errorf("invalid archive path: %w", err)
+	}
+	if archiveAbs == mainPath {
+		return fmt.Errorf("archive path must differ from the main database")
+	}
+
+	return nil
+}
+
+// mainPath returns the cleaned file path of the main database, "" for an in-memory one
+func (d *Database) mainPath() (string, error) {
+	var seq int
+	var name, mainPath string
+	err := d.db.QueryRow(`SELECT seq, name, file FROM pragma_database_list WHERE name = 'main'`).
+		Scan(&seq, &name, &mainPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database path: %w", err)
+	}
+	if mainPath == "" {
+		return "", nil
+	}
+	return filepath.Clean(mainPath), nil
+}
+
+// ExportFile writes a consistent copy of the whole database to destPath
+// VACUUM INTO copies from a single read transaction, so concurrent writes can't
+// tear the copy. It writes to a temporary file next to destPath that is renamed
+// into place, so an existing file is only replaced by a complete copy.
+func (d *Database) ExportFile(destPath string) error {
+	if destPath == "" {
+		return fmt.Errorf("destination path is empty")
+	}
+	mainPath, err := d.mainPath()
+	if err != nil {
+		return err
+	}
+	destAbs, err := filepath.Abs(destPath)
+	if err != nil {
+		return fmt.Errorf("invalid destination path: %w", err)
+	}
+	if destAbs == mainPath {
+		return fmt.Errorf("destination must differ from the main database")
+	}
+
+	// Creating the temporary file also checks that the directory is writable
+	tmp, err := os.CreateTemp(filepath.Dir(destAbs), ".light-tracking-export-*.db")
+	if err != nil {
+		return fmt.Errorf("destination is not writable: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	// VACUUM INTO accepts an existing file only if it is empty
+	if _, err := d.db.Exec(`VACUUM INTO ?`, tmpPath); err != nil {
+		return fmt.Errorf("failed to copy database: %w", err)
+	}
+	if err := os.Rename(tmpPath, destAbs); err != nil {
+		return fmt.Errorf("failed to move database copy into place: %w", err)
+	}
+	return nil
+}
+
+// archiveDSN returns the DSN opening the archive at path read-only
+// The path is escaped as a file URI, so characters such as ? and # in it aren't
+// taken for the query or the fragment
+func archiveDSN(path string) string {
+	uriPath := filepath.ToSlash(path)
+	if filepath.VolumeName(path) != "" {
+		// Windows drive paths become file:///C:/...
+		uriPath = "/" + uriPath
+	}
+	query := url.Values{}
+	query.Set("mode", "ro")
+	query.Set("_time_format", "sqlite")
+	query.Set("_pragma", "busy_timeout(5000)")
+	dsn := url.URL{Scheme: "file", Path: uriPath, RawQuery: query.Encode()}
+	return dsn.String()
+}
+
+// QueryArchive returns the archived slots starting in [start, end)
+// The archive file is opened read-only and must already exist. Task names in
+// archives of an encrypted database are decrypted with this database's key.
+func (d *Database) QueryArchive(archivePath string, start time.Time, end time.Time) ([]*models.TimeSlot, error) {
+	db, err := sql.Open("sqlite", archiveDSN(archivePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer db.Close()
+
+	// Archives written by older versions lack the newer columns
+	existing, err := archiveColumnSet(db.Query, "main")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive schema: %w", err)
+	}
+	columns := `id, task_name, start_time, end_time, duration_seconds`
+	for _, column := range archiveAddedColumns {
+		if existing[column.name] {
+			columns += `, ` + column.name
+		} else {
+			columns += `, ` + column.fallback
+		}
+	}
+
+	query := `SELECT ` + columns + `
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ?
+	          ORDER BY start_time ASC`
+
+	rows, err := db.Query(query, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archive: %w", err)
+	}
+	defer rows.Close()
+
+	return d.scanTimeSlots(rows)
+}
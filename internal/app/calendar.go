@@ -0,0 +1,301 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// calendarTitleSimilarity is the nameSimilarity from which an event title and a
+// task name are considered the same
+const calendarTitleSimilarity = 0.6
+
+// CalendarEvent is a timed event read from an ICS calendar
+type CalendarEvent struct {
+	Summary string    `json:"summary"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// CalendarMatch is an event and the tracked slots that cover it
+type CalendarMatch struct {
+	Event          CalendarEvent `json:"event"`
+	SlotIDs        []int64       `json:"slot_ids"`
+	OverlapSeconds int64         `json:"overlap_seconds"`
+}
+
+// CalendarReconciliation compares a day's calendar events with its tracked slots
+type CalendarReconciliation struct {
+	Date    string          `json:"date"`
+	Matched []CalendarMatch `json:"matched"`
+	// UnloggedEvents are events no tracked slot covers
+	UnloggedEvents []CalendarEvent `json:"unlogged_events"`
+	// UnplannedSlots are work slots that match no event
+	UnplannedSlots []*models.TimeSlot `json:"unplanned_slots"`
+}
+
+// ReconcileWithCalendar compares the events of an ICS calendar on a date with the
+// slots tracked that day. An event and a work slot match when they overlap and the
+// task name resembles the event title. All-day and cancelled events are ignored and
+// recurring events only count on their first occurrence.
+// date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ReconcileWithCalendar(icsData string, dateStr string) (*CalendarReconciliation, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	events, err := parseICSEvents(icsData)
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	slots, err := a.database.GetOverlappingTimeSlots(0, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconcileCalendar(events, slots, dayStart, dayEnd, a.now()), nil
+}
+
+// reconcileCalendar matches the events and work slots overlapping [dayStart, dayEnd)
+// Active slots count until now
+func reconcileCalendar(events []CalendarEvent, slots []*models.TimeSlot, dayStart, dayEnd, now time.Time) *CalendarReconciliation {
+	result := &CalendarReconciliation{
+		Date:           dayStart.Format("2006-01-02"),
+		Matched:        []CalendarMatch{},
+		UnloggedEvents: []CalendarEvent{},
+		UnplannedSlots: []*models.TimeSlot{},
+	}
+
+	var workSlots []*models.TimeSlot
+	for _, slot := range slots {
+		if !slot.IsBreak() {
+			workSlots = append(workSlots, slot)
+		}
+	}
+
+	matchedSlots := make(map[int64]bool)
+	for _, event := range events {
+		if !event.Start.Before(dayEnd) || !event.End.After(dayStart) {
+			continue
+		}
+
+		match := CalendarMatch{Event: event, SlotIDs: []int64{}}
+		for _, slot := range workSlots {
+			start := slot.StartTime
+			if start.Before(event.Start) {
+				start = event.Start
+			}
+			end := now
+			if slot.EndTime != nil {
+				end = *slot.EndTime
+			}
+			if end.After(event.End) {
+				end = event.End
+			}
+			overlap := end.Sub(start)
+			if overlap <= 0 || !titlesMatch(event.Summary, slot.TaskName) {
+				continue
+			}
+			match.SlotIDs = append(match.SlotIDs, slot.ID)
+			match.OverlapSeconds += int64(overlap.Seconds())
+			matchedSlots[slot.ID] = true
+		}
+
+		if len(match.SlotIDs) == 0 {
+			result.UnloggedEvents = append(result.UnloggedEvents, event)
+		} else {
+			result.Matched = append(result.Matched, match)
+		}
+	}
+
+	for _, slot := range workSlots {
+		if !matchedSlots[slot.ID] {
+			result.UnplannedSlots = append(result.UnplannedSlots, slot)
+		}
+	}
+
+	return result
+}
+
+// titlesMatch reports whether an event title and a task name likely name the same thing
+func titlesMatch(summary, taskName string) bool {
+	summary = strings.ToLower(strings.TrimSpace(summary))
+	taskName = strings.ToLower(strings.TrimSpace(taskName))
+	if summary == "" || taskName == "" {
+		return false
+	}
+	if strings.Contains(summary, taskName) || strings.Contains(taskName, summary) {
+		return true
+	}
+	return nameSimilarity(summary, taskName) >= calendarTitleSimilarity
+}
+
+// parseICSEvents reads the timed events of an ICS calendar in file order
+func parseICSEvents(data string) ([]CalendarEvent, error) {
+	// Long lines are folded by starting continuation lines with a space or tab
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+	if !strings.Contains(data, "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("calendar data is not in ICS format")
+	}
+
+	events := []CalendarEvent{}
+	var props map[string]icsProperty
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch line {
+		case "BEGIN:VEVENT":
+			props = make(map[string]icsProperty)
+			continue
+		case "END:VEVENT":
+			if event, ok := icsEvent(props); ok {
+				events = append(events, event)
+			}
+			props = nil
+			continue
+		}
+		if props == nil {
+			continue
+		}
+		if prop, ok := parseICSProperty(line); ok {
+			if _, seen := props[prop.name]; !seen {
+				props[prop.name] = prop
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// icsProperty is one "NAME;PARAM=VALUE:value" line of an ICS file
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICSProperty splits a content line into its name, parameters and value
+func parseICSProperty(line string) (icsProperty, bool) {
+	// The value starts at the first colon outside a quoted parameter value
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// icsEvent builds a CalendarEvent from the properties of a VEVENT
+// ok is false for all-day, cancelled or malformed events
+func icsEvent(props map[string]icsProperty) (CalendarEvent, bool) {
+	if strings.EqualFold(props["STATUS"].value, "CANCELLED") {
+		return CalendarEvent{}, false
+	}
+	startProp, ok := props["DTSTART"]
+	if !ok {
+		return CalendarEvent{}, false
+	}
+	start, ok := parseICSTime(startProp)
+	if !ok {
+		return CalendarEvent{}, false
+	}
+
+	var end time.Time
+	if endProp, ok := props["DTEND"]; ok {
+		if end, ok = parseICSTime(endProp); !ok {
+			return CalendarEvent{}, false
+		}
+	} else if durationProp, ok := props["DURATION"]; ok {
+		duration, ok := parseICSDuration(durationProp.value)
+		if !ok {
+			return CalendarEvent{}, false
+		}
+		end = start.Add(duration)
+	} else {
+		return CalendarEvent{}, false
+	}
+	if !end.After(start) {
+		return CalendarEvent{}, false
+	}
+
+	return CalendarEvent{Summary: unescapeICSText(props["SUMMARY"].value), Start: start, End: end}, true
+}
+
+// parseICSTime parses a DATE-TIME value in UTC, in its TZID or in local time
+// Dates without a time belong to all-day events and are rejected
+func parseICSTime(prop icsProperty) (time.Time, bool) {
+	if prop.params["VALUE"] == "DATE" || len(prop.value) == len("20060102") {
+		return time.Time{}, false
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", prop.value)
+		return t.Local(), err == nil
+	}
+
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", prop.value, location)
+	return t.Local(), err == nil
+}
+
+// icsDurationPattern matches the durations used by events, e.g. "PT1H30M" or "P1D"
+var icsDurationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration parses a positive ICS duration
+func parseICSDuration(value string) (time.Duration, bool) {
+	parts := icsDurationPattern.FindStringSubmatch(strings.TrimPrefix(value, "+"))
+	if parts == nil {
+		return 0, false
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if parts[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(parts[i+1])
+		if err != nil {
+			return 0, false
+		}
+		duration += time.Duration(n) * unit
+	}
+	return duration, duration > 0
+}
+
+// unescapeICSText reverses the escaping of ICS text values
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Chart types for ExportChartPNG
+const (
+	ChartBar = "bar"
+	ChartPie = "pie"
+)
+
+// Chart image geometry in pixels
+const (
+	chartWidth   = 480
+	chartHeight  = 320
+	chartPadding = 20
+	chartBarGap  = 8
+)
+
+var (
+	chartBackground = color.RGBA{255, 255, 255, 255}
+	chartAxisColor  = color.RGBA{100, 100, 100, 255}
+	chartEmptyColor = color.RGBA{200, 200, 200, 255}
+)
+
+// chartSlice is one task of a chart with its share of the total
+type chartSlice struct {
+	taskName string
+	seconds  int64
+	color    color.RGBA
+}
+
+// isValidChartType checks a chart type passed to ExportChartPNG
+func isValidChartType(chartType string) bool {
+	return chartType == ChartBar || chartType == ChartPie
+}
+
+// chartSlices orders task totals by duration (longest first, then by name)
+// and resolves their colors; tasks without a color get the default one
+func chartSlices(stats map[string]int64, colors map[string]string) []chartSlice {
+	slices := make([]chartSlice, 0, len(stats))
+	for taskName, seconds := range stats {
+		if seconds <= 0 {
+			continue
+		}
+		hex, ok := colors[taskName]
+		if !ok {
+			hex = defaultTaskColor(taskName)
+		}
+		slices = append(slices, chartSlice{taskName: taskName, seconds: seconds, color: parseHexColor(hex)})
+	}
+	sort.Slice(slices, func(i, j int) bool {
+		if slices[i].seconds != slices[j].seconds {
+			return slices[i].seconds > slices[j].seconds
+		}
+		return slices[i].taskName < slices[j].taskName
+	})
+	return slices
+}
+
+// parseHexColor converts a #RRGGBB color, falling back to the axis gray for invalid input
+func parseHexColor(hex string) color.RGBA {
+	if !isValidColor(hex) {
+		return chartAxisColor
+	}
+	v, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+}
+
+// renderChart draws task totals as a bar or pie chart and encodes it as PNG.
+// There are no labels since the standard library has no fonts; bars and pie
+// slices use the task colors, ordered from the longest task.
+// Days without tracked time give a placeholder: a crossed-out circle.
+func renderChart(stats map[string]int64, colors map[string]string, chartType string) ([]byte, error) {
+	if !isValidChartType(chartType) {
+		return nil, fmt.Errorf("unknown chart type %q, expected %q or %q", chartType, ChartBar, ChartPie)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{chartBackground}, image.Point{}, draw.Src)
+
+	slices := chartSlices(stats, colors)
+	switch {
+	case len(slices) == 0:
+		drawEmptyChart(img)
+	case chartType == ChartBar:
+		drawBarChart(img, slices)
+	default:
+		drawPieChart(img, slices)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawBarChart draws one vertical bar per task, scaled to the longest task
+func drawBarChart(img *image.RGBA, slices []chartSlice) {
+	left, right := chartPadding, chartWidth-chartPadding
+	top, bottom := chartPadding, chartHeight-chartPadding
+
+	barWidth := (right - left - chartBarGap*(len(slices)+1)) / len(slices)
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	maxSeconds := slices[0].seconds
+
+	for i, slice := range slices {
+		x := left + chartBarGap + i*(barWidth+chartBarGap)
+		if x >= right {
+			break
+		}
+		height := int(float64(bottom-top) * float64(slice.seconds) / float64(maxSeconds))
+		if height < 1 {
+			height = 1
+		}
+		bar := image.Rect(x, bottom-height, min(x+barWidth, right), bottom)
+		draw.Draw(img, bar, &image.Uniform{slice.color}, image.Point{}, draw.Src)
+	}
+
+	// Baseline
+	draw.Draw(img, image.Rect(left, bottom, right, bottom+1), &image.Uniform{chartAxisColor}, image.Point{}, draw.Src)
+}
+
+// drawPieChart draws a pie starting at 12 o'clock and going clockwise
+func drawPieChart(img *image.RGBA, slices []chartSlice) {
+	var total int64
+	for _, slice := range slices {
+		total += slice.seconds
+	}
+
+	// Cumulative end angle of each slice as a fraction of the full circle
+	ends := make([]float64, len(slices))
+	var acc int64
+	for i, slice := range slices {
+		acc += slice.seconds
+		ends[i] = float64(acc) / float64(total)
+	}
+
+	cx, cy := float64(chartWidth)/2, float64(chartHeight)/2
+	radius := cy - chartPadding
+
+	for y := 0; y < chartHeight; y++ {
+		for x := 0; x < chartWidth; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+
+			// Angle measured clockwise from 12 o'clock, as a fraction of the circle
+			fraction := math.Atan2(dx, -dy) / (2 * math.Pi)
+			if fraction < 0 {
+				fraction++
+			}
+			i := sort.SearchFloat64s(ends, fraction)
+			if i >= len(slices) {
+				i = len(slices) - 1
+			}
+			img.SetRGBA(x, y, slices[i].color)
+		}
+	}
+}
+
+// drawEmptyChart draws the "no data" placeholder: a gray circle crossed out by a diagonal
+func drawEmptyChart(img *image.RGBA) {
+	cx, cy := float64(chartWidth)/2, float64(chartHeight)/2
+	radius := cy / 2
+
+	for y := 0; y < chartHeight; y++ {
+		for x := 0; x < chartWidth; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			distance := math.Sqrt(dx*dx + dy*dy)
+
+			onOutline := distance >= radius-3 && distance <= radius
+			// Distance from the line y = -x through the center
+			onDiagonal := distance <= radius && math.Abs(dx+dy)/math.Sqrt2 <= 1.5
+			if onOutline || onDiagonal {
+				img.SetRGBA(x, y, chartEmptyColor)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"hash/fnv"
+	"regexp"
+)
+
+// taskColorPalette is used to pick default colors for tasks without a user-chosen one
+var taskColorPalette = []string{
+	"#4CAF50", // green
+	"#2196F3", // blue
+	"#FF9800", // orange
+	"#9C27B0", // purple
+	"#F44336", // red
+	"#009688", // teal
+	"#FFC107", // amber
+	"#3F51B5", // indigo
+	"#E91E63", // pink
+	"#795548", // brown
+}
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// defaultTaskColor returns a stable palette color derived from the task name
+func defaultTaskColor(taskName string) string {
+	h := fnv.New32a()
+	h.Write([]byte(taskName))
+	return taskColorPalette[h.Sum32()%uint32(len(taskColorPalette))]
+}
+
+// isValidColor reports whether color is a hex color in #RRGGBB format
+func isValidColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxContextLength is the longest accepted context label in characters
+const maxContextLength = 64
+
+// noContextLabel groups time tracked without a context in GetStatisticsByContext
+const noContextLabel = "No context"
+
+// normalizeContext collapses whitespace in a context label and checks its length
+func normalizeContext(label string) (string, error) {
+	label = strings.Join(strings.Fields(label), " ")
+	if utf8.RuneCountInString(label) > maxContextLength {
+		return "", fmt.Errorf("context must be at most %d characters", maxContextLength)
+	}
+	return label, nil
+}
+
+// validateContextSettings normalizes the current context and the network labels
+func validateContextSettings(settings *Settings) error {
+	current, err := normalizeContext(settings.CurrentContext)
+	if err != nil {
+		return err
+	}
+	settings.CurrentContext = current
+
+	networks := make(map[string]string, len(settings.NetworkContexts))
+	for ssid, label := range settings.NetworkContexts {
+		if ssid == "" {
+			return fmt.Errorf("network name cannot be empty")
+		}
+		if label, err = normalizeContext(label); err != nil {
+			return fmt.Errorf("network %q: %w", ssid, err)
+		}
+		if label == "" {
+			return fmt.Errorf("network %q: context cannot be empty", ssid)
+		}
+		networks[ssid] = label
+	}
+	settings.NetworkContexts = networks
+	return nil
+}
+
+// resolveContext returns the context new slots are recorded with
+// A Wi-Fi network listed in the NetworkContexts setting takes precedence; when
+// none is connected or the network can't be read the CurrentContext setting is used
+func (a *App) resolveContext() string {
+	settings := a.settings.Get()
+	if len(settings.NetworkContexts) == 0 {
+		return settings.CurrentContext
+	}
+
+	ssid, err := currentSSID()
+	if err != nil {
+		log.Println("Failed to detect the Wi-Fi network:", err)
+		return settings.CurrentContext
+	}
+	if label, ok := settings.NetworkContexts[ssid]; ok {
+		return label
+	}
+	return settings.CurrentContext
+}
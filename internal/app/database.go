@@ -2,9 +2,13 @@ package app
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"light-tracking/internal/models"
@@ -12,31 +16,141 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// Database stores time slots in SQLite.
+// Timestamps are always written in UTC and converted to local time when scanned,
+// so the absolute instant is preserved when the user changes timezone.
+// Task names may be stored encrypted, see encryption.go.
 type Database struct {
-	db *sql.DB
+	db      *sql.DB
+	dataDir appDataDir // where NewDatabase put the file, zero for NewDatabaseAt
+
+	cipherMu  sync.RWMutex
+	encrypted bool        // task names are stored encrypted
+	names     *nameCipher // nil until the database is unlocked
 }
 
-// NewDatabase creates a new database connection
-func NewDatabase() (*Database, error) {
-	// Get user's home directory
-	homeDir, err := os.UserHomeDir()
+// appDataDir is the data directory chosen by chooseAppDataDir
+type appDataDir struct {
+	path      string
+	fallback  bool // the directory in the home directory couldn't be used
+	temporary bool // the directory is in the temp directory, which the system may clear
+}
+
+var (
+	appDataDirOnce      sync.Once
+	cachedAppDataDir    appDataDir
+	cachedAppDataDirErr error
+)
+
+// getAppDataDir returns the application data directory, creating it if needed
+// It is ~/.light-tracking; without a usable home directory (sandboxes, minimal
+// containers) it falls back to $XDG_DATA_HOME/light-tracking and then to the
+// temp directory. The choice is made once per process.
+func getAppDataDir() (string, error) {
+	dir, err := chooseAppDataDirOnce()
+	return dir.path, err
+}
+
+// chooseAppDataDirOnce returns the data directory chosen for this process
+func chooseAppDataDirOnce() (appDataDir, error) {
+	appDataDirOnce.Do(func() {
+		cachedAppDataDir, cachedAppDataDirErr = chooseAppDataDir()
+	})
+	return cachedAppDataDir, cachedAppDataDirErr
+}
+
+// chooseAppDataDir creates and returns the first usable data directory candidate
+func chooseAppDataDir() (appDataDir, error) {
+	var candidates []string
+	var failures []error
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(homeDir, ".light-tracking"))
+	} else {
+		failures = append(failures, fmt.Errorf("failed to get home directory: %w", err))
+	}
+	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
+		candidates = append(candidates, filepath.Join(xdgDataHome, "light-tracking"))
+	}
+
+	for i, dir := range candidates {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			failures = append(failures, fmt.Errorf("failed to create app data directory: %w", err))
+			continue
+		}
+		if i > 0 || len(failures) > 0 {
+			log.Printf("Using fallback app data directory %s", dir)
+			return appDataDir{path: dir, fallback: true}, nil
+		}
+		log.Printf("Using app data directory %s", dir)
+		return appDataDir{path: dir}, nil
+	}
+
+	// Other users can write to the temp directory, so the directory must be ours
+	dir := filepath.Join(os.TempDir(), "light-tracking")
+	if err := makePrivateDir(dir); err != nil {
+		failures = append(failures, err)
+		return appDataDir{}, fmt.Errorf("no usable app data directory: %w", errors.Join(failures...))
+	}
+	log.Printf("Using temporary app data directory %s, data may be lost when the system clears it", dir)
+	return appDataDir{path: dir, fallback: true, temporary: true}, nil
+}
+
+// makePrivateDir creates dir readable only by the current user, or checks that
+// an existing dir is a real directory owned by the current user and restricts
+// its permissions
+func makePrivateDir(dir string) error {
+	if err := os.Mkdir(dir, 0700); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create app data directory: %w", err)
+	}
+
+	// Lstat doesn't follow a symlink planted in place of the directory
+	info, err := os.Lstat(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return fmt.Errorf("failed to check app data directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("app data directory %s is not a directory", dir)
 	}
+	if err := checkDirOwner(info); err != nil {
+		return fmt.Errorf("app data directory %s: %w", dir, err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return fmt.Errorf("failed to restrict app data directory permissions: %w", err)
+		}
+	}
+	return nil
+}
 
-	// Create app data directory
-	appDataDir := filepath.Join(homeDir, ".light-tracking")
-	if err := os.MkdirAll(appDataDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create app data directory: %w", err)
+// NewDatabase creates a new database connection to the database in the app data directory
+func NewDatabase() (*Database, error) {
+	appDataDir, err := chooseAppDataDirOnce()
+	if err != nil {
+		return nil, err
 	}
 
-	// Database file path
-	dbPath := filepath.Join(appDataDir, "time_tracking.db")
+	database, err := NewDatabaseAt(filepath.Join(appDataDir.path, "time_tracking.db"))
+	if err != nil {
+		return nil, err
+	}
+	database.dataDir = appDataDir
+	return database, nil
+}
 
-	db, err := sql.Open("sqlite", dbPath)
+// NewDatabaseAt creates a new database connection to the SQLite file at dbPath
+// ":memory:" opens a private in-memory database, e.g. for tests
+func NewDatabaseAt(dbPath string) (*Database, error) {
+	// Write times in a fixed format that SQLite's date functions understand,
+	// wait on locks instead of failing and take the write lock when a transaction begins
+	db, err := sql.Open("sqlite", dbPath+"?_time_format=sqlite&_pragma=busy_timeout(5000)&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	if dbPath == ":memory:" {
+		// Every connection would get its own empty in-memory database
+		db.SetMaxOpenConns(1)
+	}
 
 	database := &Database{db: db}
 
@@ -46,6 +160,16 @@ func NewDatabase() (*Database, error) {
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
+	if err := database.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	if err := database.loadEncryptionState(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return database, nil
 }
 
@@ -62,21 +186,136 @@ func (d *Database) initSchema() error {
 	
 	CREATE INDEX IF NOT EXISTS idx_start_time ON time_slots(start_time);
 	CREATE INDEX IF NOT EXISTS idx_task_name ON time_slots(task_name);
+
+	CREATE TABLE IF NOT EXISTS task_colors (
+		task_name TEXT PRIMARY KEY,
+		color TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS encryption (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		salt BLOB NOT NULL,
+		verifier TEXT NOT NULL
+	);
 	`
 
 	_, err := d.db.Exec(query)
 	return err
 }
 
+// timeSlotColumns lists the time_slots columns in the order expected by scanTimeSlot
+const timeSlotColumns = `id, task_name, start_time, end_time, duration_seconds, paused_seconds, paused_at, kind, notes, external_ref, context, estimate_seconds, auto_stopped`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTimeSlot scans a row selected with timeSlotColumns into a TimeSlot
+func (d *Database) scanTimeSlot(row rowScanner) (*models.TimeSlot, error) {
+	var ts models.TimeSlot
+	var endTime, pausedAt sql.NullTime
+
+	err := row.Scan(
+		&ts.ID,
+		&ts.TaskName,
+		&ts.StartTime,
+		&endTime,
+		&ts.DurationSeconds,
+		&ts.PausedSeconds,
+		&pausedAt,
+		&ts.Kind,
+		&ts.Notes,
+		&ts.ExternalRef,
+		&ts.Context,
+		&ts.EstimateSeconds,
+		&ts.AutoStopped,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if ts.TaskName, err = d.decodeName(ts.TaskName); err != nil {
+		return nil, err
+	}
+	if ts.Notes, err = d.decodeText(ts.Notes); err != nil {
+		return nil, err
+	}
+	if ts.Context, err = d.decodeText(ts.Context); err != nil {
+		return nil, err
+	}
+
+	ts.StartTime = ts.StartTime.Local()
+	if endTime.Valid {
+		et := endTime.Time.Local()
+		ts.EndTime = &et
+	}
+	if pausedAt.Valid {
+		pa := pausedAt.Time.Local()
+		ts.PausedAt = &pa
+	}
+
+	return &ts, nil
+}
+
+// scanTimeSlots scans all rows selected with timeSlotColumns
+func (d *Database) scanTimeSlots(rows *sql.Rows) ([]*models.TimeSlot, error) {
+	var slots []*models.TimeSlot
+	for rows.Next() {
+		ts, err := d.scanTimeSlot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time slot: %w", err)
+		}
+		slots = append(slots, ts)
+	}
+
+	return slots, rows.Err()
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on error
+func (d *Database) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.db.Close()
 }
 
-// CreateTimeSlot creates a new time slot
-func (d *Database) CreateTimeSlot(taskName string, startTime time.Time) (*models.TimeSlot, error) {
-	query := `INSERT INTO time_slots (task_name, start_time) VALUES (?, ?)`
-	result, err := d.db.Exec(query, taskName, startTime)
+// createTimeSlotQuery inserts an active time slot
+const createTimeSlotQuery = `INSERT INTO time_slots (task_name, start_time, kind, external_ref, context) VALUES (?, ?, ?, ?, ?)`
+
+// CreateTimeSlot creates a new active time slot of the given kind, external reference
+// and context
+func (d *Database) CreateTimeSlot(taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error) {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return nil, err
+	}
+	storedContext, err := d.encodeText(slotContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sql.Result
+	err = withRetry(func() error {
+		var err error
+		result, err = d.db.Exec(createTimeSlotQuery, storedName, startTime.UTC(), kind, externalRef, storedContext)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create time slot: %w", err)
 	}
@@ -89,61 +328,238 @@ func (d *Database) CreateTimeSlot(taskName string, startTime time.Time) (*models
 	return &models.TimeSlot{
 		ID:        id,
 		TaskName:  taskName,
-		StartTime: startTime,
+		StartTime:   startTime,
+		Kind:        kind,
+		ExternalRef: externalRef,
+		Context:     slotContext,
+	}, nil
+}
+
+// CreateCompletedTimeSlots creates finished time slots for imported entries in one
+// transaction and returns how many were created
+func (d *Database) CreateCompletedTimeSlots(entries []ImportEntry) (int, error) {
+	storedNames := make([]string, len(entries))
+	for i, entry := range entries {
+		storedName, err := d.encodeName(entry.TaskName)
+		if err != nil {
+			return 0, err
+		}
+		storedNames[i] = storedName
+	}
+
+	query := `INSERT INTO time_slots (task_name, start_time, end_time, duration_seconds) VALUES (?, ?, ?, ?)`
+	err := withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			for i, entry := range entries {
+				durationSeconds := int64(entry.End.Sub(entry.Start).Seconds())
+				if _, err := tx.Exec(query, storedNames[i], entry.Start.UTC(), entry.End.UTC(), durationSeconds); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import time slots: %w", err)
+	}
+	return len(entries), nil
+}
+
+// CreateCompletedTimeSlot creates a finished time slot, e.g. for manually logged work
+func (d *Database) CreateCompletedTimeSlot(taskName string, startTime time.Time, endTime time.Time) (*models.TimeSlot, error) {
+	durationSeconds := int64(endTime.Sub(startTime).Seconds())
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO time_slots (task_name, start_time, end_time, duration_seconds) VALUES (?, ?, ?, ?)`
+	result, err := d.db.Exec(query, storedName, startTime.UTC(), endTime.UTC(), durationSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time slot: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return &models.TimeSlot{
+		ID:              id,
+		TaskName:        taskName,
+		StartTime:       startTime,
+		EndTime:         &endTime,
+		DurationSeconds: durationSeconds,
 	}, nil
 }
 
 // GetActiveTimeSlot returns the currently active time slot, if any
 func (d *Database) GetActiveTimeSlot() (*models.TimeSlot, error) {
-	query := `SELECT id, task_name, start_time, end_time, duration_seconds 
+	query := `SELECT ` + timeSlotColumns + `
 	          FROM time_slots 
 	          WHERE end_time IS NULL 
 	          ORDER BY start_time DESC 
 	          LIMIT 1`
 
-	var ts models.TimeSlot
-	var endTime sql.NullTime
+	ts, err := d.scanTimeSlot(d.db.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active time slot: %w", err)
+	}
 
-	err := d.db.QueryRow(query).Scan(
-		&ts.ID,
-		&ts.TaskName,
-		&ts.StartTime,
-		&endTime,
-		&ts.DurationSeconds,
-	)
+	return ts, nil
+}
+
+// GetTimeSlot returns the time slot with the given id, or nil if it doesn't exist
+func (d *Database) GetTimeSlot(id int64) (*models.TimeSlot, error) {
+	query := `SELECT ` + timeSlotColumns + ` FROM time_slots WHERE id = ?`
 
+	ts, err := d.scanTimeSlot(d.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active time slot: %w", err)
+		return nil, fmt.Errorf("failed to get time slot: %w", err)
 	}
 
-	if endTime.Valid {
-		ts.EndTime = &endTime.Time
+	return ts, nil
+}
+
+// GetLastCompletedSlot returns the most recently finished time slot, if any
+func (d *Database) GetLastCompletedSlot() (*models.TimeSlot, error) {
+	query := `SELECT ` + timeSlotColumns + `
+	          FROM time_slots
+	          WHERE end_time IS NOT NULL
+	          ORDER BY end_time DESC
+	          LIMIT 1`
+
+	ts, err := d.scanTimeSlot(d.db.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last completed time slot: %w", err)
 	}
 
-	return &ts, nil
+	return ts, nil
 }
 
 // StopTimeSlot stops an active time slot
+// The start time is read and the slot updated in one transaction so a concurrent edit can't interleave
 func (d *Database) StopTimeSlot(id int64, endTime time.Time) error {
-	// First get the start time
-	var startTime time.Time
-	err := d.db.QueryRow("SELECT start_time FROM time_slots WHERE id = ?", id).Scan(&startTime)
+	return withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			return stopTimeSlot(tx, id, endTime)
+		})
+	})
+}
+
+// SwitchTimeSlot stops the active slot stopID at endTime and creates a new active
+// slot like CreateTimeSlot in one transaction, so a failure can't leave the
+// stopped slot without a successor
+func (d *Database) SwitchTimeSlot(stopID int64, endTime time.Time, taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error) {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return nil, err
+	}
+	storedContext, err := d.encodeText(slotContext)
 	if err != nil {
-		return fmt.Errorf("failed to get start time: %w", err)
+		return nil, err
 	}
 
-	// Calculate duration
-	durationSeconds := int64(endTime.Sub(startTime).Seconds())
+	var id int64
+	err = withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			if err := stopTimeSlot(tx, stopID, endTime); err != nil {
+				return err
+			}
+
+			result, err := tx.Exec(createTimeSlotQuery, storedName, startTime.UTC(), kind, externalRef, storedContext)
+			if err != nil {
+				return fmt.Errorf("failed to create time slot: %w", err)
+			}
+			id, err = result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TimeSlot{
+		ID:          id,
+		TaskName:    taskName,
+		StartTime:   startTime,
+		Kind:        kind,
+		ExternalRef: externalRef,
+		Context:     slotContext,
+	}, nil
+}
+
+// AutoStopTimeSlot stops a time slot like StopTimeSlot and flags it as stopped by the app
+func (d *Database) AutoStopTimeSlot(id int64, endTime time.Time) error {
+	return withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			if err := stopTimeSlot(tx, id, endTime); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE time_slots SET auto_stopped = 1 WHERE id = ?`, id); err != nil {
+				return fmt.Errorf("failed to flag time slot as auto-stopped: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// StopTimeSlotAndGetStatistics stops a time slot and returns task statistics
+// for [start, end) read in the same transaction, so they include the stopped slot
+func (d *Database) StopTimeSlotAndGetStatistics(id int64, endTime time.Time, start time.Time, end time.Time) (map[string]int64, error) {
+	var stats map[string]int64
+	err := withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			if err := stopTimeSlot(tx, id, endTime); err != nil {
+				return err
+			}
+
+			var err error
+			stats, err = d.taskStatisticsForRange(tx, start, end, "")
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// stopTimeSlot sets the end time and duration of a time slot within tx
+// A pause in progress ends at endTime and is added to the paused time
+func stopTimeSlot(tx *sql.Tx, id int64, endTime time.Time) error {
+	// First get the start time and pause state
+	slot, err := readSlotTimes(tx, id)
+	if err != nil {
+		return err
+	}
+	slot.EndTime = &endTime
+	slot.PausedSeconds = int64(slot.PausedUntil(endTime).Seconds())
+	slot.PausedAt = nil
+	slot.CalculateDuration()
+	if slot.DurationSeconds < 0 {
+		slot.DurationSeconds = 0
+	}
 
 	// Update the time slot
 	query := `UPDATE time_slots 
-	          SET end_time = ?, duration_seconds = ?
+	          SET end_time = ?, duration_seconds = ?, paused_seconds = ?, paused_at = NULL
 	          WHERE id = ?`
-	
-	_, err = d.db.Exec(query, endTime, durationSeconds, id)
+
+	_, err = tx.Exec(query, endTime.UTC(), slot.DurationSeconds, slot.PausedSeconds, id)
 	if err != nil {
 		return fmt.Errorf("failed to stop time slot: %w", err)
 	}
@@ -151,60 +567,168 @@ func (d *Database) StopTimeSlot(id int64, endTime time.Time) error {
 	return nil
 }
 
+// ReopenSlot makes a completed time slot active again by clearing its end time and duration
+// It fails while another slot is active, since only one slot may run at a time
+func (d *Database) ReopenSlot(id int64) error {
+	err := withRetry(func() error {
+		result, err := d.db.Exec(`UPDATE time_slots SET end_time = NULL, duration_seconds = 0, paused_at = NULL, auto_stopped = 0
+		                          WHERE id = ? AND end_time IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("completed time slot %d %w", id, ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reopen time slot: %w", err)
+	}
+	return nil
+}
+
+// SplitActiveSlot ends the active slot at start, records [start, end) as a completed
+// work slot for taskName and continues the original slot's kind and reference in a
+// new active slot from end, all in one transaction
+// The returned slot has no task name set, the caller already knows it
+func (d *Database) SplitActiveSlot(id int64, start time.Time, end time.Time, taskName string) (*models.TimeSlot, error) {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	var continued *models.TimeSlot
+	err = withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			var storedOriginal, kind, externalRef, storedContext string
+			var estimateSeconds int64
+			err := tx.QueryRow(`SELECT task_name, kind, external_ref, context, estimate_seconds FROM time_slots WHERE id = ? AND end_time IS NULL`, id).
+				Scan(&storedOriginal, &kind, &externalRef, &storedContext, &estimateSeconds)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("active time slot %d %w", id, ErrNotFound)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get active time slot: %w", err)
+			}
+
+			if err := stopTimeSlot(tx, id, start); err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(`INSERT INTO time_slots (task_name, start_time, end_time, duration_seconds, context) VALUES (?, ?, ?, ?, ?)`,
+				storedName, start.UTC(), end.UTC(), int64(end.Sub(start).Seconds()), storedContext)
+			if err != nil {
+				return fmt.Errorf("failed to create time slot: %w", err)
+			}
+
+			result, err := tx.Exec(`INSERT INTO time_slots (task_name, start_time, kind, external_ref, context, estimate_seconds) VALUES (?, ?, ?, ?, ?, ?)`,
+				storedOriginal, end.UTC(), kind, externalRef, storedContext, estimateSeconds)
+			if err != nil {
+				return fmt.Errorf("failed to create time slot: %w", err)
+			}
+			newID, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+
+			slotContext, err := d.decodeText(storedContext)
+			if err != nil {
+				return err
+			}
+			continued = &models.TimeSlot{
+				ID:              newID,
+				StartTime:       end,
+				Kind:            kind,
+				ExternalRef:     externalRef,
+				Context:         slotContext,
+				EstimateSeconds: estimateSeconds,
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return continued, nil
+}
+
+// readSlotTimes reads the start time and pause state of a time slot within tx
+func readSlotTimes(tx *sql.Tx, id int64) (*models.TimeSlot, error) {
+	slot := &models.TimeSlot{ID: id}
+	var pausedAt sql.NullTime
+
+	err := tx.QueryRow("SELECT start_time, paused_seconds, paused_at FROM time_slots WHERE id = ?", id).
+		Scan(&slot.StartTime, &slot.PausedSeconds, &pausedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get start time: %w", err)
+	}
+	if pausedAt.Valid {
+		slot.PausedAt = &pausedAt.Time
+	}
+
+	return slot, nil
+}
+
 // GetTimeSlotsByDate returns all time slots for a specific date
 func (d *Database) GetTimeSlotsByDate(date time.Time) ([]*models.TimeSlot, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	return d.GetTimeSlotsInRange(startOfDay, endOfDay)
+}
 
-	query := `SELECT id, task_name, start_time, end_time, duration_seconds 
+// GetTimeSlotsInRange returns all time slots starting in [start, end) in chronological order
+func (d *Database) GetTimeSlotsInRange(start time.Time, end time.Time) ([]*models.TimeSlot, error) {
+	query := `SELECT ` + timeSlotColumns + `
 	          FROM time_slots 
 	          WHERE start_time >= ? AND start_time < ?
 	          ORDER BY start_time ASC`
 
-	rows, err := d.db.Query(query, startOfDay, endOfDay)
+	rows, err := d.db.Query(query, start.UTC(), end.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to query time slots: %w", err)
 	}
 	defer rows.Close()
 
-	var slots []*models.TimeSlot
-	for rows.Next() {
-		var ts models.TimeSlot
-		var endTime sql.NullTime
-
-		err := rows.Scan(
-			&ts.ID,
-			&ts.TaskName,
-			&ts.StartTime,
-			&endTime,
-			&ts.DurationSeconds,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan time slot: %w", err)
-		}
-
-		if endTime.Valid {
-			ts.EndTime = &endTime.Time
-		}
-
-		slots = append(slots, &ts)
-	}
-
-	return slots, rows.Err()
+	return d.scanTimeSlots(rows)
 }
 
 // GetTaskStatistics returns aggregated statistics by task name for a specific date
 func (d *Database) GetTaskStatistics(date time.Time) (map[string]int64, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	return d.GetTaskStatisticsForRange(startOfDay, endOfDay)
+}
 
+// GetTaskStatisticsForRange returns aggregated statistics by task name
+// for completed slots starting in [start, end)
+func (d *Database) GetTaskStatisticsForRange(start time.Time, end time.Time) (map[string]int64, error) {
+	return d.taskStatisticsForRange(d.db, start, end, "")
+}
+
+// GetTaskStatisticsForKind returns task statistics for slots of one kind starting in [start, end)
+func (d *Database) GetTaskStatisticsForKind(start time.Time, end time.Time, kind string) (map[string]int64, error) {
+	return d.taskStatisticsForRange(d.db, start, end, kind)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// taskStatisticsForRange runs the task statistics query on q
+// An empty kind includes slots of every kind
+func (d *Database) taskStatisticsForRange(q queryer, start time.Time, end time.Time, kind string) (map[string]int64, error) {
 	query := `SELECT task_name, SUM(duration_seconds) as total_seconds
 	          FROM time_slots 
 	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL
+	          AND (? = '' OR kind = ?)
 	          GROUP BY task_name
 	          ORDER BY total_seconds DESC`
 
-	rows, err := d.db.Query(query, startOfDay, endOfDay)
+	rows, err := q.Query(query, start.UTC(), end.UTC(), kind, kind)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query task statistics: %w", err)
 	}
@@ -219,6 +743,9 @@ func (d *Database) GetTaskStatistics(date time.Time) (map[string]int64, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan statistics: %w", err)
 		}
+		if taskName, err = d.decodeName(taskName); err != nil {
+			return nil, err
+		}
 
 		stats[taskName] = totalSeconds
 	}
@@ -226,70 +753,1152 @@ func (d *Database) GetTaskStatistics(date time.Time) (map[string]int64, error) {
 	return stats, rows.Err()
 }
 
-// UpdateTimeSlot updates a time slot
-func (d *Database) UpdateTimeSlot(id int64, taskName string, startTime time.Time, endTime *time.Time) error {
-	var durationSeconds int64
-	if endTime != nil {
-		durationSeconds = int64(endTime.Sub(startTime).Seconds())
-	}
-
-	query := `UPDATE time_slots 
-	          SET task_name = ?, start_time = ?, end_time = ?, duration_seconds = ?
-	          WHERE id = ?`
+// GetTaskTotals returns per-task totals and session counts for completed slots
+// starting in [start, end), most tracked task first
+// An empty kind includes slots of every kind
+func (d *Database) GetTaskTotals(start time.Time, end time.Time, kind string) ([]TaskTotal, error) {
+	query := `SELECT task_name, SUM(duration_seconds) as total_seconds, COUNT(*)
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL
+	          AND (? = '' OR kind = ?)
+	          GROUP BY task_name
+	          ORDER BY total_seconds DESC`
 
-	_, err := d.db.Exec(query, taskName, startTime, endTime, durationSeconds, id)
+	rows, err := d.db.Query(query, start.UTC(), end.UTC(), kind, kind)
 	if err != nil {
-		return fmt.Errorf("failed to update time slot: %w", err)
+		return nil, fmt.Errorf("failed to query task totals: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	totals := []TaskTotal{}
+	for rows.Next() {
+		var total TaskTotal
+		if err := rows.Scan(&total.TaskName, &total.TotalSeconds, &total.Sessions); err != nil {
+			return nil, fmt.Errorf("failed to scan task total: %w", err)
+		}
+		if total.TaskName, err = d.decodeName(total.TaskName); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
 }
 
-// DeleteTimeSlot deletes a time slot
-func (d *Database) DeleteTimeSlot(id int64) error {
-	query := `DELETE FROM time_slots WHERE id = ?`
-	_, err := d.db.Exec(query, id)
+// GetDailyTotals returns the seconds tracked per local day ("2006-01-02")
+// for completed slots starting in [start, end)
+// Days are grouped in Go because SQLite's localtime may not match the app's timezone
+// An empty kind includes slots of every kind
+func (d *Database) GetDailyTotals(start time.Time, end time.Time, kind string) (map[string]int64, error) {
+	return d.bucketTotals(start, end, dayBucket, `? = '' OR kind = ?`, kind, kind)
+}
+
+// GetTaskDailyTotals returns the seconds tracked on one task per local day ("2006-01-02")
+// for completed slots starting in [start, end)
+func (d *Database) GetTaskDailyTotals(taskName string, start time.Time, end time.Time) (map[string]int64, error) {
+	storedName, err := d.encodeName(taskName)
 	if err != nil {
-		return fmt.Errorf("failed to delete time slot: %w", err)
+		return nil, err
 	}
-	return nil
+	return d.bucketTotals(start, end, dayBucket, `task_name = ?`, storedName)
 }
 
-// GetAllTimeSlots returns all time slots (for debugging/admin purposes)
-func (d *Database) GetAllTimeSlots() ([]*models.TimeSlot, error) {
-	query := `SELECT id, task_name, start_time, end_time, duration_seconds 
-	          FROM time_slots 
-	          ORDER BY start_time DESC`
-
-	rows, err := d.db.Query(query)
+// GetTrendTotals returns the seconds tracked per bucket, as keyed by bucketKey, for
+// completed slots starting in [start, end); an empty task name sums all work slots
+func (d *Database) GetTrendTotals(taskName string, start time.Time, end time.Time, bucketKey func(time.Time) string) (map[string]int64, error) {
+	if taskName == "" {
+		return d.bucketTotals(start, end, bucketKey, `kind = ?`, models.KindWork)
+	}
+	storedName, err := d.encodeName(taskName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query all time slots: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
+	return d.bucketTotals(start, end, bucketKey, `task_name = ?`, storedName)
+}
 
-	var slots []*models.TimeSlot
+// dayBucket keys a local time by its date
+func dayBucket(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// bucketTotals sums completed slots starting in [start, end) and matching filter
+// per bucketKey of their local start time
+// Buckets are computed here rather than with strftime because times are stored
+// in UTC and SQLite doesn't know the local time zone's daylight saving rules
+func (d *Database) bucketTotals(start time.Time, end time.Time, bucketKey func(time.Time) string, filter string, args ...any) (map[string]int64, error) {
+	query := `SELECT start_time, duration_seconds
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL
+	          AND (` + filter + `)`
+
+	rows, err := d.db.Query(query, append([]any{start.UTC(), end.UTC()}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var startTime time.Time
+		var durationSeconds int64
+		if err := rows.Scan(&startTime, &durationSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan daily total: %w", err)
+		}
+		totals[bucketKey(startTime.In(start.Location()))] += durationSeconds
+	}
+
+	return totals, rows.Err()
+}
+
+// GetTrackedDates returns the distinct local dates ("2006-01-02") that have at least
+// one slot starting in [start, end), in ascending order
+// Start times are deduplicated per minute in SQL and converted to local dates in Go:
+// every timezone offset is a whole number of minutes, so this is exact while SQLite's
+// own localtime conversion may not match the app's timezone
+func (d *Database) GetTrackedDates(start time.Time, end time.Time) ([]string, error) {
+	query := `SELECT DISTINCT strftime('%Y-%m-%d %H:%M', start_time) AS minute
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ?
+	          ORDER BY minute ASC`
+
+	rows, err := d.db.Query(query, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked dates: %w", err)
+	}
+	defer rows.Close()
+
+	dates := []string{}
 	for rows.Next() {
-		var ts models.TimeSlot
-		var endTime sql.NullTime
-
-		err := rows.Scan(
-			&ts.ID,
-			&ts.TaskName,
-			&ts.StartTime,
-			&endTime,
-			&ts.DurationSeconds,
-		)
+		var minute string
+		if err := rows.Scan(&minute); err != nil {
+			return nil, fmt.Errorf("failed to scan tracked date: %w", err)
+		}
+		t, err := time.Parse("2006-01-02 15:04", minute)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan time slot: %w", err)
+			return nil, fmt.Errorf("failed to parse tracked date: %w", err)
 		}
 
-		if endTime.Valid {
-			ts.EndTime = &endTime.Time
+		date := t.In(start.Location()).Format("2006-01-02")
+		if len(dates) == 0 || dates[len(dates)-1] != date {
+			dates = append(dates, date)
 		}
+	}
 
-		slots = append(slots, &ts)
+	return dates, rows.Err()
+}
+
+// GetGrandTotal returns the total seconds and number of all completed slots
+func (d *Database) GetGrandTotal() (int64, int64, error) {
+	query := `SELECT COALESCE(SUM(duration_seconds), 0), COUNT(*)
+	          FROM time_slots
+	          WHERE end_time IS NOT NULL`
+
+	var totalSeconds, slotCount int64
+	if err := d.db.QueryRow(query).Scan(&totalSeconds, &slotCount); err != nil {
+		return 0, 0, fmt.Errorf("failed to get grand total: %w", err)
 	}
 
-	return slots, rows.Err()
+	return totalSeconds, slotCount, nil
+}
+
+// GetFirstStartTime returns the start of the earliest slot, or nil if there are none
+func (d *Database) GetFirstStartTime() (*time.Time, error) {
+	var startTime time.Time
+	err := d.db.QueryRow(`SELECT start_time FROM time_slots ORDER BY start_time ASC LIMIT 1`).Scan(&startTime)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first start time: %w", err)
+	}
+
+	startTime = startTime.Local()
+	return &startTime, nil
+}
+
+// GetTopTask returns the task with the highest total duration among completed slots
+// starting in [start, end), or an empty name if there are none
+func (d *Database) GetTopTask(start time.Time, end time.Time) (string, int64, error) {
+	query := `SELECT task_name, SUM(duration_seconds) as total_seconds
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL
+	          GROUP BY task_name
+	          ORDER BY total_seconds DESC
+	          LIMIT 1`
+
+	var taskName string
+	var totalSeconds int64
+	err := d.db.QueryRow(query, start.UTC(), end.UTC()).Scan(&taskName, &totalSeconds)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get top task: %w", err)
+	}
+
+	taskName, err = d.decodeName(taskName)
+	if err != nil {
+		return "", 0, err
+	}
+	return taskName, totalSeconds, nil
+}
+
+// UpdateTimeSlot updates a time slot
+// Paused time is kept and still excluded from the duration; giving a paused
+// active slot an end time finishes its pause at that end
+func (d *Database) UpdateTimeSlot(id int64, taskName string, startTime time.Time, endTime *time.Time) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	err = withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			if err := retimeSlot(tx, id, startTime, endTime); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`UPDATE time_slots SET task_name = ? WHERE id = ?`, storedName, id)
+			return err
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update time slot: %w", err)
+	}
+
+	return nil
+}
+
+// retimeSlot sets the start and end time of a slot within tx and recomputes its duration
+// A nil end keeps the slot active
+func retimeSlot(tx *sql.Tx, id int64, startTime time.Time, endTime *time.Time) error {
+	slot, err := readSlotTimes(tx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("time slot %d %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	slot.StartTime = startTime
+
+	var endTimeUTC, pausedAtUTC *time.Time
+	if endTime != nil {
+		et := endTime.UTC()
+		endTimeUTC = &et
+		slot.EndTime = endTime
+		slot.PausedSeconds = int64(slot.PausedUntil(*endTime).Seconds())
+		slot.PausedAt = nil
+		slot.CalculateDuration()
+		if slot.DurationSeconds < 0 {
+			slot.DurationSeconds = 0
+		}
+	} else if slot.PausedAt != nil {
+		pa := slot.PausedAt.UTC()
+		pausedAtUTC = &pa
+	}
+
+	query := `UPDATE time_slots 
+	          SET start_time = ?, end_time = ?, duration_seconds = ?, paused_seconds = ?, paused_at = ?
+	          WHERE id = ?`
+
+	_, err = tx.Exec(query, startTime.UTC(), endTimeUTC, slot.DurationSeconds, slot.PausedSeconds, pausedAtUTC, id)
+	return err
+}
+
+// SlotRetime is a new start and end time for a time slot; a nil end keeps it active
+type SlotRetime struct {
+	ID        int64
+	StartTime time.Time
+	EndTime   *time.Time
+}
+
+// RetimeTimeSlots applies several time changes in one transaction and recomputes
+// the durations. If any change fails or any edited slot ends up overlapping
+// another slot, nothing is written. The active slot is checked up to now.
+func (d *Database) RetimeTimeSlots(edits []SlotRetime, now time.Time) error {
+	err := withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			for _, e := range edits {
+				if err := retimeSlot(tx, e.ID, e.StartTime, e.EndTime); err != nil {
+					return err
+				}
+			}
+
+			// Check once all edits are in, so slots may swap places
+			for _, e := range edits {
+				end := now
+				if e.EndTime != nil {
+					end = *e.EndTime
+				}
+				var otherID int64
+				err := tx.QueryRow(`SELECT id FROM time_slots
+				                    WHERE id != ? AND start_time < ? AND COALESCE(end_time, ?) > ?
+				                    LIMIT 1`, e.ID, end.UTC(), now.UTC(), e.StartTime.UTC()).Scan(&otherID)
+				if err == nil {
+					return fmt.Errorf("%w: time slot %d would overlap time slot %d", ErrOverlap, e.ID, otherID)
+				}
+				if err != sql.ErrNoRows {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply timeline edits: %w", err)
+	}
+	return nil
+}
+
+// RenameTimeSlot changes the task name of a time slot, leaving its times untouched
+func (d *Database) RenameTimeSlot(id int64, taskName string) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE time_slots SET task_name = ? WHERE id = ?`
+	err = withRetry(func() error {
+		_, err := d.db.Exec(query, storedName, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename time slot: %w", err)
+	}
+	return nil
+}
+
+// SetTimeSlotPause stores the pause state of an active time slot
+// pausedAt is nil when the slot is running
+func (d *Database) SetTimeSlotPause(id int64, pausedSeconds int64, pausedAt *time.Time) error {
+	var pausedAtUTC *time.Time
+	if pausedAt != nil {
+		pa := pausedAt.UTC()
+		pausedAtUTC = &pa
+	}
+
+	query := `UPDATE time_slots SET paused_seconds = ?, paused_at = ? WHERE id = ? AND end_time IS NULL`
+	err := withRetry(func() error {
+		_, err := d.db.Exec(query, pausedSeconds, pausedAtUTC, id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pause state: %w", err)
+	}
+	return nil
+}
+
+// SetTimeSlotNotes replaces the notes of a time slot
+func (d *Database) SetTimeSlotNotes(id int64, notes string) error {
+	storedNotes, err := d.encodeText(notes)
+	if err != nil {
+		return err
+	}
+
+	err = withRetry(func() error {
+		result, err := d.db.Exec(`UPDATE time_slots SET notes = ? WHERE id = ?`, storedNotes, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("time slot %d %w", id, ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+	return nil
+}
+
+// SetTimeSlotRef sets the external reference of a time slot; empty clears it
+func (d *Database) SetTimeSlotRef(id int64, externalRef string) error {
+	err := withRetry(func() error {
+		result, err := d.db.Exec(`UPDATE time_slots SET external_ref = ? WHERE id = ?`, externalRef, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("time slot %d %w", id, ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update external reference: %w", err)
+	}
+	return nil
+}
+
+// SetTimeSlotEstimate sets how long a time slot is expected to take; 0 clears it
+func (d *Database) SetTimeSlotEstimate(id int64, estimateSeconds int64) error {
+	err := withRetry(func() error {
+		result, err := d.db.Exec(`UPDATE time_slots SET estimate_seconds = ? WHERE id = ?`, estimateSeconds, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("time slot %d %w", id, ErrNotFound)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update estimate: %w", err)
+	}
+	return nil
+}
+
+// GetEstimateTotals returns the estimated and actual seconds per task of completed
+// slots with an estimate starting in [start, end), ordered by task name
+func (d *Database) GetEstimateTotals(start time.Time, end time.Time) ([]EstimateAccuracy, error) {
+	query := `SELECT task_name, COUNT(*), SUM(estimate_seconds), SUM(duration_seconds)
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL AND estimate_seconds > 0
+	          GROUP BY task_name`
+
+	rows, err := d.db.Query(query, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query estimate totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []EstimateAccuracy
+	for rows.Next() {
+		var total EstimateAccuracy
+		if err := rows.Scan(&total.TaskName, &total.Sessions, &total.EstimatedSeconds, &total.ActualSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan estimate total: %w", err)
+		}
+		if total.TaskName, err = d.decodeName(total.TaskName); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Encrypted names don't sort by their plaintext, so sort after decoding
+	sort.Slice(totals, func(i, j int) bool { return totals[i].TaskName < totals[j].TaskName })
+	return totals, nil
+}
+
+// GetLeftRunningCounts returns, per task with at least one such slot, how many
+// completed work slots starting in [start, end) were auto-stopped or lasted longer
+// than longDuration
+func (d *Database) GetLeftRunningCounts(start time.Time, end time.Time, longDuration time.Duration) ([]LeftRunningTask, error) {
+	query := `SELECT task_name, COUNT(*), SUM(auto_stopped), SUM(duration_seconds > ?),
+	                 SUM(auto_stopped OR duration_seconds > ?) AS flagged
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL AND kind = ?
+	          GROUP BY task_name
+	          HAVING flagged > 0`
+
+	longSeconds := int64(longDuration.Seconds())
+	rows, err := d.db.Query(query, longSeconds, longSeconds, start.UTC(), end.UTC(), models.KindWork)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks left running: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []LeftRunningTask{}
+	for rows.Next() {
+		var task LeftRunningTask
+		if err := rows.Scan(&task.TaskName, &task.Sessions, &task.AutoStopped, &task.LongSessions, &task.Flagged); err != nil {
+			return nil, fmt.Errorf("failed to scan task left running: %w", err)
+		}
+		if task.TaskName, err = d.decodeName(task.TaskName); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// GetTimeByRef returns the seconds tracked per external reference for completed
+// slots starting in [start, end); an empty ref includes every referenced slot
+func (d *Database) GetTimeByRef(externalRef string, start time.Time, end time.Time) (map[string]int64, error) {
+	query := `SELECT external_ref, SUM(duration_seconds)
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL
+	          AND external_ref != '' AND (? = '' OR external_ref = ?)
+	          GROUP BY external_ref`
+
+	rows, err := d.db.Query(query, start.UTC(), end.UTC(), externalRef, externalRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time by reference: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var ref string
+		var seconds int64
+		if err := rows.Scan(&ref, &seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan time by reference: %w", err)
+		}
+		totals[ref] = seconds
+	}
+
+	return totals, rows.Err()
 }
 
+// GetContextTotals returns the work seconds per context of completed slots starting
+// in [start, end); slots without a context are summed under ""
+func (d *Database) GetContextTotals(start time.Time, end time.Time) (map[string]int64, error) {
+	query := `SELECT context, SUM(duration_seconds)
+	          FROM time_slots
+	          WHERE start_time >= ? AND start_time < ? AND end_time IS NOT NULL AND kind = ?
+	          GROUP BY context`
+
+	rows, err := d.db.Query(query, start.UTC(), end.UTC(), models.KindWork)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query context totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int64)
+	for rows.Next() {
+		var storedContext string
+		var seconds int64
+		if err := rows.Scan(&storedContext, &seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan context total: %w", err)
+		}
+		slotContext, err := d.decodeText(storedContext)
+		if err != nil {
+			return nil, err
+		}
+		totals[slotContext] = seconds
+	}
+
+	return totals, rows.Err()
+}
+
+// SetTimeSlotStart changes the start time of an active time slot
+func (d *Database) SetTimeSlotStart(id int64, startTime time.Time) error {
+	query := `UPDATE time_slots SET start_time = ? WHERE id = ? AND end_time IS NULL`
+	err := withRetry(func() error {
+		_, err := d.db.Exec(query, startTime.UTC(), id)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update start time: %w", err)
+	}
+	return nil
+}
+
+// MergeIntoActiveSlot deletes a previous slot and moves the active slot's start to startTime
+func (d *Database) MergeIntoActiveSlot(previousID int64, activeID int64, startTime time.Time) error {
+	return withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			var pausedSeconds int64
+			err := tx.QueryRow(`SELECT paused_seconds FROM time_slots WHERE id = ?`, previousID).Scan(&pausedSeconds)
+			if err != nil {
+				return fmt.Errorf("failed to get previous time slot: %w", err)
+			}
+			if _, err := tx.Exec(`DELETE FROM time_slots WHERE id = ?`, previousID); err != nil {
+				return fmt.Errorf("failed to delete previous time slot: %w", err)
+			}
+			if _, err := tx.Exec(`UPDATE interruptions SET slot_id = ? WHERE slot_id = ?`, activeID, previousID); err != nil {
+				return fmt.Errorf("failed to move interruptions: %w", err)
+			}
+
+			// Pauses of the previous slot stay excluded from the merged slot
+			result, err := tx.Exec(`UPDATE time_slots SET start_time = ?, paused_seconds = paused_seconds + ?
+			                        WHERE id = ? AND end_time IS NULL`,
+				startTime.UTC(), pausedSeconds, activeID)
+			if err != nil {
+				return fmt.Errorf("failed to update start time: %w", err)
+			}
+			if n, err := result.RowsAffected(); err != nil || n == 0 {
+				return fmt.Errorf("active time slot %d %w", activeID, ErrNotFound)
+			}
+			return nil
+		})
+	})
+}
+
+// DeleteTimeSlot deletes a time slot and its interruptions
+func (d *Database) DeleteTimeSlot(id int64) error {
+	err := withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`DELETE FROM interruptions WHERE slot_id = ?`, id); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM time_slots WHERE id = ?`, id)
+			return err
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete time slot: %w", err)
+	}
+	return nil
+}
+
+// PruneOlderThan deletes completed slots that started before cutoff and their
+// interruptions, and returns how many slots were deleted; the active slot is never pruned
+func (d *Database) PruneOlderThan(cutoff time.Time) (int64, error) {
+	var pruned int64
+	err := withRetry(func() error {
+		return d.withTx(func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM interruptions WHERE slot_id IN (`+archivedSlotIDs+`)`, cutoff.UTC())
+			if err != nil {
+				return err
+			}
+			result, err := tx.Exec(`DELETE FROM time_slots WHERE end_time IS NOT NULL AND start_time < ?`, cutoff.UTC())
+			if err != nil {
+				return err
+			}
+			pruned, err = result.RowsAffected()
+			return err
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune time slots: %w", err)
+	}
+
+	return pruned, nil
+}
+
+// RecalculateDurations recomputes the duration of every completed slot from its
+// start, end and paused time and returns how many stored durations were wrong
+func (d *Database) RecalculateDurations() (int64, error) {
+	var fixed int64
+	err := withRetry(func() error {
+		fixed = 0
+		return d.withTx(func(tx *sql.Tx) error {
+			rows, err := tx.Query(`SELECT id, start_time, end_time, paused_seconds, duration_seconds
+			                       FROM time_slots WHERE end_time IS NOT NULL`)
+			if err != nil {
+				return err
+			}
+
+			wrong := make(map[int64]int64)
+			for rows.Next() {
+				var slot models.TimeSlot
+				var endTime time.Time
+				var stored int64
+				if err := rows.Scan(&slot.ID, &slot.StartTime, &endTime, &slot.PausedSeconds, &stored); err != nil {
+					rows.Close()
+					return err
+				}
+				slot.EndTime = &endTime
+				slot.CalculateDuration()
+				if slot.DurationSeconds < 0 {
+					slot.DurationSeconds = 0
+				}
+				if slot.DurationSeconds != stored {
+					wrong[slot.ID] = slot.DurationSeconds
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			for id, duration := range wrong {
+				if _, err := tx.Exec(`UPDATE time_slots SET duration_seconds = ? WHERE id = ?`, duration, id); err != nil {
+					return err
+				}
+			}
+			fixed = int64(len(wrong))
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to recalculate durations: %w", err)
+	}
+
+	return fixed, nil
+}
+
+// GetAllTimeSlots returns all time slots (for debugging/admin purposes)
+func (d *Database) GetAllTimeSlots() ([]*models.TimeSlot, error) {
+	query := `SELECT ` + timeSlotColumns + `
+	          FROM time_slots 
+	          ORDER BY start_time DESC`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query all time slots: %w", err)
+	}
+	defer rows.Close()
+
+	return d.scanTimeSlots(rows)
+}
+
+// GetTaskNames returns all distinct task names in alphabetical order
+func (d *Database) GetTaskNames() ([]string, error) {
+	query := `SELECT DISTINCT task_name FROM time_slots`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan task name: %w", err)
+		}
+		if name, err = d.decodeName(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sorted here since encrypted names can't be ordered in SQL
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetRecentTaskNames returns all distinct task names, most recently used first
+func (d *Database) GetRecentTaskNames() ([]string, error) {
+	query := `SELECT task_name FROM time_slots GROUP BY task_name ORDER BY MAX(start_time) DESC`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent task names: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan task name: %w", err)
+		}
+		if name, err = d.decodeName(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// SetTaskColor stores the color for a task, replacing any previous one
+// An empty color removes the stored color
+func (d *Database) SetTaskColor(taskName string, color string) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	if color == "" {
+		_, err := d.db.Exec(`DELETE FROM task_colors WHERE task_name = ?`, storedName)
+		if err != nil {
+			return fmt.Errorf("failed to reset task color: %w", err)
+		}
+		return nil
+	}
+
+	query := `INSERT INTO task_colors (task_name, color) VALUES (?, ?)
+	          ON CONFLICT(task_name) DO UPDATE SET color = excluded.color`
+
+	_, err = d.db.Exec(query, storedName, color)
+	if err != nil {
+		return fmt.Errorf("failed to set task color: %w", err)
+	}
+	return nil
+}
+
+// GetTaskColors returns all user-chosen task colors keyed by task name
+func (d *Database) GetTaskColors() (map[string]string, error) {
+	rows, err := d.db.Query(`SELECT task_name, color FROM task_colors`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task colors: %w", err)
+	}
+	defer rows.Close()
+
+	colors := make(map[string]string)
+	for rows.Next() {
+		var taskName, color string
+		if err := rows.Scan(&taskName, &color); err != nil {
+			return nil, fmt.Errorf("failed to scan task color: %w", err)
+		}
+		if taskName, err = d.decodeName(taskName); err != nil {
+			return nil, err
+		}
+		colors[taskName] = color
+	}
+
+	return colors, rows.Err()
+}
+
+// SetTaskProject assigns a task to a project, replacing any previous one
+// An empty project removes the assignment
+func (d *Database) SetTaskProject(taskName string, project string) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	if project == "" {
+		_, err := d.db.Exec(`DELETE FROM task_projects WHERE task_name = ?`, storedName)
+		if err != nil {
+			return fmt.Errorf("failed to reset task project: %w", err)
+		}
+		return nil
+	}
+
+	storedProject, err := d.encodeText(project)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO task_projects (task_name, project) VALUES (?, ?)
+	          ON CONFLICT(task_name) DO UPDATE SET project = excluded.project`
+
+	_, err = d.db.Exec(query, storedName, storedProject)
+	if err != nil {
+		return fmt.Errorf("failed to set task project: %w", err)
+	}
+	return nil
+}
+
+// GetTaskProjects returns the project of every assigned task keyed by task name
+func (d *Database) GetTaskProjects() (map[string]string, error) {
+	rows, err := d.db.Query(`SELECT task_name, project FROM task_projects`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make(map[string]string)
+	for rows.Next() {
+		var taskName, project string
+		if err := rows.Scan(&taskName, &project); err != nil {
+			return nil, fmt.Errorf("failed to scan task project: %w", err)
+		}
+		if taskName, err = d.decodeName(taskName); err != nil {
+			return nil, err
+		}
+		if project, err = d.decodeText(project); err != nil {
+			return nil, err
+		}
+		projects[taskName] = project
+	}
+
+	return projects, rows.Err()
+}
+
+// SetTaskRate stores the hourly rate of a task, replacing any previous one
+func (d *Database) SetTaskRate(taskName string, ratePerHour float64, currency string) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO task_rates (task_name, rate_per_hour, currency) VALUES (?, ?, ?)
+	          ON CONFLICT(task_name) DO UPDATE SET rate_per_hour = excluded.rate_per_hour, currency = excluded.currency`
+
+	_, err = d.db.Exec(query, storedName, ratePerHour, currency)
+	if err != nil {
+		return fmt.Errorf("failed to set task rate: %w", err)
+	}
+	return nil
+}
+
+// DeleteTaskRate removes the hourly rate of a task
+func (d *Database) DeleteTaskRate(taskName string) error {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM task_rates WHERE task_name = ?`, storedName); err != nil {
+		return fmt.Errorf("failed to delete task rate: %w", err)
+	}
+	return nil
+}
+
+// GetTaskRates returns the hourly rate of every task that has one, keyed by task name
+func (d *Database) GetTaskRates() (map[string]TaskRate, error) {
+	rows, err := d.db.Query(`SELECT task_name, rate_per_hour, currency FROM task_rates`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make(map[string]TaskRate)
+	for rows.Next() {
+		var taskName string
+		var rate TaskRate
+		if err := rows.Scan(&taskName, &rate.RatePerHour, &rate.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan task rate: %w", err)
+		}
+		if taskName, err = d.decodeName(taskName); err != nil {
+			return nil, err
+		}
+		rates[taskName] = rate
+	}
+
+	return rates, rows.Err()
+}
+
+// CreateRecurringTask stores a validated recurring task and returns its id
+func (d *Database) CreateRecurringTask(task RecurringTask) (int64, error) {
+	storedName, err := d.encodeName(task.TaskName)
+	if err != nil {
+		return 0, err
+	}
+	mask, err := weekdayMask(task.Weekdays)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := d.db.Exec(`INSERT INTO recurring_tasks (task_name, start_clock, end_clock, weekdays, action, last_run)
+	                          VALUES (?, ?, ?, ?, ?, ?)`,
+		storedName, task.Start, task.End, mask, task.Action, task.LastRun)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create recurring task: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateRecurringTask replaces a recurring task; its last run date is kept
+func (d *Database) UpdateRecurringTask(task RecurringTask) error {
+	storedName, err := d.encodeName(task.TaskName)
+	if err != nil {
+		return err
+	}
+	mask, err := weekdayMask(task.Weekdays)
+	if err != nil {
+		return err
+	}
+
+	result, err := d.db.Exec(`UPDATE recurring_tasks SET task_name = ?, start_clock = ?, end_clock = ?, weekdays = ?, action = ?
+	                          WHERE id = ?`,
+		storedName, task.Start, task.End, mask, task.Action, task.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring task: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("recurring task %d %w", task.ID, ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteRecurringTask deletes a recurring task; slots it already logged are kept
+func (d *Database) DeleteRecurringTask(id int64) error {
+	if _, err := d.db.Exec(`DELETE FROM recurring_tasks WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete recurring task: %w", err)
+	}
+	return nil
+}
+
+// GetRecurringTasks returns all recurring tasks ordered by start time
+func (d *Database) GetRecurringTasks() ([]RecurringTask, error) {
+	rows, err := d.db.Query(`SELECT id, task_name, start_clock, end_clock, weekdays, action, last_run
+	                         FROM recurring_tasks ORDER BY start_clock, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recurring tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []RecurringTask{}
+	for rows.Next() {
+		var task RecurringTask
+		var mask int
+		if err := rows.Scan(&task.ID, &task.TaskName, &task.Start, &task.End, &mask, &task.Action, &task.LastRun); err != nil {
+			return nil, fmt.Errorf("failed to scan recurring task: %w", err)
+		}
+		if task.TaskName, err = d.decodeName(task.TaskName); err != nil {
+			return nil, err
+		}
+		task.Weekdays = maskWeekdays(mask)
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// ClaimRecurringRun marks the occurrence of a recurring task on date as handled
+// It reports false when the occurrence was already claimed
+func (d *Database) ClaimRecurringRun(id int64, date string) (bool, error) {
+	var claimed bool
+	err := d.withTx(func(tx *sql.Tx) error {
+		var err error
+		claimed, err = claimRecurringRun(tx, id, date)
+		return err
+	})
+	return claimed, err
+}
+
+// LogRecurringRun claims the occurrence of a recurring task on date and records
+// it as a completed slot from start to end in the same transaction
+// The slot is nil when the occurrence was already claimed
+func (d *Database) LogRecurringRun(id int64, date string, taskName string, start time.Time, end time.Time) (*models.TimeSlot, error) {
+	storedName, err := d.encodeName(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	var slot *models.TimeSlot
+	err = d.withTx(func(tx *sql.Tx) error {
+		claimed, err := claimRecurringRun(tx, id, date)
+		if err != nil || !claimed {
+			return err
+		}
+
+		durationSeconds := int64(end.Sub(start).Seconds())
+		result, err := tx.Exec(`INSERT INTO time_slots (task_name, start_time, end_time, duration_seconds) VALUES (?, ?, ?, ?)`,
+			storedName, start.UTC(), end.UTC(), durationSeconds)
+		if err != nil {
+			return fmt.Errorf("failed to create time slot: %w", err)
+		}
+		slotID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		slot = &models.TimeSlot{
+			ID:              slotID,
+			TaskName:        taskName,
+			StartTime:       start,
+			EndTime:         &end,
+			DurationSeconds: durationSeconds,
+			Kind:            models.KindWork,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// RecordPomodoro stores a finished pomodoro and returns its id
+func (d *Database) RecordPomodoro(pomodoro Pomodoro) (int64, error) {
+	storedName, err := d.encodeName(pomodoro.TaskName)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := d.db.Exec(`INSERT INTO pomodoros (task_name, start_time, end_time, focus_seconds, completed)
+	                          VALUES (?, ?, ?, ?, ?)`,
+		storedName, pomodoro.StartTime.UTC(), pomodoro.EndTime.UTC(), pomodoro.FocusSeconds, pomodoro.Completed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record pomodoro: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return id, nil
+}
+
+// GetPomodoroStats counts the completed and interrupted pomodoros started in
+// [start, end) and sums their focus time
+func (d *Database) GetPomodoroStats(start time.Time, end time.Time) (*PomodoroStats, error) {
+	stats := &PomodoroStats{}
+	err := d.db.QueryRow(`SELECT COALESCE(SUM(completed), 0), COALESCE(SUM(NOT completed), 0), COALESCE(SUM(focus_seconds), 0)
+	                      FROM pomodoros WHERE start_time >= ? AND start_time < ?`,
+		start.UTC(), end.UTC()).Scan(&stats.Completed, &stats.Interrupted, &stats.FocusSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pomodoro stats: %w", err)
+	}
+	return stats, nil
+}
+
+// AddInterruption marks an interruption of a slot at a point in time
+func (d *Database) AddInterruption(slotID int64, at time.Time, note string) (*Interruption, error) {
+	storedNote, err := d.encodeText(note)
+	if err != nil {
+		return nil, err
+	}
+
+	var result sql.Result
+	err = withRetry(func() error {
+		var err error
+		result, err = d.db.Exec(`INSERT INTO interruptions (slot_id, time, note) VALUES (?, ?, ?)`,
+			slotID, at.UTC(), storedNote)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add interruption: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return &Interruption{ID: id, SlotID: slotID, Time: at, Note: note}, nil
+}
+
+// GetInterruptions returns the interruptions of a slot ordered by time
+func (d *Database) GetInterruptions(slotID int64) ([]Interruption, error) {
+	rows, err := d.db.Query(`SELECT id, slot_id, time, note FROM interruptions WHERE slot_id = ? ORDER BY time, id`, slotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interruptions: %w", err)
+	}
+	defer rows.Close()
+
+	interruptions := []Interruption{}
+	for rows.Next() {
+		var interruption Interruption
+		if err := rows.Scan(&interruption.ID, &interruption.SlotID, &interruption.Time, &interruption.Note); err != nil {
+			return nil, fmt.Errorf("failed to scan interruption: %w", err)
+		}
+		if interruption.Note, err = d.decodeText(interruption.Note); err != nil {
+			return nil, err
+		}
+		interruption.Time = interruption.Time.Local()
+		interruptions = append(interruptions, interruption)
+	}
+
+	return interruptions, rows.Err()
+}
+
+// CountInterruptions counts the interruptions marked in [start, end) on slots
+// that still exist
+func (d *Database) CountInterruptions(start time.Time, end time.Time) (int, error) {
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM interruptions
+	                      WHERE time >= ? AND time < ? AND slot_id IN (SELECT id FROM time_slots)`,
+		start.UTC(), end.UTC()).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count interruptions: %w", err)
+	}
+	return count, nil
+}
+
+// claimRecurringRun sets the last run date of a recurring task within tx unless
+// it already is date or later
+func claimRecurringRun(tx *sql.Tx, id int64, date string) (bool, error) {
+	result, err := tx.Exec(`UPDATE recurring_tasks SET last_run = ? WHERE id = ? AND last_run < ?`, date, id, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim recurring task: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim recurring task: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetOverlappingTimeSlots returns slots other than excludeID that overlap [start, end)
+// Active slots are treated as running until now
+func (d *Database) GetOverlappingTimeSlots(excludeID int64, start time.Time, end time.Time) ([]*models.TimeSlot, error) {
+	query := `SELECT ` + timeSlotColumns + `
+	          FROM time_slots
+	          WHERE id != ? AND start_time < ? AND (end_time IS NULL OR end_time > ?)
+	          ORDER BY start_time ASC`
+
+	rows, err := d.db.Query(query, excludeID, end.UTC(), start.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overlapping time slots: %w", err)
+	}
+	defer rows.Close()
+
+	return d.scanTimeSlots(rows)
+}
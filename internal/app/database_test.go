@@ -0,0 +1,490 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// newTestDatabase returns an empty in-memory database
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	db, err := NewDatabaseAt(":memory:")
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// countRows counts the rows of a table in the database
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&count); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return count
+}
+
+// seedInterruptedSlots creates a completed slot before cutoff and one after it,
+// each with an interruption, and returns them in that order
+func seedInterruptedSlots(t *testing.T, db *Database, cutoff time.Time) (old int64, recent int64) {
+	t.Helper()
+
+	for i, start := range []time.Time{cutoff.Add(-48 * time.Hour), cutoff.Add(time.Hour)} {
+		slot, err := db.CreateCompletedTimeSlot("Task", start, start.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+		if _, err := db.AddInterruption(slot.ID, start.Add(30*time.Minute), "call"); err != nil {
+			t.Fatalf("AddInterruption: %v", err)
+		}
+		if i == 0 {
+			old = slot.ID
+		} else {
+			recent = slot.ID
+		}
+	}
+	return old, recent
+}
+
+func TestPruneOlderThanDeletesInterruptions(t *testing.T) {
+	db := newTestDatabase(t)
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	old, recent := seedInterruptedSlots(t, db, cutoff)
+
+	pruned, err := db.PruneOlderThan(cutoff)
+	if err != nil {
+		t.Fatalf("PruneOlderThan: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d slots, want 1", pruned)
+	}
+
+	if got, _ := db.GetInterruptions(old); len(got) != 0 {
+		t.Errorf("pruned slot still has %d interruptions", len(got))
+	}
+	if got, _ := db.GetInterruptions(recent); len(got) != 1 {
+		t.Errorf("kept slot has %d interruptions, want 1", len(got))
+	}
+	if n := countRows(t, db.db, "interruptions"); n != 1 {
+		t.Errorf("%d interruptions left, want 1", n)
+	}
+}
+
+func TestArchiveBeforeMovesInterruptions(t *testing.T) {
+	db := newTestDatabase(t)
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	old, recent := seedInterruptedSlots(t, db, cutoff)
+
+	archivePath := filepath.Join(t.TempDir(), "archive.db")
+	moved, err := db.ArchiveBefore(cutoff, archivePath)
+	if err != nil {
+		t.Fatalf("ArchiveBefore: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("moved %d slots, want 1", moved)
+	}
+
+	if got, _ := db.GetInterruptions(old); len(got) != 0 {
+		t.Errorf("archived slot still has %d interruptions in the live database", len(got))
+	}
+	if got, _ := db.GetInterruptions(recent); len(got) != 1 {
+		t.Errorf("kept slot has %d interruptions, want 1", len(got))
+	}
+
+	archive, err := sql.Open("sqlite", archivePath)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer archive.Close()
+
+	var slotID int64
+	if err := archive.QueryRow(`SELECT slot_id FROM interruptions`).Scan(&slotID); err != nil {
+		t.Fatalf("query archived interruptions: %v", err)
+	}
+	if slotID != old {
+		t.Errorf("archived interruption belongs to slot %d, want %d", slotID, old)
+	}
+	if n := countRows(t, archive, "interruptions"); n != 1 {
+		t.Errorf("%d archived interruptions, want 1", n)
+	}
+}
+
+func TestQueryArchiveSpecialPath(t *testing.T) {
+	db := newTestDatabase(t)
+	cutoff := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	old, _ := seedInterruptedSlots(t, db, cutoff)
+
+	// ? and # would start the query or the fragment of a plain "file:" DSN
+	archivePath := filepath.Join(t.TempDir(), "old #1 ?100%", "archive.db")
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if _, err := db.ArchiveBefore(cutoff, archivePath); err != nil {
+		t.Fatalf("ArchiveBefore: %v", err)
+	}
+
+	slots, err := db.QueryArchive(archivePath, cutoff.Add(-72*time.Hour), cutoff)
+	if err != nil {
+		t.Fatalf("QueryArchive: %v", err)
+	}
+	if len(slots) != 1 || slots[0].ID != old {
+		t.Fatalf("QueryArchive = %+v, want slot %d", slots, old)
+	}
+}
+
+// holdWriteLock takes the write lock of the SQLite file at path on another
+// connection and releases it after hold
+func holdWriteLock(t *testing.T, path string, hold time.Duration) {
+	t.Helper()
+
+	locker, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open locker: %v", err)
+	}
+	conn, err := locker.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("locker connection: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), `BEGIN IMMEDIATE`); err != nil {
+		t.Fatalf("take write lock: %v", err)
+	}
+
+	released := make(chan struct{})
+	t.Cleanup(func() {
+		<-released
+		locker.Close()
+	})
+	go func() {
+		defer close(released)
+		time.Sleep(hold)
+		conn.ExecContext(context.Background(), `COMMIT`)
+		conn.Close()
+	}()
+}
+
+// TestWritesRetryWhileBusy checks that timer writes wait for a concurrent writer
+// through withRetry rather than failing with SQLITE_BUSY
+func TestWritesRetryWhileBusy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	setup, err := NewDatabaseAt(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	slot, err := setup.CreateTimeSlot("Design", models.KindWork, "", "", time.Now().Add(-time.Hour))
+	setup.Close()
+	if err != nil {
+		t.Fatalf("CreateTimeSlot: %v", err)
+	}
+
+	// Without busy_timeout a locked database fails at once, so only withRetry can wait
+	raw, err := sql.Open("sqlite", path+"?_time_format=sqlite")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	db := &Database{db: raw}
+	defer db.Close()
+
+	holdWriteLock(t, path, 150*time.Millisecond)
+	_, err = raw.Exec(`UPDATE time_slots SET task_name = 'x' WHERE id = ?`, slot.ID)
+	if !isBusyError(err) {
+		t.Fatalf("write on the locked database = %v, want SQLITE_BUSY", err)
+	}
+	if err := db.RenameTimeSlot(slot.ID, "Review"); err != nil {
+		t.Fatalf("RenameTimeSlot while busy: %v", err)
+	}
+
+	newStart := slot.StartTime.Add(-30 * time.Minute)
+	holdWriteLock(t, path, 150*time.Millisecond)
+	if err := db.SetTimeSlotStart(slot.ID, newStart); err != nil {
+		t.Fatalf("SetTimeSlotStart while busy: %v", err)
+	}
+
+	stored, err := db.GetTimeSlot(slot.ID)
+	if err != nil {
+		t.Fatalf("GetTimeSlot: %v", err)
+	}
+	if stored.TaskName != "Review" || !stored.StartTime.Equal(newStart) {
+		t.Errorf("stored slot = %q from %v, want %q from %v", stored.TaskName, stored.StartTime, "Review", newStart)
+	}
+}
+
+// TestTimestampsSurviveTimezoneChange writes a slot under one local timezone and
+// reads it back under another, as after travelling with the laptop
+func TestTimestampsSurviveTimezoneChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "travel.db")
+	tokyo := setLocal(t, "Asia/Tokyo")
+
+	start := time.Date(2026, 3, 10, 8, 30, 0, 0, tokyo)
+	end := start.Add(45 * time.Minute)
+	db, err := NewDatabaseAt(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	slot, err := db.CreateTimeSlot("Standup", models.KindWork, "", "", start)
+	if err != nil {
+		t.Fatalf("CreateTimeSlot: %v", err)
+	}
+	if err := db.StopTimeSlot(slot.ID, end); err != nil {
+		t.Fatalf("StopTimeSlot: %v", err)
+	}
+	var stored string
+	if err := db.db.QueryRow(`SELECT CAST(start_time AS TEXT) FROM time_slots WHERE id = ?`, slot.ID).Scan(&stored); err != nil {
+		t.Fatalf("query stored start: %v", err)
+	}
+	db.Close()
+	if want := start.UTC().Format("2006-01-02 15:04:05"); !strings.HasPrefix(stored, want) {
+		t.Errorf("stored start = %q, want UTC %s", stored, want)
+	}
+
+	newYork := setLocal(t, "America/New_York")
+	db, err = NewDatabaseAt(path)
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	defer db.Close()
+
+	got, err := db.GetTimeSlot(slot.ID)
+	if err != nil {
+		t.Fatalf("GetTimeSlot: %v", err)
+	}
+	if !got.StartTime.Equal(start) || got.EndTime == nil || !got.EndTime.Equal(end) {
+		t.Errorf("slot = %v to %v, want %v to %v", got.StartTime, got.EndTime, start, end)
+	}
+	if got.StartTime.Location() != newYork {
+		t.Errorf("start location = %v, want %v", got.StartTime.Location(), newYork)
+	}
+	if got.DurationSeconds != 45*60 {
+		t.Errorf("duration = %ds, want %ds", got.DurationSeconds, 45*60)
+	}
+
+	// 08:30 in Tokyo is 19:30 the previous evening in New York
+	for date, want := range map[string]int{"2026-03-09": 1, "2026-03-10": 0} {
+		day, _ := parseDate(date)
+		slots, err := db.GetTimeSlotsByDate(day)
+		if err != nil {
+			t.Fatalf("GetTimeSlotsByDate: %v", err)
+		}
+		if len(slots) != want {
+			t.Errorf("%s has %d slots, want %d", date, len(slots), want)
+		}
+	}
+}
+
+// TestConcurrentStopAndUpdate stops a slot while another goroutine moves its
+// start; whichever write wins, the stored duration matches the stored times
+func TestConcurrentStopAndUpdate(t *testing.T) {
+	db, err := NewDatabaseAt(filepath.Join(t.TempDir(), "concurrent.db"))
+	if err != nil {
+		t.Fatalf("NewDatabaseAt: %v", err)
+	}
+	defer db.Close()
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	for i := range 30 {
+		start := base.Add(time.Duration(i) * time.Hour)
+		slot, err := db.CreateTimeSlot("Task", models.KindWork, "", "", start)
+		if err != nil {
+			t.Fatalf("CreateTimeSlot: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		ready := make(chan struct{})
+		errs := make(chan error, 2)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-ready
+			errs <- db.StopTimeSlot(slot.ID, start.Add(40*time.Minute))
+		}()
+		go func() {
+			defer wg.Done()
+			<-ready
+			errs <- db.UpdateTimeSlot(slot.ID, "Edited", start.Add(-20*time.Minute), nil)
+		}()
+		close(ready)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("concurrent write: %v", err)
+			}
+		}
+
+		got, err := db.GetTimeSlot(slot.ID)
+		if err != nil {
+			t.Fatalf("GetTimeSlot: %v", err)
+		}
+		if got.TaskName != "Edited" || !got.StartTime.Equal(start.Add(-20*time.Minute)) {
+			t.Fatalf("slot = %q from %v, want the update applied", got.TaskName, got.StartTime)
+		}
+		// Either the update reopened the stopped slot or the stop used the new start
+		var want int64
+		if got.EndTime != nil {
+			want = int64(got.EndTime.Sub(got.StartTime).Seconds())
+		}
+		if got.DurationSeconds != want {
+			t.Fatalf("iteration %d: duration = %ds with end %v, want %ds", i, got.DurationSeconds, got.EndTime, want)
+		}
+		if got.EndTime == nil {
+			if err := db.StopTimeSlot(slot.ID, start.Add(50*time.Minute)); err != nil {
+				t.Fatalf("StopTimeSlot: %v", err)
+			}
+		}
+	}
+}
+
+func TestRecalculateDurations(t *testing.T) {
+	db := newTestDatabase(t)
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	// Each slot is an hour long; the SQL seeds the mismatched duration
+	seeds := []struct {
+		name string
+		seed string
+		want int64
+	}{
+		{"Correct", ``, 3600},
+		{"Zero", `UPDATE time_slots SET duration_seconds = 0 WHERE id = ?`, 3600},
+		{"Too long", `UPDATE time_slots SET duration_seconds = 99999 WHERE id = ?`, 3600},
+		{"Paused", `UPDATE time_slots SET paused_seconds = 600, duration_seconds = 0 WHERE id = ?`, 3000},
+	}
+	ids := make([]int64, len(seeds))
+	for i, seed := range seeds {
+		slotStart := start.Add(time.Duration(i) * 2 * time.Hour)
+		slot, err := db.CreateCompletedTimeSlot(seed.name, slotStart, slotStart.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+		ids[i] = slot.ID
+		if seed.seed != "" {
+			if _, err := db.db.Exec(seed.seed, slot.ID); err != nil {
+				t.Fatalf("seed %s: %v", seed.name, err)
+			}
+		}
+	}
+	active, err := db.CreateTimeSlot("Running", "work", "", "", start.Add(10*time.Hour))
+	if err != nil {
+		t.Fatalf("CreateTimeSlot: %v", err)
+	}
+
+	fixed, err := db.RecalculateDurations()
+	if err != nil {
+		t.Fatalf("RecalculateDurations: %v", err)
+	}
+	if fixed != 3 {
+		t.Errorf("fixed %d slots, want 3", fixed)
+	}
+	for i, seed := range seeds {
+		slot, err := db.GetTimeSlot(ids[i])
+		if err != nil {
+			t.Fatalf("GetTimeSlot: %v", err)
+		}
+		if slot.DurationSeconds != seed.want {
+			t.Errorf("%s: duration = %ds, want %ds", seed.name, slot.DurationSeconds, seed.want)
+		}
+	}
+	if slot, _ := db.GetTimeSlot(active.ID); !slot.IsActive() || slot.DurationSeconds != 0 {
+		t.Errorf("running slot changed to %+v", slot)
+	}
+
+	if fixed, err := db.RecalculateDurations(); err != nil || fixed != 0 {
+		t.Errorf("second RecalculateDurations = %d, %v, want nothing left to fix", fixed, err)
+	}
+}
+
+func TestMakePrivateDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits don't apply on Windows")
+	}
+	base := t.TempDir()
+
+	created := filepath.Join(base, "created")
+	if err := makePrivateDir(created); err != nil {
+		t.Fatalf("makePrivateDir: %v", err)
+	}
+	if info, err := os.Lstat(created); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("created directory = %v, %v, want mode 0700", info, err)
+	}
+
+	// A directory left by an older version is kept and restricted
+	existing := filepath.Join(base, "existing")
+	if err := os.Mkdir(existing, 0755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	if err := makePrivateDir(existing); err != nil {
+		t.Fatalf("makePrivateDir on an existing directory: %v", err)
+	}
+	if info, err := os.Lstat(existing); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("existing directory = %v, %v, want mode 0700", info, err)
+	}
+
+	// Someone else may plant a symlink or a file in the temp directory
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(created, link); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	if err := makePrivateDir(link); err == nil {
+		t.Error("makePrivateDir accepted a symlink")
+	}
+	file := filepath.Join(base, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := makePrivateDir(file); err == nil {
+		t.Error("makePrivateDir accepted a file")
+	}
+}
+
+func TestSwitchTimeSlot(t *testing.T) {
+	db := newTestDatabase(t)
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	first, err := db.CreateTimeSlot("Design", models.KindWork, "", "", start)
+	if err != nil {
+		t.Fatalf("CreateTimeSlot: %v", err)
+	}
+	switchAt := start.Add(30 * time.Minute)
+	second, err := db.SwitchTimeSlot(first.ID, switchAt, "Review", models.KindWork, "PROJ-1", "", switchAt)
+	if err != nil {
+		t.Fatalf("SwitchTimeSlot: %v", err)
+	}
+
+	stopped, err := db.GetTimeSlot(first.ID)
+	if err != nil {
+		t.Fatalf("GetTimeSlot: %v", err)
+	}
+	if stopped.IsActive() || stopped.DurationSeconds != 1800 {
+		t.Errorf("first slot = %+v, want it stopped after 30m", stopped)
+	}
+	active, err := db.GetActiveTimeSlot()
+	if err != nil {
+		t.Fatalf("GetActiveTimeSlot: %v", err)
+	}
+	if active == nil || active.ID != second.ID || active.TaskName != "Review" || active.ExternalRef != "PROJ-1" {
+		t.Errorf("active slot = %+v, want the new Review slot %d", active, second.ID)
+	}
+
+	// A failed stop rolls back the new slot too
+	if _, err := db.SwitchTimeSlot(second.ID+100, switchAt.Add(time.Hour), "Lost", models.KindWork, "", "", switchAt.Add(time.Hour)); err == nil {
+		t.Fatal("SwitchTimeSlot stopped a missing slot")
+	}
+	if active, err := db.GetActiveTimeSlot(); err != nil || active == nil || active.ID != second.ID {
+		t.Errorf("active slot after a failed switch = %+v, %v, want slot %d", active, err, second.ID)
+	}
+	if names, err := db.GetTaskNames(); err != nil || len(names) != 2 {
+		t.Errorf("task names after a failed switch = %v, %v, want Design and Review", names, err)
+	}
+}
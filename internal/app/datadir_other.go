@@ -0,0 +1,21 @@
+//go:build !windows
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkDirOwner fails unless the current user owns the directory described by info
+func checkDirOwner(info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("unknown owner")
+	}
+	if int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("owned by user %d, not by the current user %d", stat.Uid, os.Getuid())
+	}
+	return nil
+}
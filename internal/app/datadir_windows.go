@@ -0,0 +1,9 @@
+package app
+
+import "os"
+
+// checkDirOwner accepts any directory: the temp directory on Windows is in the
+// user's profile, which other users can't write to
+func checkDirOwner(info os.FileInfo) error {
+	return nil
+}
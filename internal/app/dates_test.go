@@ -0,0 +1,140 @@
+package app
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// setLocal runs the rest of the test with time.Local set to the named zone
+// Tests using it must not run in parallel
+func setLocal(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", name, err)
+	}
+	previous := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = previous })
+	return loc
+}
+
+func TestParseDateIsLocalMidnight(t *testing.T) {
+	tests := []struct {
+		zone string
+		date string
+	}{
+		{"America/New_York", "2026-03-08"}, // clocks go forward at 02:00
+		{"America/New_York", "2026-11-01"}, // clocks go back at 02:00
+		{"Europe/Berlin", "2026-03-29"},
+		{"Asia/Tokyo", "2026-01-01"},
+		{"Pacific/Auckland", "2026-04-05"},
+		{"America/Sao_Paulo", "2026-06-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.date, func(t *testing.T) {
+			loc := setLocal(t, tt.zone)
+
+			got, err := parseDate(tt.date)
+			if err != nil {
+				t.Fatalf("parseDate: %v", err)
+			}
+			if got.Location() != loc {
+				t.Errorf("location = %v, want %v", got.Location(), loc)
+			}
+			if got.Format("2006-01-02 15:04") != tt.date+" 00:00" {
+				t.Errorf("parseDate(%q) = %v, want local midnight", tt.date, got)
+			}
+		})
+	}
+}
+
+func TestParseDateRangeDayLengths(t *testing.T) {
+	tests := []struct {
+		name   string
+		zone   string
+		start  string
+		end    string
+		length time.Duration
+	}{
+		{"spring forward", "America/New_York", "2026-03-08", "2026-03-08", 23 * time.Hour},
+		{"fall back", "America/New_York", "2026-11-01", "2026-11-01", 25 * time.Hour},
+		{"week over a DST change", "Europe/Berlin", "2026-03-23", "2026-03-29", 7*24*time.Hour - time.Hour},
+		{"ordinary day", "Asia/Tokyo", "2026-07-01", "2026-07-01", 24 * time.Hour},
+		{"southern hemisphere fall back", "Pacific/Auckland", "2026-04-05", "2026-04-05", 25 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLocal(t, tt.zone)
+
+			start, end, err := parseDateRange(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("parseDateRange: %v", err)
+			}
+			if got := end.Sub(start); got != tt.length {
+				t.Errorf("range length = %v, want %v", got, tt.length)
+			}
+			// The range ends at the next local midnight, not 24 hours later
+			if end.Hour() != 0 || end.Minute() != 0 {
+				t.Errorf("range ends at %v, want local midnight", end)
+			}
+		})
+	}
+}
+
+func TestParseDateRangeInvalid(t *testing.T) {
+	tests := []struct {
+		start string
+		end   string
+	}{
+		{"2026-03-10", "2026-03-09"},
+		{"2026-13-01", "2026-13-02"},
+		{"2026-03-10", "10.03.2026"},
+		{"", "2026-03-10"},
+	}
+	for _, tt := range tests {
+		if _, _, err := parseDateRange(tt.start, tt.end); err == nil {
+			t.Errorf("parseDateRange(%q, %q) succeeded", tt.start, tt.end)
+		}
+	}
+}
+
+// TestDayBucketingNearMidnight checks that slots just before and after local
+// midnight land on their local day at several UTC offsets
+func TestDayBucketingNearMidnight(t *testing.T) {
+	zones := []string{"America/Los_Angeles", "America/New_York", "Europe/Berlin", "Asia/Kolkata", "Asia/Tokyo", "Pacific/Auckland"}
+	for _, zone := range zones {
+		t.Run(zone, func(t *testing.T) {
+			loc := setLocal(t, zone)
+			a := newTestApp(t)
+
+			lateStart := time.Date(2026, 3, 9, 23, 30, 0, 0, loc)
+			if _, err := a.database.CreateCompletedTimeSlot("Late", lateStart, lateStart.Add(20*time.Minute)); err != nil {
+				t.Fatalf("CreateCompletedTimeSlot: %v", err)
+			}
+			earlyStart := time.Date(2026, 3, 10, 0, 10, 0, 0, loc)
+			if _, err := a.database.CreateCompletedTimeSlot("Early", earlyStart, earlyStart.Add(20*time.Minute)); err != nil {
+				t.Fatalf("CreateCompletedTimeSlot: %v", err)
+			}
+
+			for date, want := range map[string]string{"2026-03-09": "Late", "2026-03-10": "Early"} {
+				slots, err := a.GetTimeSlotsByDate(date)
+				if err != nil {
+					t.Fatalf("GetTimeSlotsByDate: %v", err)
+				}
+				if len(slots) != 1 || slots[0].TaskName != want {
+					t.Errorf("%s slots = %v, want only %s", date, slots, want)
+				}
+				stats, err := a.GetTaskStatistics(date, 0, "")
+				if err != nil {
+					t.Fatalf("GetTaskStatistics: %v", err)
+				}
+				if len(stats) != 1 || stats[want] != 20*60 {
+					t.Errorf("%s statistics = %v, want %s 1200s", date, stats, want)
+				}
+			}
+		})
+	}
+}
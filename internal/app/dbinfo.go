@@ -0,0 +1,81 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// DBInfo describes the database file and how much data it holds
+type DBInfo struct {
+	Path string `json:"path"`
+	// SizeBytes is the file size on disk; when the file can't be read it is the
+	// size SQLite reports for the pages in use
+	SizeBytes     int64  `json:"size_bytes"`
+	RowCount      int64  `json:"row_count"` // stored time slots, the active one included
+	HasActiveSlot bool   `json:"has_active_slot"`
+	FirstDate     string `json:"first_date"` // "2006-01-02", empty without slots
+	LastDate      string `json:"last_date"`
+	// Fallback is set when the data directory in the home directory couldn't be
+	// used; Temporary when the database lives in the temp directory, which the
+	// system may clear
+	Fallback  bool `json:"fallback"`
+	Temporary bool `json:"temporary"`
+}
+
+// Info returns the file path, size and contents summary of the database
+func (d *Database) Info() (*DBInfo, error) {
+	path, err := d.mainPath()
+	if err != nil {
+		return nil, err
+	}
+	info := &DBInfo{Path: path, Fallback: d.dataDir.fallback, Temporary: d.dataDir.temporary}
+
+	if err := d.db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(end_time IS NULL), 0) > 0 FROM time_slots`).
+		Scan(&info.RowCount, &info.HasActiveSlot); err != nil {
+		return nil, fmt.Errorf("failed to count time slots: %w", err)
+	}
+
+	if info.FirstDate, err = d.edgeDate("ASC"); err != nil {
+		return nil, err
+	}
+	if info.LastDate, err = d.edgeDate("DESC"); err != nil {
+		return nil, err
+	}
+
+	stat, statErr := os.Stat(path)
+	if statErr == nil {
+		info.SizeBytes = stat.Size()
+		return info, nil
+	}
+
+	// The file may not exist yet, be unreadable or the database lives in memory;
+	// the page count still gives its size
+	if path != "" {
+		log.Printf("Failed to stat database file: %v", statErr)
+	}
+	err = d.db.QueryRow(`SELECT page_count * page_size FROM pragma_page_count, pragma_page_size`).
+		Scan(&info.SizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	return info, nil
+}
+
+// edgeDate returns the local start date of the first ("ASC") or last ("DESC")
+// slot, or "" when there are none
+func (d *Database) edgeDate(order string) (string, error) {
+	var startTime time.Time
+	err := d.db.QueryRow(`SELECT start_time FROM time_slots ORDER BY start_time ` + order + ` LIMIT 1`).
+		Scan(&startTime)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get tracked dates: %w", err)
+	}
+	return startTime.Local().Format("2006-01-02"), nil
+}
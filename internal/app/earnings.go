@@ -0,0 +1,156 @@
+package app
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// currencyPattern matches ISO 4217 currency codes such as "USD"
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencySymbols are prefixed to amounts; other currencies get their code appended
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+}
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+}
+
+// TaskRate is the hourly rate of a task
+type TaskRate struct {
+	RatePerHour float64 `json:"rate_per_hour"`
+	Currency    string  `json:"currency"`
+}
+
+// TaskEarnings is what the time on one task earned in a period
+type TaskEarnings struct {
+	TaskName    string  `json:"task_name"`
+	Seconds     int64   `json:"seconds"`
+	RatePerHour float64 `json:"rate_per_hour"`
+	Currency    string  `json:"currency"`
+	Amount      float64 `json:"amount"`
+	Formatted   string  `json:"formatted"`
+}
+
+// CurrencyTotal is the sum of earnings in one currency
+type CurrencyTotal struct {
+	Currency  string  `json:"currency"`
+	Amount    float64 `json:"amount"`
+	Formatted string  `json:"formatted"`
+}
+
+// EarningsReport lists the earnings per task with a total per currency
+// Amounts in different currencies are never added up. Tasks without a rate
+// are listed in NoRate with their time only.
+type EarningsReport struct {
+	Tasks  []TaskEarnings  `json:"tasks"`
+	Totals []CurrencyTotal `json:"totals"`
+	NoRate []TaskTotal     `json:"no_rate"`
+}
+
+// isValidCurrency reports whether currency is an upper-case ISO 4217 code
+func isValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+// roundMoney rounds an amount to the minor unit of its currency
+func roundMoney(amount float64, currency string) float64 {
+	if zeroDecimalCurrencies[currency] {
+		return math.Round(amount)
+	}
+	return math.Round(amount*100) / 100
+}
+
+// formatMoney formats an amount with thousands separators, e.g. "$1,234.50" or "1,234.50 CHF"
+func formatMoney(amount float64, currency string) string {
+	decimals := 2
+	if zeroDecimalCurrencies[currency] {
+		decimals = 0
+	}
+	number := strconv.FormatFloat(math.Abs(amount), 'f', decimals, 64)
+
+	whole, fraction, _ := strings.Cut(number, ".")
+	var b strings.Builder
+	if amount < 0 {
+		b.WriteString("-")
+	}
+	if symbol, ok := currencySymbols[currency]; ok {
+		b.WriteString(symbol)
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(",")
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString("." + fraction)
+	}
+	if _, ok := currencySymbols[currency]; !ok {
+		b.WriteString(" " + currency)
+	}
+	return b.String()
+}
+
+// buildEarningsReport multiplies each task's hours by its rate
+// Tasks are sorted by amount, then name; totals by currency
+func buildEarningsReport(tasks []TaskTotal, rates map[string]TaskRate) *EarningsReport {
+	report := &EarningsReport{
+		Tasks:  []TaskEarnings{},
+		Totals: []CurrencyTotal{},
+		NoRate: []TaskTotal{},
+	}
+	totals := make(map[string]float64)
+
+	for _, task := range tasks {
+		if task.TotalSeconds <= 0 {
+			continue
+		}
+		rate, ok := rates[task.TaskName]
+		if !ok {
+			report.NoRate = append(report.NoRate, task)
+			continue
+		}
+
+		amount := roundMoney(float64(task.TotalSeconds)/3600*rate.RatePerHour, rate.Currency)
+		report.Tasks = append(report.Tasks, TaskEarnings{
+			TaskName:    task.TaskName,
+			Seconds:     task.TotalSeconds,
+			RatePerHour: rate.RatePerHour,
+			Currency:    rate.Currency,
+			Amount:      amount,
+			Formatted:   formatMoney(amount, rate.Currency),
+		})
+		totals[rate.Currency] += amount
+	}
+
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		if report.Tasks[i].Amount != report.Tasks[j].Amount {
+			return report.Tasks[i].Amount > report.Tasks[j].Amount
+		}
+		return report.Tasks[i].TaskName < report.Tasks[j].TaskName
+	})
+
+	for currency, amount := range totals {
+		amount = roundMoney(amount, currency)
+		report.Totals = append(report.Totals, CurrencyTotal{
+			Currency:  currency,
+			Amount:    amount,
+			Formatted: formatMoney(amount, currency),
+		})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+
+	return report
+}
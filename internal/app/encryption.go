@@ -0,0 +1,297 @@
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Task names are encrypted field by field because modernc.org/sqlite has no
+// page-level encryption. Encryption is deterministic (the nonce is derived from
+// the plaintext), so equal names give equal ciphertexts and GROUP BY, DISTINCT
+// and lookups by name keep working. The tradeoff is that someone with the file
+// can still see which slots share a task, as well as all times and durations.
+const (
+	encryptedPrefix    = "enc:v1:"
+	kdfIterations      = 600000
+	kdfSaltSize        = 16
+	minPassphraseLen   = 8
+	encryptionVerifier = "light-tracking"
+)
+
+var (
+	// ErrDatabaseLocked is returned when an encrypted database is used before UnlockDatabase
+	ErrDatabaseLocked = errors.New("database is encrypted and locked, unlock it with the passphrase first")
+	// ErrWrongPassphrase is returned when the passphrase doesn't match the database
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+// nameCipher encrypts task names with AES-256-GCM
+type nameCipher struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// newNameCipher derives the encryption and nonce keys from passphrase with PBKDF2
+func newNameCipher(passphrase string, salt []byte) (*nameCipher, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nameCipher{aead: aead, macKey: key[32:]}, nil
+}
+
+// encrypt returns the stored form of plaintext
+func (c *nameCipher) encrypt(plaintext string) string {
+	mac := hmac.New(sha256.New, c.macKey)
+	mac.Write([]byte(plaintext))
+	nonce := mac.Sum(nil)[:c.aead.NonceSize()]
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed)
+}
+
+// decrypt reverses encrypt
+func (c *nameCipher) decrypt(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// loadEncryptionState marks the database as encrypted and locked if a key was set up
+func (d *Database) loadEncryptionState() error {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM encryption`).Scan(&count); err != nil {
+		return fmt.Errorf("failed to read encryption state: %w", err)
+	}
+
+	d.cipherMu.Lock()
+	defer d.cipherMu.Unlock()
+	d.encrypted = count > 0
+	return nil
+}
+
+// IsEncrypted reports whether task names are stored encrypted
+func (d *Database) IsEncrypted() bool {
+	d.cipherMu.RLock()
+	defer d.cipherMu.RUnlock()
+	return d.encrypted
+}
+
+// IsLocked reports whether the database is encrypted and not yet unlocked
+func (d *Database) IsLocked() bool {
+	d.cipherMu.RLock()
+	defer d.cipherMu.RUnlock()
+	return d.encrypted && d.names == nil
+}
+
+// Unlock derives the key from passphrase and checks it against the stored verifier
+func (d *Database) Unlock(passphrase string) error {
+	var salt []byte
+	var verifier string
+	err := d.db.QueryRow(`SELECT salt, verifier FROM encryption WHERE id = 1`).Scan(&salt, &verifier)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("database is not encrypted")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	names, err := newNameCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if plaintext, err := names.decrypt(verifier); err != nil || plaintext != encryptionVerifier {
+		return ErrWrongPassphrase
+	}
+
+	d.cipherMu.Lock()
+	defer d.cipherMu.Unlock()
+	d.names = names
+	return nil
+}
+
+// EnableEncryption encrypts all stored task names with a key derived from passphrase
+// The database stays unlocked afterwards
+func (d *Database) EnableEncryption(passphrase string) error {
+	if len(passphrase) < minPassphraseLen {
+		return fmt.Errorf("passphrase must be at least %d characters", minPassphraseLen)
+	}
+	if d.IsEncrypted() {
+		return fmt.Errorf("database is already encrypted")
+	}
+
+	salt := make([]byte, kdfSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	names, err := newNameCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	err = d.withTx(func(tx *sql.Tx) error {
+		if err := encryptColumn(tx, names, `SELECT id, task_name FROM time_slots`,
+			`UPDATE time_slots SET task_name = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt time slots: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT id, notes FROM time_slots WHERE notes != ''`,
+			`UPDATE time_slots SET notes = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt notes: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT id, context FROM time_slots WHERE context != ''`,
+			`UPDATE time_slots SET context = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt contexts: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT rowid, task_name FROM task_colors`,
+			`UPDATE task_colors SET task_name = ? WHERE rowid = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt task colors: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT rowid, task_name FROM task_projects`,
+			`UPDATE task_projects SET task_name = ? WHERE rowid = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt task projects: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT rowid, project FROM task_projects`,
+			`UPDATE task_projects SET project = ? WHERE rowid = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt task projects: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT rowid, task_name FROM task_rates`,
+			`UPDATE task_rates SET task_name = ? WHERE rowid = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt task rates: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT id, task_name FROM recurring_tasks`,
+			`UPDATE recurring_tasks SET task_name = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt recurring tasks: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT id, task_name FROM pomodoros`,
+			`UPDATE pomodoros SET task_name = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt pomodoros: %w", err)
+		}
+		if err := encryptColumn(tx, names, `SELECT id, note FROM interruptions WHERE note != ''`,
+			`UPDATE interruptions SET note = ? WHERE id = ?`); err != nil {
+			return fmt.Errorf("failed to encrypt interruptions: %w", err)
+		}
+
+		_, err := tx.Exec(`INSERT INTO encryption (id, salt, verifier) VALUES (1, ?, ?)`,
+			salt, names.encrypt(encryptionVerifier))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	d.cipherMu.Lock()
+	defer d.cipherMu.Unlock()
+	d.encrypted = true
+	d.names = names
+	return nil
+}
+
+// encryptColumn rewrites every (id, value) row returned by selectQuery with updateQuery
+func encryptColumn(tx *sql.Tx, names *nameCipher, selectQuery, updateQuery string) error {
+	rows, err := tx.Query(selectQuery)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return err
+		}
+		values[id] = value
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, value := range values {
+		if _, err := tx.Exec(updateQuery, names.encrypt(value), id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeName returns the stored form of a task name
+func (d *Database) encodeName(name string) (string, error) {
+	d.cipherMu.RLock()
+	defer d.cipherMu.RUnlock()
+
+	if !d.encrypted {
+		return name, nil
+	}
+	if d.names == nil {
+		return "", ErrDatabaseLocked
+	}
+	return d.names.encrypt(name), nil
+}
+
+// decodeName reverses encodeName
+func (d *Database) decodeName(stored string) (string, error) {
+	d.cipherMu.RLock()
+	defer d.cipherMu.RUnlock()
+
+	if !d.encrypted {
+		return stored, nil
+	}
+	if d.names == nil {
+		return "", ErrDatabaseLocked
+	}
+	return d.names.decrypt(stored)
+}
+
+// encodeText returns the stored form of free text such as notes
+// Empty text is stored as is so "no notes" stays recognizable
+func (d *Database) encodeText(text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	return d.encodeName(text)
+}
+
+// decodeText reverses encodeText
+func (d *Database) decodeText(stored string) (string, error) {
+	if stored == "" {
+		return "", nil
+	}
+	return d.decodeName(stored)
+}
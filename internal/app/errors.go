@@ -0,0 +1,82 @@
+package app
+
+import (
+	"errors"
+)
+
+var (
+	// ErrEmptyTaskName is returned when a task name is empty after trimming
+	ErrEmptyTaskName = errors.New("task name cannot be empty")
+	// ErrNotFound is returned when a time slot doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrOverlap is returned when a change would make time slots overlap
+	ErrOverlap = errors.New("time slots overlap")
+	// ErrTimerNotRunning is returned by operations that need a running timer
+	ErrTimerNotRunning = errors.New("timer is not running")
+	// ErrTimerClosed is returned by timer operations after the app was closed
+	ErrTimerClosed = errors.New("timer is closed")
+	// ErrConfirmationRequired is returned instead of stopping a long session,
+	// see the ConfirmStopAfterMinutes setting
+	ErrConfirmationRequired = errors.New("confirmation required")
+)
+
+// Error codes sent to the frontend, see FormatError
+const (
+	ErrorCodeEmptyTaskName   = "empty_task_name"
+	ErrorCodeTaskNameTooLong = "task_name_too_long"
+	ErrorCodeNotFound        = "not_found"
+	ErrorCodeOverlap         = "overlap"
+	ErrorCodeTimerNotRunning = "timer_not_running"
+	ErrorCodeNoHistory       = "no_history"
+	ErrorCodeDatabaseLocked  = "database_locked"
+	ErrorCodeWrongPassphrase = "wrong_passphrase"
+	ErrorCodeConfirmation    = "confirmation_required"
+	ErrorCodeUnknown         = "unknown"
+)
+
+// errorCodes maps sentinel errors to their codes
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrEmptyTaskName, ErrorCodeEmptyTaskName},
+	{ErrTaskNameTooLong, ErrorCodeTaskNameTooLong},
+	{ErrNotFound, ErrorCodeNotFound},
+	{ErrOverlap, ErrorCodeOverlap},
+	{ErrTimerNotRunning, ErrorCodeTimerNotRunning},
+	{ErrNoHistory, ErrorCodeNoHistory},
+	{ErrDatabaseLocked, ErrorCodeDatabaseLocked},
+	{ErrWrongPassphrase, ErrorCodeWrongPassphrase},
+	{ErrConfirmationRequired, ErrorCodeConfirmation},
+}
+
+// BackendError is the error value bound methods reject with in the frontend
+type BackendError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the human-readable message
+func (e *BackendError) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the code of the first sentinel err wraps, or ErrorCodeUnknown
+func ErrorCode(err error) string {
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return ErrorCodeUnknown
+}
+
+// FormatError turns errors returned by bound methods into a BackendError,
+// so the frontend can branch on the code instead of matching English text.
+// It is set as the Wails ErrorFormatter in main.go.
+func FormatError(err error) any {
+	return &BackendError{
+		Code:    ErrorCode(err),
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import "math"
+
+// maxEstimateMinutes is the longest accepted estimate, one day
+const maxEstimateMinutes = 24 * 60
+
+// EstimateAccuracy compares the estimated and actual time of a task's estimated slots
+type EstimateAccuracy struct {
+	TaskName         string `json:"task_name"`
+	Sessions         int    `json:"sessions"`
+	EstimatedSeconds int64  `json:"estimated_seconds"`
+	ActualSeconds    int64  `json:"actual_seconds"`
+	// VarianceSeconds is actual minus estimated; positive means the task took longer
+	VarianceSeconds int64 `json:"variance_seconds"`
+	// VariancePercent is VarianceSeconds relative to the estimate, rounded to one decimal
+	VariancePercent float64 `json:"variance_percent"`
+}
+
+// withVariance fills in the variance fields from the estimated and actual totals
+func withVariance(totals []EstimateAccuracy) []EstimateAccuracy {
+	for i := range totals {
+		total := &totals[i]
+		total.VarianceSeconds = total.ActualSeconds - total.EstimatedSeconds
+		if total.EstimatedSeconds > 0 {
+			percent := float64(total.VarianceSeconds) * 100 / float64(total.EstimatedSeconds)
+			total.VariancePercent = math.Round(percent*10) / 10
+		}
+	}
+	return totals
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"light-tracking/internal/models"
+)
+
+// Events emitted to the frontend (and to Go listeners such as the systray)
+const (
+	// EventTimerStarted carries the active slot when the timer starts outside the
+	// timer view, e.g. when a stopped slot is reopened
+	EventTimerStarted = "timer:started"
+	// EventTimerRenamed carries the active slot after its task name changed
+	EventTimerRenamed = "timer:renamed"
+	// EventTimerAdjusted carries the active slot after its start time changed
+	EventTimerAdjusted = "timer:adjusted"
+	// EventTimerPaused carries the active slot after it was paused
+	EventTimerPaused = "timer:paused"
+	// EventTimerResumed carries the active slot after it was resumed
+	EventTimerResumed = "timer:resumed"
+	// EventTimerRecovered carries a RecoveredSlot when a running timer
+	// was found at startup and needs the user to keep, stop or discard it
+	EventTimerRecovered = "timer:recovered"
+	// EventTimerTick carries the elapsed seconds of the running timer
+	EventTimerTick = "timer:tick"
+	// EventNotification asks the frontend to show a notification in the window
+	// when no desktop notification backend is available
+	EventNotification = "notification:show"
+	// EventStillWorkingPrompt carries the active slot and asks the frontend to
+	// show a dialog confirming the user is still working on it
+	EventStillWorkingPrompt = "timer:still-working"
+	// EventTimerAutoStopped carries the slot stopped because a prompt went unanswered
+	EventTimerAutoStopped = "timer:auto-stopped"
+	// EventIdleDetected carries an IdlePeriod once the user is back after being
+	// idle while the timer ran, so the frontend can ask what to do with it
+	EventIdleDetected = "timer:idle"
+	// EventRecurringDue carries a RecurringTask with the prompt action when it comes up
+	EventRecurringDue = "recurring:due"
+	// EventDataChanged carries a DataChange after stored data was written, so
+	// views can refresh instead of polling
+	EventDataChanged = "data:changed"
+	// EventFocusModeChanged carries whether focus mode is now on
+	EventFocusModeChanged = "focus:changed"
+	// EventPomodoroFinished carries a Pomodoro once it was completed or interrupted
+	EventPomodoroFinished = "pomodoro:finished"
+)
+
+// Scopes of a DataChange
+const (
+	// ChangeScopeToday means only today's data changed
+	ChangeScopeToday = "today"
+	// ChangeScopeRange means data between StartDate and EndDate changed
+	ChangeScopeRange = "range"
+	// ChangeScopeAll means any data may have changed, e.g. after archiving
+	ChangeScopeAll = "all"
+)
+
+// DataChange is a coarse hint of what data changed
+type DataChange struct {
+	Scope string `json:"scope"`
+	// StartDate and EndDate are the first and last local dates ("2006-01-02")
+	// affected; empty for ChangeScopeAll
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
+
+// newDataChange scopes a change of data at times; no times means everything
+func newDataChange(now time.Time, times []time.Time) DataChange {
+	if len(times) == 0 {
+		return DataChange{Scope: ChangeScopeAll}
+	}
+
+	first, last := times[0], times[0]
+	for _, t := range times[1:] {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	change := DataChange{
+		Scope:     ChangeScopeRange,
+		StartDate: first.In(now.Location()).Format("2006-01-02"),
+		EndDate:   last.In(now.Location()).Format("2006-01-02"),
+	}
+	today := now.Format("2006-01-02")
+	if change.StartDate == today && change.EndDate == today {
+		change.Scope = ChangeScopeToday
+	}
+	return change
+}
+
+// dataChanged emits EventDataChanged for data changed at times
+// Call it after the write succeeded; without times everything may have changed
+func (a *App) dataChanged(times ...time.Time) {
+	a.emit(EventDataChanged, newDataChange(a.now(), times))
+}
+
+// slotsChanged emits EventDataChanged for the days slots span; an active slot
+// spans until now and nil slots are skipped, so nothing is emitted without slots
+func (a *App) slotsChanged(slots ...*models.TimeSlot) {
+	var times []time.Time
+	for _, slot := range slots {
+		if slot == nil {
+			continue
+		}
+		end := a.now()
+		if slot.EndTime != nil {
+			end = *slot.EndTime
+		}
+		times = append(times, slot.StartTime, end)
+	}
+	if len(times) > 0 {
+		a.dataChanged(times...)
+	}
+}
+
+// emit sends an event through the Wails runtime
+// Events emitted before Startup are dropped since there is no frontend yet
+func (a *App) emit(eventName string, data ...interface{}) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(a.ctx, eventName, data...)
+}
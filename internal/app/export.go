@@ -0,0 +1,226 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// Embedded so IANA export time zones also work where the OS has no zone database, e.g. Windows
+	_ "time/tzdata"
+
+	"light-tracking/internal/models"
+)
+
+// ExportTimeZoneLocal exports timestamps in the computer's time zone
+const ExportTimeZoneLocal = "local"
+
+// exportLocation resolves the ExportTimeZone setting; empty means local time
+func exportLocation(name string) (*time.Location, error) {
+	switch {
+	case name == "" || strings.EqualFold(name, ExportTimeZoneLocal):
+		return time.Local, nil
+	case strings.EqualFold(name, "UTC"):
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown export time zone %q, expected \"local\", \"UTC\" or an IANA name such as \"Europe/Berlin\"", name)
+	}
+	return location, nil
+}
+
+// markdownEscaper escapes characters with special meaning in Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"#", `\#`,
+)
+
+// buildDailyMarkdown renders a day's completed slots as a Markdown summary
+func buildDailyMarkdown(date time.Time, slots []*models.TimeSlot) string {
+	totals := make(map[string]int64)
+	sessions := make(map[string]int)
+	var total int64
+	var sessionCount int
+
+	for _, slot := range slots {
+		if slot.IsActive() {
+			continue
+		}
+		totals[slot.TaskName] += slot.DurationSeconds
+		sessions[slot.TaskName]++
+		total += slot.DurationSeconds
+		sessionCount++
+	}
+
+	var b strings.Builder
+	dateStr := date.Format("2006-01-02")
+
+	if sessionCount == 0 {
+		fmt.Fprintf(&b, "# %s\n\nNo time tracked\n", dateStr)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "# %s — %s\n\n", dateStr, formatDuration(time.Duration(total)*time.Second))
+
+	taskNames := make([]string, 0, len(totals))
+	for name := range totals {
+		taskNames = append(taskNames, name)
+	}
+	sort.Slice(taskNames, func(i, j int) bool {
+		if totals[taskNames[i]] != totals[taskNames[j]] {
+			return totals[taskNames[i]] > totals[taskNames[j]]
+		}
+		return taskNames[i] < taskNames[j]
+	})
+
+	for _, name := range taskNames {
+		fmt.Fprintf(&b, "- **%s**: %s (%s)\n",
+			markdownEscaper.Replace(name),
+			formatDuration(time.Duration(totals[name])*time.Second),
+			formatPlural(sessions[name], "session"))
+	}
+
+	fmt.Fprintf(&b, "\nSessions: %d\n", sessionCount)
+	return b.String()
+}
+
+// unassignedRef labels slots without an external reference in the issue time log
+const unassignedRef = "unassigned"
+
+// buildIssueTimeLog renders completed slots as one "ref: 2h 30m" line per
+// external reference, sorted by ref, with unreferenced time listed last
+func buildIssueTimeLog(slots []*models.TimeSlot) string {
+	totals := make(map[string]int64)
+	var unassigned int64
+	var hasUnassigned bool
+
+	for _, slot := range slots {
+		if slot.IsActive() {
+			continue
+		}
+		if slot.ExternalRef == "" {
+			unassigned += slot.DurationSeconds
+			hasUnassigned = true
+			continue
+		}
+		totals[slot.ExternalRef] += slot.DurationSeconds
+	}
+
+	refs := make([]string, 0, len(totals))
+	for ref := range totals {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var b strings.Builder
+	for _, ref := range refs {
+		fmt.Fprintf(&b, "%s: %s\n", ref, formatShortDuration(time.Duration(totals[ref])*time.Second))
+	}
+	if hasUnassigned {
+		fmt.Fprintf(&b, "%s: %s\n", unassignedRef, formatShortDuration(time.Duration(unassigned)*time.Second))
+	}
+	return b.String()
+}
+
+// ExportRow is a time slot as written to CSV and JSON exports
+type ExportRow struct {
+	ID              int64      `json:"id"`
+	TaskName        string     `json:"task_name"`
+	StartTime       time.Time  `json:"start_time"`
+	EndTime         *time.Time `json:"end_time"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	PausedSeconds   int64      `json:"paused_seconds"`
+	Kind            string     `json:"kind"`
+	ExternalRef     string     `json:"external_ref"`
+	Context         string     `json:"context"`
+	EstimateSeconds int64      `json:"estimate_seconds"`
+	AutoStopped     bool       `json:"auto_stopped"`
+	InProgress      bool       `json:"in_progress"`
+}
+
+// exportRows converts slots to export rows with their times in location.
+// With snapshotActive, an active slot gets end = now and its live duration so
+// spreadsheets don't show an empty end and zero duration; it stays marked in progress.
+func exportRows(slots []*models.TimeSlot, now time.Time, snapshotActive bool, location *time.Location) []ExportRow {
+	rows := make([]ExportRow, 0, len(slots))
+	for _, slot := range slots {
+		row := ExportRow{
+			ID:              slot.ID,
+			TaskName:        slot.TaskName,
+			StartTime:       slot.StartTime.In(location),
+			DurationSeconds: slot.DurationSeconds,
+			PausedSeconds:   slot.PausedSeconds,
+			Kind:            slot.Kind,
+			ExternalRef:     slot.ExternalRef,
+			Context:         slot.Context,
+			EstimateSeconds: slot.EstimateSeconds,
+			AutoStopped:     slot.AutoStopped,
+			InProgress:      slot.IsActive(),
+		}
+		if slot.EndTime != nil {
+			end := slot.EndTime.In(location)
+			row.EndTime = &end
+		}
+		if row.InProgress && snapshotActive {
+			end := now.In(location)
+			row.EndTime = &end
+			row.PausedSeconds = int64(slot.PausedUntil(now).Seconds())
+			row.DurationSeconds = int64(now.Sub(slot.StartTime).Seconds()) - row.PausedSeconds
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// buildCSV renders export rows as CSV with a header line
+// Times are RFC3339; the end time is empty for active slots that weren't snapshotted
+func buildCSV(rows []ExportRow) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"id", "task_name", "start_time", "end_time", "duration_seconds", "paused_seconds", "kind", "external_ref", "context", "estimate_seconds", "auto_stopped", "in_progress"})
+	for _, row := range rows {
+		endTime := ""
+		if row.EndTime != nil {
+			endTime = row.EndTime.Format(time.RFC3339)
+		}
+		w.Write([]string{
+			strconv.FormatInt(row.ID, 10),
+			row.TaskName,
+			row.StartTime.Format(time.RFC3339),
+			endTime,
+			strconv.FormatInt(row.DurationSeconds, 10),
+			strconv.FormatInt(row.PausedSeconds, 10),
+			row.Kind,
+			row.ExternalRef,
+			row.Context,
+			strconv.FormatInt(row.EstimateSeconds, 10),
+			strconv.FormatBool(row.AutoStopped),
+			strconv.FormatBool(row.InProgress),
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// buildJSON renders export rows as an indented JSON array
+func buildJSON(rows []ExportRow) (string, error) {
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newExportTestApp returns an app with a completed 09:00-09:30 "Plan" slot and a
+// "Build" slot started at 10:00 that has run 20 minutes and been paused for 10
+func newExportTestApp(t *testing.T) (*App, time.Time) {
+	t.Helper()
+
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a, clock := newClockedTestApp(t, day.Add(time.Hour))
+	if _, err := a.database.CreateCompletedTimeSlot("Plan", day, day.Add(30*time.Minute)); err != nil {
+		t.Fatalf("CreateCompletedTimeSlot: %v", err)
+	}
+	if _, err := a.StartTimer("Build"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(20 * time.Minute)
+	if err := a.PauseTimer(); err != nil {
+		t.Fatalf("PauseTimer: %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	return a, clock.Now()
+}
+
+// readCSV parses an export into header-keyed records
+func readCSV(t *testing.T, data string) []map[string]string {
+	t.Helper()
+
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(record))
+		for i, value := range record {
+			row[records[0][i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestExportCSVSnapshotsActiveSlot(t *testing.T) {
+	a, now := newExportTestApp(t)
+
+	data, err := a.ExportCSV("2026-03-10", "2026-03-10", true)
+	if err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	rows := readCSV(t, data)
+	if len(rows) != 2 {
+		t.Fatalf("exported %d rows, want 2", len(rows))
+	}
+
+	plan, build := rows[0], rows[1]
+	if plan["task_name"] != "Plan" || plan["duration_seconds"] != "1800" || plan["in_progress"] != "false" {
+		t.Errorf("completed row = %v", plan)
+	}
+	if build["task_name"] != "Build" || build["in_progress"] != "true" {
+		t.Errorf("active row = %v, want Build in progress", build)
+	}
+	if build["end_time"] != now.Format(time.RFC3339) {
+		t.Errorf("active end_time = %q, want now %q", build["end_time"], now.Format(time.RFC3339))
+	}
+	// 30 minutes since the start, the last 10 of them paused
+	if build["duration_seconds"] != "1200" || build["paused_seconds"] != "600" {
+		t.Errorf("active duration and pause = %s, %s, want 1200, 600", build["duration_seconds"], build["paused_seconds"])
+	}
+}
+
+func TestExportCSVKeepsActiveSlotRaw(t *testing.T) {
+	a, _ := newExportTestApp(t)
+
+	data, err := a.ExportCSV("2026-03-10", "2026-03-10", false)
+	if err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	rows := readCSV(t, data)
+	if len(rows) != 2 {
+		t.Fatalf("exported %d rows, want 2", len(rows))
+	}
+	if build := rows[1]; build["end_time"] != "" || build["duration_seconds"] != "0" || build["in_progress"] != "true" {
+		t.Errorf("active row = %v, want an empty end, zero duration and in progress", build)
+	}
+}
+
+func TestExportJSONActiveSlot(t *testing.T) {
+	a, now := newExportTestApp(t)
+
+	for _, snapshot := range []bool{true, false} {
+		data, err := a.ExportJSON("2026-03-10", "2026-03-10", snapshot)
+		if err != nil {
+			t.Fatalf("ExportJSON: %v", err)
+		}
+		var rows []ExportRow
+		if err := json.Unmarshal([]byte(data), &rows); err != nil {
+			t.Fatalf("decode export: %v", err)
+		}
+		if len(rows) != 2 || !rows[1].InProgress || rows[0].InProgress {
+			t.Fatalf("snapshot %v: rows = %+v, want the second in progress", snapshot, rows)
+		}
+
+		build := rows[1]
+		if snapshot {
+			if build.EndTime == nil || !build.EndTime.Equal(now) || build.DurationSeconds != 1200 {
+				t.Errorf("snapshotted row ends %v after %ds, want %v after 1200s", build.EndTime, build.DurationSeconds, now)
+			}
+		} else {
+			if build.EndTime != nil || build.DurationSeconds != 0 {
+				t.Errorf("raw row ends %v after %ds, want null and 0", build.EndTime, build.DurationSeconds)
+			}
+			if !strings.Contains(data, `"end_time": null`) {
+				t.Error("raw export doesn't keep a null end_time")
+			}
+		}
+	}
+
+	// Exporting doesn't touch the running slot
+	if state := a.GetTimerState(); !state.Paused || state.Slot.EndTime != nil {
+		t.Errorf("timer state after export = %+v", state)
+	}
+}
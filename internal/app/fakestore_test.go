@@ -0,0 +1,767 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// fakeStore is an in-memory SlotStore and StatsStore for Timer and App tests
+// It mirrors the Database semantics the timer and the statistics rely on, e.g.
+// stopping folds a pause in progress into the paused time and statistics only
+// count completed slots by their start; failNext makes the next call fail
+type fakeStore struct {
+	mu            sync.Mutex
+	slots         map[int64]*models.TimeSlot
+	nextID        int64
+	pomodoros     []Pomodoro
+	interruptions []Interruption
+	failNext      error
+}
+
+var (
+	_ SlotStore  = (*fakeStore)(nil)
+	_ StatsStore = (*fakeStore)(nil)
+)
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{slots: make(map[int64]*models.TimeSlot)}
+}
+
+// fail returns the error set with failNext once, nil otherwise
+// Caller must hold the lock
+func (s *fakeStore) fail() error {
+	err := s.failNext
+	s.failNext = nil
+	return err
+}
+
+// slot returns the stored slot with the given id
+// Caller must hold the lock
+func (s *fakeStore) slot(id int64) (*models.TimeSlot, error) {
+	slot, ok := s.slots[id]
+	if !ok {
+		return nil, fmt.Errorf("time slot %d %w", id, ErrNotFound)
+	}
+	return slot, nil
+}
+
+// get returns a copy of the stored slot with the given id, nil if there is none
+func (s *fakeStore) get(id int64) *models.TimeSlot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, ok := s.slots[id]
+	if !ok {
+		return nil
+	}
+	return copySlot(slot)
+}
+
+// active returns copies of the stored slots without an end time
+func (s *fakeStore) active() []*models.TimeSlot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var active []*models.TimeSlot
+	for _, slot := range s.slots {
+		if slot.IsActive() {
+			active = append(active, copySlot(slot))
+		}
+	}
+	return active
+}
+
+// add stores a copy of slot under a new id and returns the copy
+// Caller must hold the lock
+func (s *fakeStore) add(slot models.TimeSlot) *models.TimeSlot {
+	s.nextID++
+	slot.ID = s.nextID
+	s.slots[slot.ID] = &slot
+	return copySlot(&slot)
+}
+
+func copySlot(slot *models.TimeSlot) *models.TimeSlot {
+	c := *slot
+	if c.EndTime != nil {
+		end := *c.EndTime
+		c.EndTime = &end
+	}
+	if c.PausedAt != nil {
+		pausedAt := *c.PausedAt
+		c.PausedAt = &pausedAt
+	}
+	return &c
+}
+
+// stop ends a slot like the database's stopTimeSlot
+// Caller must hold the lock
+func (s *fakeStore) stop(id int64, endTime time.Time) error {
+	slot, err := s.slot(id)
+	if err != nil {
+		return err
+	}
+	slot.EndTime = &endTime
+	slot.PausedSeconds = int64(slot.PausedUntil(endTime).Seconds())
+	slot.PausedAt = nil
+	slot.CalculateDuration()
+	if slot.DurationSeconds < 0 {
+		slot.DurationSeconds = 0
+	}
+	return nil
+}
+
+func (s *fakeStore) CreateTimeSlot(taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.add(models.TimeSlot{TaskName: taskName, Kind: kind, ExternalRef: externalRef, Context: slotContext, StartTime: startTime}), nil
+}
+
+func (s *fakeStore) StopTimeSlot(id int64, endTime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return s.stop(id, endTime)
+}
+
+func (s *fakeStore) SwitchTimeSlot(stopID int64, endTime time.Time, taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	if err := s.stop(stopID, endTime); err != nil {
+		return nil, err
+	}
+	return s.add(models.TimeSlot{TaskName: taskName, Kind: kind, ExternalRef: externalRef, Context: slotContext, StartTime: startTime}), nil
+}
+
+func (s *fakeStore) AutoStopTimeSlot(id int64, endTime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	if err := s.stop(id, endTime); err != nil {
+		return err
+	}
+	s.slots[id].AutoStopped = true
+	return nil
+}
+
+func (s *fakeStore) UpdateTimeSlot(id int64, taskName string, startTime time.Time, endTime *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	slot, err := s.slot(id)
+	if err != nil {
+		return err
+	}
+	slot.TaskName = taskName
+	slot.StartTime = startTime
+	slot.EndTime = endTime
+	slot.CalculateDuration()
+	return nil
+}
+
+func (s *fakeStore) RetimeTimeSlots(edits []SlotRetime, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		slot, err := s.slot(e.ID)
+		if err != nil {
+			return err
+		}
+		slot.StartTime = e.StartTime
+		slot.EndTime = e.EndTime
+		slot.CalculateDuration()
+	}
+	return nil
+}
+
+func (s *fakeStore) RenameTimeSlot(id int64, taskName string) error {
+	return s.set(id, func(slot *models.TimeSlot) { slot.TaskName = taskName })
+}
+
+func (s *fakeStore) SetTimeSlotStart(id int64, startTime time.Time) error {
+	return s.set(id, func(slot *models.TimeSlot) { slot.StartTime = startTime })
+}
+
+func (s *fakeStore) SetTimeSlotPause(id int64, pausedSeconds int64, pausedAt *time.Time) error {
+	return s.set(id, func(slot *models.TimeSlot) {
+		slot.PausedSeconds = pausedSeconds
+		slot.PausedAt = pausedAt
+	})
+}
+
+func (s *fakeStore) SetTimeSlotNotes(id int64, notes string) error {
+	return s.set(id, func(slot *models.TimeSlot) { slot.Notes = notes })
+}
+
+func (s *fakeStore) SetTimeSlotRef(id int64, externalRef string) error {
+	return s.set(id, func(slot *models.TimeSlot) { slot.ExternalRef = externalRef })
+}
+
+func (s *fakeStore) SetTimeSlotEstimate(id int64, estimateSeconds int64) error {
+	return s.set(id, func(slot *models.TimeSlot) { slot.EstimateSeconds = estimateSeconds })
+}
+
+// set applies fn to the stored slot with the given id
+func (s *fakeStore) set(id int64, fn func(slot *models.TimeSlot)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	slot, err := s.slot(id)
+	if err != nil {
+		return err
+	}
+	fn(slot)
+	return nil
+}
+
+func (s *fakeStore) MergeIntoActiveSlot(previousID int64, activeID int64, startTime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	previous, err := s.slot(previousID)
+	if err != nil {
+		return err
+	}
+	active, err := s.slot(activeID)
+	if err != nil {
+		return err
+	}
+	delete(s.slots, previousID)
+	active.StartTime = startTime
+	active.PausedSeconds += previous.PausedSeconds
+	return nil
+}
+
+func (s *fakeStore) ReopenSlot(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	slot, err := s.slot(id)
+	if err != nil || slot.IsActive() {
+		return fmt.Errorf("completed time slot %d %w", id, ErrNotFound)
+	}
+	slot.EndTime = nil
+	slot.DurationSeconds = 0
+	slot.PausedAt = nil
+	slot.AutoStopped = false
+	return nil
+}
+
+func (s *fakeStore) SplitActiveSlot(id int64, start time.Time, end time.Time, taskName string) (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	original, err := s.slot(id)
+	if err != nil || !original.IsActive() {
+		return nil, fmt.Errorf("active time slot %d %w", id, ErrNotFound)
+	}
+	if err := s.stop(id, start); err != nil {
+		return nil, err
+	}
+	s.add(models.TimeSlot{TaskName: taskName, Kind: models.KindWork, StartTime: start, EndTime: &end,
+		DurationSeconds: int64(end.Sub(start).Seconds()), Context: original.Context})
+	continued := s.add(models.TimeSlot{TaskName: original.TaskName, Kind: original.Kind, StartTime: end,
+		ExternalRef: original.ExternalRef, Context: original.Context, EstimateSeconds: original.EstimateSeconds})
+	continued.TaskName = ""
+	return continued, nil
+}
+
+func (s *fakeStore) DeleteTimeSlot(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return err
+	}
+	delete(s.slots, id)
+	return nil
+}
+
+func (s *fakeStore) GetActiveTimeSlot() (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	var latest *models.TimeSlot
+	for _, slot := range s.slots {
+		if slot.IsActive() && (latest == nil || slot.StartTime.After(latest.StartTime)) {
+			latest = slot
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return copySlot(latest), nil
+}
+
+func (s *fakeStore) GetLastCompletedSlot() (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	var last *models.TimeSlot
+	for _, slot := range s.slots {
+		if !slot.IsActive() && (last == nil || slot.EndTime.After(*last.EndTime)) {
+			last = slot
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+	return copySlot(last), nil
+}
+
+func (s *fakeStore) CreateCompletedTimeSlot(taskName string, startTime time.Time, endTime time.Time) (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.add(models.TimeSlot{TaskName: taskName, Kind: models.KindWork, StartTime: startTime, EndTime: &endTime,
+		DurationSeconds: int64(endTime.Sub(startTime).Seconds())}), nil
+}
+
+func (s *fakeStore) CreateCompletedTimeSlots(entries []ImportEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		end := entry.End
+		s.add(models.TimeSlot{TaskName: entry.TaskName, Kind: models.KindWork, StartTime: entry.Start, EndTime: &end,
+			DurationSeconds: int64(entry.End.Sub(entry.Start).Seconds())})
+	}
+	return len(entries), nil
+}
+
+func (s *fakeStore) StopTimeSlotAndGetStatistics(id int64, endTime time.Time, start time.Time, end time.Time) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	if err := s.stop(id, endTime); err != nil {
+		return nil, err
+	}
+	return s.statistics(start, end, ""), nil
+}
+
+func (s *fakeStore) GetTimeSlot(id int64) (*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	slot, ok := s.slots[id]
+	if !ok {
+		return nil, nil
+	}
+	return copySlot(slot), nil
+}
+
+func (s *fakeStore) GetTimeSlotsByDate(date time.Time) ([]*models.TimeSlot, error) {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return s.GetTimeSlotsInRange(dayStart, dayStart.AddDate(0, 0, 1))
+}
+
+func (s *fakeStore) GetTimeSlotsInRange(start time.Time, end time.Time) ([]*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.sorted(func(slot *models.TimeSlot) bool { return startsIn(slot, start, end) }), nil
+}
+
+func (s *fakeStore) GetOverlappingTimeSlots(excludeID int64, start time.Time, end time.Time) ([]*models.TimeSlot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.sorted(func(slot *models.TimeSlot) bool {
+		return slot.ID != excludeID && slot.StartTime.Before(end) && (slot.IsActive() || slot.EndTime.After(start))
+	}), nil
+}
+
+func (s *fakeStore) GetTaskNames() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, slot := range s.slots {
+		if !seen[slot.TaskName] {
+			seen[slot.TaskName] = true
+			names = append(names, slot.TaskName)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *fakeStore) GetRecentTaskNames() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	slots := s.sorted(func(*models.TimeSlot) bool { return true })
+	seen := make(map[string]bool)
+	var names []string
+	for i := len(slots) - 1; i >= 0; i-- {
+		if name := slots[i].TaskName; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (s *fakeStore) RecordPomodoro(pomodoro Pomodoro) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return 0, err
+	}
+	pomodoro.ID = int64(len(s.pomodoros) + 1)
+	s.pomodoros = append(s.pomodoros, pomodoro)
+	return pomodoro.ID, nil
+}
+
+func (s *fakeStore) AddInterruption(slotID int64, at time.Time, note string) (*Interruption, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	interruption := Interruption{ID: int64(len(s.interruptions) + 1), SlotID: slotID, Time: at, Note: note}
+	s.interruptions = append(s.interruptions, interruption)
+	return &interruption, nil
+}
+
+func (s *fakeStore) GetInterruptions(slotID int64) ([]Interruption, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	interruptions := []Interruption{}
+	for _, interruption := range s.interruptions {
+		if interruption.SlotID == slotID {
+			interruptions = append(interruptions, interruption)
+		}
+	}
+	sort.SliceStable(interruptions, func(i, j int) bool { return interruptions[i].Time.Before(interruptions[j].Time) })
+	return interruptions, nil
+}
+
+// startsIn reports whether slot starts in [start, end)
+func startsIn(slot *models.TimeSlot, start time.Time, end time.Time) bool {
+	return !slot.StartTime.Before(start) && slot.StartTime.Before(end)
+}
+
+// sorted returns copies of the stored slots matching keep in chronological order
+// Caller must hold the lock
+func (s *fakeStore) sorted(keep func(slot *models.TimeSlot) bool) []*models.TimeSlot {
+	slots := []*models.TimeSlot{}
+	for _, slot := range s.slots {
+		if keep(slot) {
+			slots = append(slots, copySlot(slot))
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if slots[i].StartTime.Equal(slots[j].StartTime) {
+			return slots[i].ID < slots[j].ID
+		}
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+	return slots
+}
+
+// completed returns copies of the completed slots starting in [start, end) that
+// match keep, like the statistics queries of Database
+// Caller must hold the lock
+func (s *fakeStore) completed(start time.Time, end time.Time, keep func(slot *models.TimeSlot) bool) []*models.TimeSlot {
+	return s.sorted(func(slot *models.TimeSlot) bool {
+		return !slot.IsActive() && startsIn(slot, start, end) && keep(slot)
+	})
+}
+
+// statistics sums the completed slots starting in [start, end) per task name
+// An empty kind includes slots of every kind
+// Caller must hold the lock
+func (s *fakeStore) statistics(start time.Time, end time.Time, kind string) map[string]int64 {
+	stats := make(map[string]int64)
+	for _, slot := range s.completed(start, end, func(slot *models.TimeSlot) bool { return kind == "" || slot.Kind == kind }) {
+		stats[slot.TaskName] += slot.DurationSeconds
+	}
+	return stats
+}
+
+// totals sums the completed slots starting in [start, end) that match keep per
+// key of the slot
+func (s *fakeStore) totals(start time.Time, end time.Time, keep func(slot *models.TimeSlot) bool, key func(slot *models.TimeSlot) string) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	totals := make(map[string]int64)
+	for _, slot := range s.completed(start, end, keep) {
+		totals[key(slot)] += slot.DurationSeconds
+	}
+	return totals, nil
+}
+
+func (s *fakeStore) GetTaskStatistics(date time.Time) (map[string]int64, error) {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return s.GetTaskStatisticsForRange(dayStart, dayStart.AddDate(0, 0, 1))
+}
+
+func (s *fakeStore) GetTaskStatisticsForRange(start time.Time, end time.Time) (map[string]int64, error) {
+	return s.GetTaskStatisticsForKind(start, end, "")
+}
+
+func (s *fakeStore) GetTaskStatisticsForKind(start time.Time, end time.Time, kind string) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return s.statistics(start, end, kind), nil
+}
+
+func (s *fakeStore) GetTaskTotals(start time.Time, end time.Time, kind string) ([]TaskTotal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	byTask := make(map[string]*TaskTotal)
+	totals := []TaskTotal{}
+	for _, slot := range s.completed(start, end, func(slot *models.TimeSlot) bool { return kind == "" || slot.Kind == kind }) {
+		total, ok := byTask[slot.TaskName]
+		if !ok {
+			total = &TaskTotal{TaskName: slot.TaskName}
+			byTask[slot.TaskName] = total
+		}
+		total.TotalSeconds += slot.DurationSeconds
+		total.Sessions++
+	}
+	for _, total := range byTask {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].TotalSeconds > totals[j].TotalSeconds })
+	return totals, nil
+}
+
+func (s *fakeStore) GetDailyTotals(start time.Time, end time.Time, kind string) (map[string]int64, error) {
+	return s.totals(start, end, func(slot *models.TimeSlot) bool { return kind == "" || slot.Kind == kind },
+		func(slot *models.TimeSlot) string { return dayBucket(slot.StartTime.In(start.Location())) })
+}
+
+func (s *fakeStore) GetTaskDailyTotals(taskName string, start time.Time, end time.Time) (map[string]int64, error) {
+	return s.totals(start, end, func(slot *models.TimeSlot) bool { return slot.TaskName == taskName },
+		func(slot *models.TimeSlot) string { return dayBucket(slot.StartTime.In(start.Location())) })
+}
+
+func (s *fakeStore) GetTrendTotals(taskName string, start time.Time, end time.Time, bucketKey func(time.Time) string) (map[string]int64, error) {
+	return s.totals(start, end, func(slot *models.TimeSlot) bool {
+		if taskName == "" {
+			return slot.Kind == models.KindWork
+		}
+		return slot.TaskName == taskName
+	}, func(slot *models.TimeSlot) string { return bucketKey(slot.StartTime.In(start.Location())) })
+}
+
+func (s *fakeStore) GetTimeByRef(externalRef string, start time.Time, end time.Time) (map[string]int64, error) {
+	return s.totals(start, end, func(slot *models.TimeSlot) bool {
+		return slot.ExternalRef != "" && (externalRef == "" || slot.ExternalRef == externalRef)
+	}, func(slot *models.TimeSlot) string { return slot.ExternalRef })
+}
+
+func (s *fakeStore) GetContextTotals(start time.Time, end time.Time) (map[string]int64, error) {
+	return s.totals(start, end, func(slot *models.TimeSlot) bool { return slot.Kind == models.KindWork },
+		func(slot *models.TimeSlot) string { return slot.Context })
+}
+
+func (s *fakeStore) GetEstimateTotals(start time.Time, end time.Time) ([]EstimateAccuracy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	byTask := make(map[string]*EstimateAccuracy)
+	for _, slot := range s.completed(start, end, func(slot *models.TimeSlot) bool { return slot.EstimateSeconds > 0 }) {
+		total, ok := byTask[slot.TaskName]
+		if !ok {
+			total = &EstimateAccuracy{TaskName: slot.TaskName}
+			byTask[slot.TaskName] = total
+		}
+		total.Sessions++
+		total.EstimatedSeconds += slot.EstimateSeconds
+		total.ActualSeconds += slot.DurationSeconds
+	}
+	var totals []EstimateAccuracy
+	for _, total := range byTask {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].TaskName < totals[j].TaskName })
+	return totals, nil
+}
+
+func (s *fakeStore) GetLeftRunningCounts(start time.Time, end time.Time, longDuration time.Duration) ([]LeftRunningTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	longSeconds := int64(longDuration.Seconds())
+	byTask := make(map[string]*LeftRunningTask)
+	var order []string
+	for _, slot := range s.completed(start, end, func(slot *models.TimeSlot) bool { return slot.Kind == models.KindWork }) {
+		task, ok := byTask[slot.TaskName]
+		if !ok {
+			task = &LeftRunningTask{TaskName: slot.TaskName}
+			byTask[slot.TaskName] = task
+			order = append(order, slot.TaskName)
+		}
+		task.Sessions++
+		long := slot.DurationSeconds > longSeconds
+		if slot.AutoStopped {
+			task.AutoStopped++
+		}
+		if long {
+			task.LongSessions++
+		}
+		if slot.AutoStopped || long {
+			task.Flagged++
+		}
+	}
+	tasks := []LeftRunningTask{}
+	for _, name := range order {
+		if task := byTask[name]; task.Flagged > 0 {
+			tasks = append(tasks, *task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *fakeStore) GetTrackedDates(start time.Time, end time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	dates := []string{}
+	for _, slot := range s.sorted(func(slot *models.TimeSlot) bool { return startsIn(slot, start, end) }) {
+		date := dayBucket(slot.StartTime.In(start.Location()))
+		if len(dates) == 0 || dates[len(dates)-1] != date {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+func (s *fakeStore) GetTopTask(start time.Time, end time.Time) (string, int64, error) {
+	totals, err := s.GetTaskTotals(start, end, "")
+	if err != nil || len(totals) == 0 {
+		return "", 0, err
+	}
+	return totals[0].TaskName, totals[0].TotalSeconds, nil
+}
+
+func (s *fakeStore) GetGrandTotal() (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return 0, 0, err
+	}
+	var totalSeconds, slotCount int64
+	for _, slot := range s.slots {
+		if !slot.IsActive() {
+			totalSeconds += slot.DurationSeconds
+			slotCount++
+		}
+	}
+	return totalSeconds, slotCount, nil
+}
+
+func (s *fakeStore) GetFirstStartTime() (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	slots := s.sorted(func(*models.TimeSlot) bool { return true })
+	if len(slots) == 0 {
+		return nil, nil
+	}
+	first := slots[0].StartTime.Local()
+	return &first, nil
+}
+
+func (s *fakeStore) GetPomodoroStats(start time.Time, end time.Time) (*PomodoroStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	stats := &PomodoroStats{}
+	for _, pomodoro := range s.pomodoros {
+		if pomodoro.StartTime.Before(start) || !pomodoro.StartTime.Before(end) {
+			continue
+		}
+		if pomodoro.Completed {
+			stats.Completed++
+		} else {
+			stats.Interrupted++
+		}
+		stats.FocusSeconds += pomodoro.FocusSeconds
+	}
+	return stats, nil
+}
+
+func (s *fakeStore) CountInterruptions(start time.Time, end time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.fail(); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, interruption := range s.interruptions {
+		if _, ok := s.slots[interruption.SlotID]; ok && !interruption.Time.Before(start) && interruption.Time.Before(end) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EnterFocusMode hides the main window so only the tray is left, which shows the
+// elapsed time; with the FocusMuteNotifications setting only critical
+// notifications get through. ExitFocusMode or the tray brings the window back.
+func (a *App) EnterFocusMode() error {
+	return a.setFocusMode(true)
+}
+
+// ExitFocusMode shows the main window again and lifts the notification filter
+func (a *App) ExitFocusMode() error {
+	return a.setFocusMode(false)
+}
+
+// IsFocusMode reports whether focus mode is on
+func (a *App) IsFocusMode() bool {
+	a.focusMu.Lock()
+	defer a.focusMu.Unlock()
+	return a.focusMode
+}
+
+// setFocusMode hides or shows the window and tells the tray and frontend
+func (a *App) setFocusMode(enabled bool) error {
+	// Without the tray there would be no way to bring the window back
+	if enabled && a.settings.Get().DisableSystray {
+		return fmt.Errorf("focus mode needs the tray icon, enable it first")
+	}
+
+	a.focusMu.Lock()
+	changed := a.focusMode != enabled
+	a.focusMode = enabled
+	a.focusMu.Unlock()
+	if !changed || a.ctx == nil {
+		return nil
+	}
+
+	if enabled {
+		runtime.WindowHide(a.ctx)
+	} else {
+		runtime.WindowShow(a.ctx)
+	}
+	a.emit(EventFocusModeChanged, enabled)
+	return nil
+}
+
+// autoFocus enters focus mode after a work slot starts if the FocusOnStart setting is on
+func (a *App) autoFocus() {
+	settings := a.settings.Get()
+	if !settings.FocusOnStart || settings.DisableSystray {
+		return
+	}
+	if err := a.EnterFocusMode(); err != nil {
+		log.Println("Failed to enter focus mode:", err)
+	}
+}
+
+// notificationMuted reports whether focus mode holds back a notification of urgency
+func (a *App) notificationMuted(urgency string) bool {
+	return urgency != UrgencyCritical && a.settings.Get().FocusMuteNotifications && a.IsFocusMode()
+}
+
+// SetFocusOnStart makes starting a work timer enter focus mode
+func (a *App) SetFocusOnStart(enabled bool) error {
+	return a.settings.Update(func(s *Settings) {
+		s.FocusOnStart = enabled
+	})
+}
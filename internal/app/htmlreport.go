@@ -0,0 +1,160 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// HTML report bar chart geometry in pixels
+const (
+	htmlChartWidth     = 600
+	htmlChartLabel     = 180 // width of the task name column left of the bars
+	htmlChartLabelText = 26  // most characters of a task name that fit the label column
+	htmlChartBarHeight = 20
+	htmlChartBarGap    = 6
+)
+
+// htmlReportTemplate renders a self-contained report; html/template escapes
+// task names in the table and the SVG alike
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Time report {{.StartDate}} – {{.EndDate}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; color: #222; max-width: 680px; margin: 2em auto; padding: 0 1em; }
+h1 { font-size: 1.5em; margin-bottom: 0.2em; }
+.range { color: #666; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; margin: 1.5em 0; }
+th, td { padding: 0.4em 0.6em; border-bottom: 1px solid #ddd; text-align: left; }
+th.num, td.num { text-align: right; }
+tfoot td { font-weight: bold; border-bottom: none; }
+.swatch { display: inline-block; width: 0.8em; height: 0.8em; border-radius: 2px; margin-right: 0.4em; }
+.empty { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Time report</h1>
+<p class="range">{{.StartDate}} – {{.EndDate}}</p>
+{{- if .Rows}}
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.ChartWidth}}" height="{{.ChartHeight}}" viewBox="0 0 {{.ChartWidth}} {{.ChartHeight}}" role="img" aria-label="Time per task">
+{{- range .Rows}}
+<text x="0" y="{{.TextY}}" font-size="12" fill="#222">{{.ShortName}}</text>
+<rect x="{{.BarX}}" y="{{.BarY}}" width="{{.BarWidth}}" height="{{.BarHeight}}" rx="2" fill="{{.Color}}"><title>{{.TaskName}}: {{.Total}}</title></rect>
+{{- end}}
+</svg>
+<table>
+<thead><tr><th>Task</th><th class="num">Sessions</th><th class="num">Total</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td><span class="swatch" style="background: {{.Color}}"></span>{{.TaskName}}</td><td class="num">{{.Sessions}}</td><td class="num">{{.Total}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td>Total</td><td class="num">{{.Sessions}}</td><td class="num">{{.Total}}</td></tr></tfoot>
+</table>
+{{- else}}
+<p class="empty">No time tracked in this period.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlReport is the data of htmlReportTemplate
+type htmlReport struct {
+	StartDate   string
+	EndDate     string
+	Rows        []htmlReportRow
+	Sessions    int
+	Total       string
+	ChartWidth  int
+	ChartHeight int
+}
+
+// htmlReportRow is one task of the HTML report with its bar in the chart
+type htmlReportRow struct {
+	TaskName  string
+	ShortName string // TaskName cut to fit the chart label column
+	Sessions  int
+	Total     string
+	Color     string
+	BarX      int
+	BarY      int
+	BarWidth  int
+	BarHeight int
+	TextY     int
+}
+
+// buildHTMLReport renders task totals, most tracked first, as an HTML document
+// with a bar chart and a summary table; tasks without a color get the default one
+func buildHTMLReport(startDate, endDate string, tasks []TaskTotal, colors map[string]string) (string, error) {
+	report := htmlReport{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		ChartWidth: htmlChartWidth,
+	}
+
+	var longest, total int64
+	for _, task := range tasks {
+		if task.TotalSeconds > longest {
+			longest = task.TotalSeconds
+		}
+	}
+	for i, task := range tasks {
+		color, ok := colors[task.TaskName]
+		if !ok {
+			color = defaultTaskColor(task.TaskName)
+		}
+		barWidth := 0
+		if longest > 0 {
+			barWidth = int(float64(htmlChartWidth-htmlChartLabel) * float64(task.TotalSeconds) / float64(longest))
+		}
+		barY := i * (htmlChartBarHeight + htmlChartBarGap)
+
+		report.Rows = append(report.Rows, htmlReportRow{
+			TaskName:  task.TaskName,
+			ShortName: truncateRunes(task.TaskName, htmlChartLabelText),
+			Sessions:  task.Sessions,
+			Total:     formatShortDuration(time.Duration(task.TotalSeconds) * time.Second),
+			Color:     color,
+			BarX:      htmlChartLabel,
+			BarY:      barY,
+			BarWidth:  max(barWidth, 1),
+			BarHeight: htmlChartBarHeight,
+			TextY:     barY + htmlChartBarHeight*3/4,
+		})
+		report.Sessions += task.Sessions
+		total += task.TotalSeconds
+	}
+	report.Total = formatShortDuration(time.Duration(total) * time.Second)
+	report.ChartHeight = len(tasks)*(htmlChartBarHeight+htmlChartBarGap) - htmlChartBarGap
+
+	var b bytes.Buffer
+	if err := htmlReportTemplate.Execute(&b, report); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ExportHTMLReport returns a self-contained HTML report of the work tracked
+// between two dates (inclusive), with a bar chart and a table of sessions and
+// totals per task, e.g. for emailing a client
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) ExportHTMLReport(startStr string, endStr string) (string, error) {
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return "", err
+	}
+	tasks, err := a.database.GetTaskTotals(start, end, models.KindWork)
+	if err != nil {
+		return "", err
+	}
+	colors, err := a.database.GetTaskColors()
+	if err != nil {
+		return "", err
+	}
+	return buildHTMLReport(startStr, endStr, tasks, colors)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// decodeIcon encodes and decodes the default tray icon for state
+func decodeIcon(t *testing.T, state trayState) image.Image {
+	t.Helper()
+
+	img, err := png.Decode(bytes.NewReader(createDefaultIcon(state)))
+	if err != nil {
+		t.Fatalf("decode icon: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != iconSize || size.Y != iconSize {
+		t.Fatalf("icon is %dx%d, want %dx%d", size.X, size.Y, iconSize, iconSize)
+	}
+	return img
+}
+
+// pixel returns the non-premultiplied color of a pixel
+func pixel(img image.Image, x, y int) color.NRGBA {
+	return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+}
+
+// sameColor reports whether a decoded pixel is c
+func sameColor(got color.NRGBA, c color.RGBA) bool {
+	return got == color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+func TestDefaultIconRunning(t *testing.T) {
+	img := decodeIcon(t, trayRunning)
+
+	if got := pixel(img, iconCenter, iconCenter); !sameColor(got, iconActiveColor) {
+		t.Errorf("center = %v, want the active color %v", got, iconActiveColor)
+	}
+	// Just inside the radius is filled, the corners stay transparent
+	inside := iconCenter + int(iconRadius) - 1
+	if got := pixel(img, inside, iconCenter); !sameColor(got, iconActiveColor) {
+		t.Errorf("pixel at radius-1 = %v, want the active color", got)
+	}
+	if got := pixel(img, 0, 0); got.A != 0 {
+		t.Errorf("corner = %v, want transparent", got)
+	}
+}
+
+func TestDefaultIconPaused(t *testing.T) {
+	img := decodeIcon(t, trayPaused)
+
+	if got := pixel(img, iconCenter, iconCenter); !sameColor(got, iconPausedColor) {
+		t.Errorf("center = %v, want the paused color %v", got, iconPausedColor)
+	}
+}
+
+func TestDefaultIconStopped(t *testing.T) {
+	img := decodeIcon(t, trayStopped)
+
+	if got := pixel(img, iconCenter, iconCenter); got.A != 0 {
+		t.Errorf("center = %v, want transparent", got)
+	}
+	// The outline runs through iconRadius on every axis
+	edge := iconCenter + int(iconRadius)
+	for _, p := range []image.Point{
+		{edge, iconCenter},
+		{iconCenter, edge},
+		{iconCenter - int(iconRadius), iconCenter},
+		{iconCenter, iconCenter - int(iconRadius)},
+	} {
+		if got := pixel(img, p.X, p.Y); !sameColor(got, iconInactiveColor) {
+			t.Errorf("outline pixel %v = %v, want the inactive color %v", p, got, iconInactiveColor)
+		}
+	}
+	if got := pixel(img, edge+2, iconCenter); got.A != 0 {
+		t.Errorf("pixel outside the outline = %v, want transparent", got)
+	}
+	if got := pixel(img, edge-3, iconCenter); got.A != 0 {
+		t.Errorf("pixel inside the outline = %v, want transparent", got)
+	}
+}
+
+func TestDefaultIconStatesDiffer(t *testing.T) {
+	running := createDefaultIcon(trayRunning)
+	if bytes.Equal(running, createDefaultIcon(trayStopped)) || bytes.Equal(running, createDefaultIcon(trayPaused)) {
+		t.Error("tray states share an icon")
+	}
+	if !bytes.Equal(running, createDefaultIcon(trayRunning)) {
+		t.Error("the running icon isn't deterministic")
+	}
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// idleCheckInterval is how often the system idle time is polled while the timer runs
+const idleCheckInterval = 15 * time.Second
+
+// minSleepDuration is the shortest suspend handled by the CountSleepTime setting;
+// shorter gaps can be scheduling delays or clock adjustments
+const minSleepDuration = time.Minute
+
+// Actions for an idle period detected while the timer ran
+const (
+	IdleKeep     = "keep"
+	IdleDiscard  = "discard"
+	IdleReassign = "reassign"
+)
+
+// IdlePeriod is a stretch without keyboard or mouse input while the timer ran
+type IdlePeriod struct {
+	SlotID    int64     `json:"slot_id"`
+	TaskName  string    `json:"task_name"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Seconds   int64     `json:"seconds"`
+}
+
+// validateIdleAction checks an action passed to ResolveIdlePeriod
+func validateIdleAction(action string) error {
+	switch action {
+	case IdleKeep, IdleDiscard, IdleReassign:
+		return nil
+	default:
+		return fmt.Errorf("unknown idle action %q", action)
+	}
+}
+
+// IdleDetector watches the system idle time while the timer runs and, once the
+// user is back, reports idle periods longer than the IdleThresholdMinutes setting
+// The idle time stays tracked until the user decides what to do with it
+type IdleDetector struct {
+	app       *App
+	ctx       context.Context
+	idleTime  func() (time.Duration, error)
+	awakeTime func() (time.Duration, error) // clock that stops while the computer is suspended
+
+	mu          sync.Mutex
+	slotID      int64         // active slot the idle state belongs to
+	idleSince   time.Time     // last input before the current idle stretch, zero while active
+	pending     *IdlePeriod   // detected period until it is resolved
+	warned      bool          // idle time unavailability was logged
+	lastPoll    time.Time     // wall clock at the previous poll, to detect suspends
+	lastAwake   time.Duration // awake time at the previous poll
+	sleepWarned bool          // awake time unavailability was logged
+	resumedAt   time.Time     // end of the last suspend; idle periods don't reach back past it
+}
+
+// NewIdleDetector creates an idle detector reading the system idle time
+func NewIdleDetector(app *App) *IdleDetector {
+	return &IdleDetector{
+		app:       app,
+		idleTime:  systemIdleTime,
+		awakeTime: systemAwakeTime,
+	}
+}
+
+// Start begins polling the idle time until ctx is cancelled
+func (d *IdleDetector) Start(ctx context.Context) {
+	d.ctx = ctx
+	d.app.goWorker(d.monitor)
+}
+
+// monitor polls the idle time periodically
+func (d *IdleDetector) monitor() {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.poll(d.app.now())
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// poll reads the idle time when detection applies to the running slot
+// A pending period is dropped once its slot stopped, since there is nothing left to resolve
+func (d *IdleDetector) poll(now time.Time) {
+	threshold := time.Duration(d.app.settings.Get().IdleThresholdMinutes) * time.Minute
+	activeSlot := d.app.GetActiveTimeSlot()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	slept := d.sleptSinceLastPoll(now)
+	if slept >= minSleepDuration {
+		d.resumedAt = now
+		d.idleSince = time.Time{}
+		activeSlot = d.handleSleep(activeSlot, now.Add(-slept), now)
+	}
+
+	if d.pending != nil && (activeSlot == nil || activeSlot.ID != d.pending.SlotID) {
+		d.pending = nil
+	}
+	if threshold <= 0 || activeSlot == nil || activeSlot.IsPaused() {
+		d.idleSince = time.Time{}
+		return
+	}
+	if activeSlot.ID != d.slotID {
+		d.slotID = activeSlot.ID
+		d.idleSince = time.Time{}
+	}
+	if d.pending != nil {
+		return
+	}
+
+	idle, err := d.idleTime()
+	if err != nil {
+		if !d.warned {
+			log.Println("Idle detection is unavailable:", err)
+			d.warned = true
+		}
+		return
+	}
+	d.observe(activeSlot, now, idle, threshold)
+}
+
+// observe tracks an idle stretch and reports it when input resumes
+// Caller must hold the lock
+func (d *IdleDetector) observe(activeSlot *models.TimeSlot, now time.Time, idle time.Duration, threshold time.Duration) {
+	lastInput := now.Add(-idle)
+	if idle >= threshold {
+		if d.idleSince.IsZero() {
+			d.idleSince = lastInput
+			if d.idleSince.Before(activeSlot.StartTime) {
+				d.idleSince = activeSlot.StartTime
+			}
+			// Time asleep was already dealt with by handleSleep
+			if d.idleSince.Before(d.resumedAt) {
+				d.idleSince = d.resumedAt
+			}
+		}
+		return
+	}
+	if d.idleSince.IsZero() {
+		return
+	}
+
+	start := d.idleSince
+	d.idleSince = time.Time{}
+	if !lastInput.After(start) {
+		return
+	}
+
+	d.pending = &IdlePeriod{
+		SlotID:    activeSlot.ID,
+		TaskName:  activeSlot.TaskName,
+		StartTime: start,
+		EndTime:   lastInput,
+		Seconds:   int64(lastInput.Sub(start).Seconds()),
+	}
+	d.app.emit(EventIdleDetected, d.pending)
+	if d.app.notificationManager != nil {
+		d.app.notificationManager.SendNotification(
+			"Welcome back",
+			"You were away for "+formatShortDuration(lastInput.Sub(start))+" while tracking '"+
+				activeSlot.TaskName+"'. Keep, discard or reassign that time in Light Tracking",
+		)
+	}
+}
+
+// sleptSinceLastPoll returns how long the computer was suspended since the
+// previous poll and remembers the readings of this one
+// Caller must hold the lock
+func (d *IdleDetector) sleptSinceLastPoll(now time.Time) time.Duration {
+	awake, err := d.awakeTime()
+	if err != nil {
+		if !d.sleepWarned {
+			log.Println("Sleep detection is unavailable:", err)
+			d.sleepWarned = true
+		}
+		d.lastPoll = time.Time{}
+		return 0
+	}
+
+	slept := sleptBetween(d.lastPoll, now, d.lastAwake, awake)
+	d.lastPoll = now
+	d.lastAwake = awake
+	return slept
+}
+
+// sleptBetween returns how long the computer was suspended between two polls at
+// wall clock times last and now with awake times lastAwake and nowAwake: the wall
+// clock keeps running during a suspend while the awake time stops, so the
+// difference is the time asleep
+func sleptBetween(last, now time.Time, lastAwake, nowAwake time.Duration) time.Duration {
+	if last.IsZero() {
+		return 0
+	}
+	return now.Round(0).Sub(last.Round(0)) - (nowAwake - lastAwake)
+}
+
+// handleSleep applies the CountSleepTime setting to a suspend from start to end
+// while activeSlot ran: the time asleep keeps counting when it's on, otherwise it
+// is excluded like discarded idle time. Returns the running slot afterwards.
+// Caller must hold the lock
+func (d *IdleDetector) handleSleep(activeSlot *models.TimeSlot, start time.Time, end time.Time) *models.TimeSlot {
+	if activeSlot == nil || activeSlot.IsPaused() || d.app.settings.Get().CountSleepTime {
+		return activeSlot
+	}
+	if start.Before(activeSlot.StartTime) {
+		start = activeSlot.StartTime
+	}
+	if !end.After(start) {
+		return activeSlot
+	}
+
+	slot, err := d.app.timer.ExcludeIdle(activeSlot.ID, start, end)
+	if err != nil {
+		log.Println("Failed to exclude time asleep:", err)
+		return activeSlot
+	}
+	log.Printf("Excluded %s asleep from '%s'", formatShortDuration(end.Sub(start)), slot.TaskName)
+	d.app.emit(EventTimerAdjusted, slot)
+	d.app.slotsChanged(slot)
+	return slot
+}
+
+// Pending returns a copy of the detected idle period, nil when there is none
+func (d *IdleDetector) Pending() *IdlePeriod {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending == nil {
+		return nil
+	}
+	period := *d.pending
+	return &period
+}
+
+// Resolve applies action to the pending idle period and returns the running slot
+// taskName is the task reassigned idle time is recorded for
+func (d *IdleDetector) Resolve(action string, taskName string) (*models.TimeSlot, error) {
+	if err := validateIdleAction(action); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	period := d.pending
+	if period == nil {
+		return nil, fmt.Errorf("no idle period to resolve")
+	}
+
+	var slot *models.TimeSlot
+	var err error
+	switch action {
+	case IdleKeep:
+		slot = d.app.timer.GetActiveSlot()
+	case IdleDiscard:
+		slot, err = d.app.timer.ExcludeIdle(period.SlotID, period.StartTime, period.EndTime)
+	case IdleReassign:
+		reassign := func() (*models.TimeSlot, error) {
+			return d.app.timer.ReassignIdle(period.SlotID, period.StartTime, period.EndTime, taskName)
+		}
+		if d.app.pomodoroManager != nil {
+			slot, err = d.app.pomodoroManager.splitActive(period.StartTime, reassign)
+		} else {
+			slot, err = reassign()
+		}
+	}
+	if err != nil {
+		if errors.Is(err, ErrTimerNotRunning) {
+			// The slot was stopped in the meantime
+			d.pending = nil
+		}
+		return nil, err
+	}
+
+	d.pending = nil
+	return slot, nil
+}
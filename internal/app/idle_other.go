@@ -0,0 +1,62 @@
+//go:build !windows
+
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemIdleTime returns how long ago the last keyboard or mouse input was
+// macOS reads the HID idle time from ioreg; Linux needs xprintidle, which only works under X11
+func systemIdleTime() (time.Duration, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("ioreg", "-c", "IOHIDSystem").Output()
+		if err != nil {
+			return 0, fmt.Errorf("failed to run ioreg: %w", err)
+		}
+		return parseIoregIdleTime(string(out))
+	case "linux":
+		out, err := exec.Command("xprintidle").Output()
+		if err != nil {
+			return 0, fmt.Errorf("failed to run xprintidle: %w", err)
+		}
+		ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse xprintidle output: %w", err)
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	default:
+		return 0, fmt.Errorf("idle detection is not supported on %s", runtime.GOOS)
+	}
+}
+
+// processStart anchors systemAwakeTime to the monotonic clock
+var processStart = time.Now()
+
+// systemAwakeTime returns the monotonic time since the process started, which
+// stops while the computer is suspended on Linux and macOS
+func systemAwakeTime() (time.Duration, error) {
+	return time.Since(processStart), nil
+}
+
+// parseIoregIdleTime extracts HIDIdleTime, in nanoseconds, from ioreg output
+func parseIoregIdleTime(out string) (time.Duration, error) {
+	for _, line := range strings.Split(out, "\n") {
+		_, value, found := strings.Cut(line, `"HIDIdleTime" = `)
+		if !found {
+			continue
+		}
+		ns, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse HIDIdleTime: %w", err)
+		}
+		return time.Duration(ns), nil
+	}
+	return 0, fmt.Errorf("HIDIdleTime not found in ioreg output")
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+// sleepThrough runs a 09:00 slot until 12:00 with a suspend from start to end in
+// between, handled with the CountSleepTime setting set to countSleep
+func sleepThrough(t *testing.T, countSleep bool, start time.Time, end time.Time) *App {
+	t.Helper()
+
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a, clock := newClockedTestApp(t, day)
+	if err := a.settings.Update(func(s *Settings) { s.CountSleepTime = countSleep }); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+	if _, err := a.StartTimer("Overnight job"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(3 * time.Hour)
+
+	d := NewIdleDetector(a)
+	d.mu.Lock()
+	d.handleSleep(a.GetActiveTimeSlot(), start, end)
+	d.mu.Unlock()
+	return a
+}
+
+func TestSleepExcludedByDefault(t *testing.T) {
+	if DefaultSettings().CountSleepTime {
+		t.Fatal("sleep time counts by default")
+	}
+
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a := sleepThrough(t, false, day.Add(time.Hour), day.Add(150*time.Minute))
+
+	slot := a.GetActiveTimeSlot()
+	if slot.PausedSeconds != 5400 {
+		t.Errorf("paused = %ds, want the 5400s asleep", slot.PausedSeconds)
+	}
+	if got := a.GetElapsedTime(); got != 5400 {
+		t.Errorf("elapsed = %ds, want 3h minus 90m asleep", got)
+	}
+	stored, err := a.database.GetTimeSlot(slot.ID)
+	if err != nil {
+		t.Fatalf("GetTimeSlot: %v", err)
+	}
+	if stored.PausedSeconds != 5400 {
+		t.Errorf("stored paused = %ds, want 5400s", stored.PausedSeconds)
+	}
+}
+
+func TestSleepCountedWhenEnabled(t *testing.T) {
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a := sleepThrough(t, true, day.Add(time.Hour), day.Add(150*time.Minute))
+
+	if slot := a.GetActiveTimeSlot(); slot.PausedSeconds != 0 {
+		t.Errorf("paused = %ds, want the time asleep kept", slot.PausedSeconds)
+	}
+	if got := a.GetElapsedTime(); got != 3*3600 {
+		t.Errorf("elapsed = %ds, want the full 3h", got)
+	}
+}
+
+func TestSleepBeforeSlotStartIsClipped(t *testing.T) {
+	// Asleep from 08:00, before the 09:00 start, until 10:00
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a := sleepThrough(t, false, day.Add(-time.Hour), day.Add(time.Hour))
+
+	if slot := a.GetActiveTimeSlot(); slot.PausedSeconds != 3600 {
+		t.Errorf("paused = %ds, want only the hour after the start", slot.PausedSeconds)
+	}
+}
+
+func TestPollDetectsSleepFromAwakeTime(t *testing.T) {
+	day := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	a, clock := newClockedTestApp(t, day)
+	if _, err := a.StartTimer("Report"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+
+	// The awake time stops during the suspend while the wall clock keeps going
+	var awake time.Duration
+	d := NewIdleDetector(a)
+	d.awakeTime = func() (time.Duration, error) { return awake, nil }
+
+	clock.Advance(time.Hour)
+	awake += time.Hour
+	d.poll(clock.Now())
+	clock.Advance(idleCheckInterval)
+	awake += idleCheckInterval
+	d.poll(clock.Now())
+	if slot := a.GetActiveTimeSlot(); slot.PausedSeconds != 0 {
+		t.Fatalf("paused = %ds without a suspend", slot.PausedSeconds)
+	}
+
+	clock.Advance(2 * time.Hour)
+	awake += idleCheckInterval
+	d.poll(clock.Now())
+	want := 2*time.Hour - idleCheckInterval
+	if slot := a.GetActiveTimeSlot(); slot.PausedSeconds != int64(want.Seconds()) {
+		t.Errorf("paused = %ds, want the %v asleep", slot.PausedSeconds, want)
+	}
+}
+
+func TestReassignIdleKeepsEstimatePlanAndPomodoro(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	a.pomodoroManager = NewPomodoroManager(a)
+	a.idleDetector = NewIdleDetector(a)
+
+	slot, err := a.StartPomodoro("Design", 25)
+	if err != nil {
+		t.Fatalf("StartPomodoro: %v", err)
+	}
+	if err := a.timer.SetEstimate(slot.ID, time.Hour); err != nil {
+		t.Fatalf("SetEstimate: %v", err)
+	}
+	if err := a.SetPlannedDuration(90); err != nil {
+		t.Fatalf("SetPlannedDuration: %v", err)
+	}
+
+	// Away from 09:10 to 09:15, back at 09:20
+	clock.Advance(20 * time.Minute)
+	a.idleDetector.pending = &IdlePeriod{
+		SlotID:    slot.ID,
+		TaskName:  "Design",
+		StartTime: slot.StartTime.Add(10 * time.Minute),
+		EndTime:   slot.StartTime.Add(15 * time.Minute),
+	}
+	continued, err := a.ResolveIdlePeriod(IdleReassign, "Meeting")
+	if err != nil {
+		t.Fatalf("ResolveIdlePeriod: %v", err)
+	}
+
+	if got := store.get(continued.ID).EstimateSeconds; got != 3600 {
+		t.Errorf("continued estimate = %ds, want the 3600s of the original", got)
+	}
+	// 10 of the 90 planned minutes were tracked before the meeting
+	finish := a.GetPlannedFinishTime()
+	if want := continued.StartTime.Add(80 * time.Minute); finish == nil || !finish.FinishTime.Equal(want) {
+		t.Errorf("planned finish = %+v, want %v", finish, want)
+	}
+
+	// The pomodoro goes on: 10 minutes before the meeting and 15 after it
+	a.pomodoroManager.check(clock.Now())
+	if store.pomodoros != nil {
+		t.Fatalf("pomodoros = %+v after the split, want the cycle still running", store.pomodoros)
+	}
+	clock.Advance(10 * time.Minute)
+	a.pomodoroManager.check(clock.Now())
+	if len(store.pomodoros) != 1 || !store.pomodoros[0].Completed || store.pomodoros[0].FocusSeconds != 25*60 {
+		t.Errorf("pomodoros = %+v, want one completed 25m pomodoro", store.pomodoros)
+	}
+}
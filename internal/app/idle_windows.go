@@ -0,0 +1,47 @@
+package app
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = kernel32.NewProc("GetTickCount")
+
+	procQueryUnbiasedInterruptTime = kernel32.NewProc("QueryUnbiasedInterruptTime")
+)
+
+// lastInputInfo mirrors the Win32 LASTINPUTINFO struct
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// systemIdleTime returns how long ago the last keyboard or mouse input was
+func systemIdleTime() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ret, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return 0, fmt.Errorf("failed to get last input info: %w", err)
+	}
+	tick, _, _ := procGetTickCount.Call()
+
+	// Both counters wrap after 49.7 days; uint32 arithmetic keeps the difference right
+	return time.Duration(uint32(tick)-info.dwTime) * time.Millisecond, nil
+}
+
+// systemAwakeTime returns how long the system has been running, time suspended
+// or hibernated excluded
+// The monotonic clock of Go keeps running during a suspend on Windows, the
+// unbiased interrupt time doesn't
+func systemAwakeTime() (time.Duration, error) {
+	var ticks uint64 // 100ns units
+	if ret, _, err := procQueryUnbiasedInterruptTime.Call(uintptr(unsafe.Pointer(&ticks))); ret == 0 {
+		return 0, fmt.Errorf("failed to query unbiased interrupt time: %w", err)
+	}
+	return time.Duration(ticks) * 100, nil
+}
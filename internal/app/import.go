@@ -0,0 +1,157 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Formats accepted by ImportGeneric
+const (
+	// ImportFormatGeneric is a list of {"task", "start", "end"} objects with RFC3339 times
+	ImportFormatGeneric = "generic"
+	// ImportFormatClockify is a Clockify time entry export: a list of entries, or a
+	// detailed report object holding them under "timeentries"
+	ImportFormatClockify = "clockify"
+)
+
+// ImportEntry is a completed slot read from another tracker's export
+type ImportEntry struct {
+	TaskName string
+	Start    time.Time
+	End      time.Time
+}
+
+// genericEntry is one entry of ImportFormatGeneric
+type genericEntry struct {
+	Task  string `json:"task"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// clockifyEntry is the part of a Clockify time entry we import
+type clockifyEntry struct {
+	Description  string `json:"description"`
+	ProjectName  string `json:"projectName"`
+	TimeInterval struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"timeInterval"`
+}
+
+// ImportGeneric imports completed slots from another tracker's JSON export in
+// format, see ImportFormatGeneric and ImportFormatClockify, and returns how many
+// were imported. Malformed entries are skipped and logged; the rest are stored
+// in one transaction, so either all of them are imported or none.
+func (a *App) ImportGeneric(data string, format string) (int, error) {
+	rawEntries, err := importRawEntries(data, format)
+	if err != nil {
+		return 0, err
+	}
+
+	now := a.now()
+	var entries []ImportEntry
+	for i, raw := range rawEntries {
+		entry, err := a.parseImportEntry(raw, format, now)
+		if err != nil {
+			log.Printf("Skipped import entry %d: %v", i+1, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	imported, err := a.database.CreateCompletedTimeSlots(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	times := make([]time.Time, 0, 2*len(entries))
+	for _, entry := range entries {
+		times = append(times, entry.Start, entry.End)
+	}
+	a.dataChanged(times...)
+	return imported, nil
+}
+
+// importRawEntries splits an export into its entries, each parsed on its own
+// so a malformed entry doesn't fail the whole import
+func importRawEntries(data string, format string) ([]json.RawMessage, error) {
+	switch format {
+	case ImportFormatGeneric, ImportFormatClockify:
+	default:
+		return nil, fmt.Errorf("unknown import format %q, expected %q or %q", format, ImportFormatGeneric, ImportFormatClockify)
+	}
+
+	var entries []json.RawMessage
+	trimmed := strings.TrimSpace(data)
+	if format == ImportFormatClockify && strings.HasPrefix(trimmed, "{") {
+		var report struct {
+			TimeEntries []json.RawMessage `json:"timeentries"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &report); err != nil {
+			return nil, fmt.Errorf("failed to parse import data: %w", err)
+		}
+		return report.TimeEntries, nil
+	}
+	if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse import data: %w", err)
+	}
+	return entries, nil
+}
+
+// parseImportEntry converts one raw entry to an ImportEntry and validates it
+func (a *App) parseImportEntry(raw json.RawMessage, format string, now time.Time) (ImportEntry, error) {
+	var taskName, startStr, endStr string
+	switch format {
+	case ImportFormatClockify:
+		var entry clockifyEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return ImportEntry{}, err
+		}
+		taskName = entry.Description
+		if strings.TrimSpace(taskName) == "" {
+			taskName = entry.ProjectName
+		}
+		startStr, endStr = entry.TimeInterval.Start, entry.TimeInterval.End
+	default:
+		var entry genericEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return ImportEntry{}, err
+		}
+		taskName, startStr, endStr = entry.Task, entry.Start, entry.End
+	}
+
+	taskName = normalizeTaskName(taskName)
+	if taskName == "" {
+		return ImportEntry{}, ErrEmptyTaskName
+	}
+	if err := a.checkTaskNameLength(taskName); err != nil {
+		return ImportEntry{}, err
+	}
+
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil {
+		return ImportEntry{}, fmt.Errorf("invalid start time: %w", err)
+	}
+	// Running entries have no end yet
+	if endStr == "" {
+		return ImportEntry{}, fmt.Errorf("entry has no end time")
+	}
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil {
+		return ImportEntry{}, fmt.Errorf("invalid end time: %w", err)
+	}
+	if !end.After(start) {
+		return ImportEntry{}, fmt.Errorf("end time must be after start time")
+	}
+	if end.After(now) {
+		return ImportEntry{}, fmt.Errorf("end time is in the future")
+	}
+
+	return ImportEntry{TaskName: taskName, Start: start.Local(), End: end.Local()}, nil
+}
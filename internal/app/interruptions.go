@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interruption marks a brief interruption of a slot, e.g. a 30-second question,
+// that doesn't deserve a slot of its own
+type Interruption struct {
+	ID     int64     `json:"id"`
+	SlotID int64     `json:"slot_id"`
+	Time   time.Time `json:"time"`
+	Note   string    `json:"note"`
+}
+
+// AddInterruptionMarker records a brief interruption of the running slot without
+// stopping the timer or starting a new slot; the note is optional
+// Interruptions lower the focus score of the day, see GetFocusScore
+func (a *App) AddInterruptionMarker(note string) error {
+	note = strings.Join(strings.Fields(note), " ")
+
+	slot, err := a.timer.AddInterruption(note)
+	if err != nil {
+		return err
+	}
+
+	a.slotsChanged(slot)
+	return nil
+}
+
+// GetInterruptions returns the interruptions marked during a slot, oldest first
+func (a *App) GetInterruptions(slotID int64) ([]Interruption, error) {
+	if slotID <= 0 {
+		return nil, fmt.Errorf("invalid slot id %d", slotID)
+	}
+	return a.database.GetInterruptions(slotID)
+}
@@ -0,0 +1,262 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migrations upgrade the schema created by initSchema step by step.
+// The number of applied migrations is stored in PRAGMA user_version,
+// so new migrations must only ever be appended to this list.
+var migrations = []func(tx *sql.Tx) error{
+	migrateTimestampsToUTC,
+	migrateSingleActiveSlot,
+	migrateTrackPauses,
+	migrateSlotKind,
+	migrateSlotNotes,
+	migrateExternalRef,
+	migrateTaskProjects,
+	migrateTaskRates,
+	migrateRecurringTasks,
+	migrateSlotContext,
+	migrateSlotEstimate,
+	migrateSlotAutoStopped,
+	migratePomodoros,
+	migrateInterruptions,
+}
+
+// migrate applies all migrations that haven't been applied yet
+func (d *Database) migrate() error {
+	var version int
+	if err := d.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		err := d.withTx(func(tx *sql.Tx) error {
+			if err := migrations[i](tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d failed: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateTimestampsToUTC rewrites timestamps stored in local time as UTC
+func migrateTimestampsToUTC(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, start_time, end_time FROM time_slots`)
+	if err != nil {
+		return err
+	}
+
+	type slotTimes struct {
+		id        int64
+		startTime time.Time
+		endTime   sql.NullTime
+	}
+
+	var slots []slotTimes
+	for rows.Next() {
+		var st slotTimes
+		if err := rows.Scan(&st.id, &st.startTime, &st.endTime); err != nil {
+			rows.Close()
+			return err
+		}
+		slots = append(slots, st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, st := range slots {
+		var endTime *time.Time
+		if st.endTime.Valid {
+			et := st.endTime.Time.UTC()
+			endTime = &et
+		}
+
+		_, err := tx.Exec(`UPDATE time_slots SET start_time = ?, end_time = ? WHERE id = ?`,
+			st.startTime.UTC(), endTime, st.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateSingleActiveSlot lets the database itself hold at most one active slot.
+// Extra active slots left by earlier bugs are closed first: each one ends when
+// the next slot starts, and only the most recently started one stays active.
+// Multi-timer support would have to drop this index or make it conditional.
+func migrateSingleActiveSlot(tx *sql.Tx) error {
+	rows, err := tx.Query(`SELECT id, start_time FROM time_slots
+	                       WHERE end_time IS NULL
+	                       ORDER BY start_time DESC, id DESC`)
+	if err != nil {
+		return err
+	}
+
+	type activeSlot struct {
+		id        int64
+		startTime time.Time
+	}
+
+	var active []activeSlot
+	for rows.Next() {
+		var slot activeSlot
+		if err := rows.Scan(&slot.id, &slot.startTime); err != nil {
+			rows.Close()
+			return err
+		}
+		active = append(active, slot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// active[0] is the most recent slot and stays running
+	for i := 1; i < len(active); i++ {
+		slot := active[i]
+
+		var endTime time.Time
+		err := tx.QueryRow(`SELECT start_time FROM time_slots
+		                    WHERE id != ? AND (start_time > ? OR (start_time = ? AND id > ?))
+		                    ORDER BY start_time ASC, id ASC
+		                    LIMIT 1`,
+			slot.id, slot.startTime.UTC(), slot.startTime.UTC(), slot.id).Scan(&endTime)
+		if err != nil {
+			return fmt.Errorf("failed to find end for active slot %d: %w", slot.id, err)
+		}
+
+		durationSeconds := int64(endTime.Sub(slot.startTime).Seconds())
+		_, err = tx.Exec(`UPDATE time_slots SET end_time = ?, duration_seconds = ? WHERE id = ?`,
+			endTime.UTC(), durationSeconds, slot.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_single_active
+	                  ON time_slots((end_time IS NULL)) WHERE end_time IS NULL`)
+	return err
+}
+
+// migrateTrackPauses adds the paused time of a slot and the start of its current pause
+func migrateTrackPauses(tx *sql.Tx) error {
+	if _, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN paused_seconds INTEGER NOT NULL DEFAULT 0`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN paused_at DATETIME`)
+	return err
+}
+
+// migrateSlotKind adds the slot kind; existing slots are work
+func migrateSlotKind(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN kind TEXT NOT NULL DEFAULT 'work'`)
+	return err
+}
+
+// migrateSlotNotes adds free-form notes to time slots
+func migrateSlotNotes(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN notes TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// migrateExternalRef adds a reference to an external issue or ticket
+func migrateExternalRef(tx *sql.Tx) error {
+	if _, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN external_ref TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_external_ref ON time_slots(external_ref) WHERE external_ref != ''`)
+	return err
+}
+
+// migrateTaskProjects adds the task to project mapping used to group tasks
+func migrateTaskProjects(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS task_projects (
+		task_name TEXT PRIMARY KEY,
+		project TEXT NOT NULL
+	)`)
+	return err
+}
+
+// migrateRecurringTasks adds tasks that happen at a fixed time, e.g. a daily standup
+// weekdays is a bit mask with bit 0 for Sunday; last_run is the last local date
+// ("2006-01-02") an occurrence was handled, so it is handled only once
+func migrateRecurringTasks(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS recurring_tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_name TEXT NOT NULL,
+		start_clock TEXT NOT NULL,
+		end_clock TEXT NOT NULL,
+		weekdays INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		last_run TEXT NOT NULL DEFAULT ''
+	)`)
+	return err
+}
+
+// migrateSlotContext records where a slot was tracked, e.g. "home" or "office"
+func migrateSlotContext(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN context TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// migrateSlotEstimate records how long a slot was expected to take
+func migrateSlotEstimate(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN estimate_seconds INTEGER NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migrateSlotAutoStopped flags slots the app stopped on its own
+func migrateSlotAutoStopped(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE time_slots ADD COLUMN auto_stopped BOOLEAN NOT NULL DEFAULT 0`)
+	return err
+}
+
+// migratePomodoros adds the outcome of every pomodoro for the pomodoro stats
+func migratePomodoros(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pomodoros (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_name TEXT NOT NULL,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		focus_seconds INTEGER NOT NULL,
+		completed BOOLEAN NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_pomodoros_start_time ON pomodoros(start_time)`)
+	return err
+}
+
+// migrateInterruptions adds brief interruptions marked on a slot
+func migrateInterruptions(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS interruptions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		slot_id INTEGER NOT NULL,
+		time DATETIME NOT NULL,
+		note TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX IF NOT EXISTS idx_interruptions_slot_id ON interruptions(slot_id);
+	CREATE INDEX IF NOT EXISTS idx_interruptions_time ON interruptions(time)`)
+	return err
+}
+
+// migrateTaskRates adds hourly rates for the earnings report
+func migrateTaskRates(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS task_rates (
+		task_name TEXT PRIMARY KEY,
+		rate_per_hour REAL NOT NULL,
+		currency TEXT NOT NULL
+	)`)
+	return err
+}
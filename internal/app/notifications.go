@@ -3,16 +3,67 @@ package app
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
+
+	"light-tracking/internal/models"
+)
+
+// Desktop notification backends
+const (
+	backendNotifySend = "notify-send"
+	backendDbusSend   = "dbus-send"
+	backendOsascript  = "osascript"
+	backendPowershell = "powershell"
+	backendInApp      = "in-app"
+)
+
+// Notification urgencies, as understood by notify-send
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
 )
 
+// isValidUrgency reports whether urgency is one of the notification urgencies
+func isValidUrgency(urgency string) bool {
+	switch urgency {
+	case UrgencyLow, UrgencyNormal, UrgencyCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationBackendStatus describes which notification backend is in use
+// When no desktop backend is available, notifications are shown inside the window
+type NotificationBackendStatus struct {
+	Available bool   `json:"available"`
+	Backend   string `json:"backend"`
+	Reason    string `json:"reason,omitempty"`
+}
+
 type NotificationManager struct {
 	app            *App
 	ctx            context.Context
 	lastNotifyTime time.Time
 	notifyInterval time.Duration // Notify every 2 hours
+	backend        NotificationBackendStatus
+
+	// State of the "are you still working?" prompt
+	mu            sync.Mutex
+	confirmSlotID int64     // active slot the confirmation state belongs to
+	lastConfirmed time.Time // slot start or last confirmation
+	promptSentAt  time.Time // zero when no prompt is pending
+
+	// plannedNotified is the finish time of the last plan we notified about
+	plannedNotified time.Time
+	// estimateNotified is the last slot we sent an estimate exceeded notification for
+	estimateNotified int64
 }
 
 // NewNotificationManager creates a new notification manager
@@ -21,13 +72,57 @@ func NewNotificationManager(app *App) *NotificationManager {
 		app:            app,
 		notifyInterval: 2 * time.Hour,
 		lastNotifyTime: time.Time{},
+		backend:        detectNotificationBackend(),
 	}
 }
 
+// detectNotificationBackend finds a desktop notification backend for the current OS
+func detectNotificationBackend() NotificationBackendStatus {
+	var candidates []string
+	switch runtime.GOOS {
+	case "linux":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return NotificationBackendStatus{
+				Backend: backendInApp,
+				Reason:  "no D-Bus session bus found (DBUS_SESSION_BUS_ADDRESS is not set)",
+			}
+		}
+		candidates = []string{backendNotifySend, backendDbusSend}
+	case "darwin":
+		candidates = []string{backendOsascript}
+	case "windows":
+		candidates = []string{backendPowershell}
+	default:
+		return NotificationBackendStatus{
+			Backend: backendInApp,
+			Reason:  "desktop notifications are not supported on " + runtime.GOOS,
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate); err == nil {
+			return NotificationBackendStatus{Available: true, Backend: candidate}
+		}
+	}
+
+	return NotificationBackendStatus{
+		Backend: backendInApp,
+		Reason:  fmt.Sprintf("none of %v found in PATH", candidates),
+	}
+}
+
+// BackendStatus returns the notification backend detected at startup
+func (n *NotificationManager) BackendStatus() NotificationBackendStatus {
+	return n.backend
+}
+
 // Start starts monitoring for long sessions and sends notifications
 func (n *NotificationManager) Start(ctx context.Context) {
 	n.ctx = ctx
-	go n.monitorLongSessions()
+	n.app.goWorker(n.monitorLongSessions)
+	n.app.goWorker(n.monitorStillWorking)
+	n.app.goWorker(n.monitorPlannedDuration)
+	n.app.goWorker(n.monitorEstimate)
 }
 
 // monitorLongSessions checks if timer is running for a long time and sends notifications
@@ -38,6 +133,10 @@ func (n *NotificationManager) monitorLongSessions() {
 	for {
 		select {
 		case <-ticker.C:
+			// Don't nag outside working hours
+			if !n.app.settings.Get().Schedule.IsWithinWorkHours(n.app.now()) {
+				continue
+			}
 			if n.app.IsTimerRunning() {
 				elapsed := n.app.GetElapsedTime()
 				elapsedDuration := time.Duration(elapsed) * time.Second
@@ -45,15 +144,15 @@ func (n *NotificationManager) monitorLongSessions() {
 				// Send notification if session is longer than notifyInterval
 				// and we haven't notified recently
 				if elapsedDuration >= n.notifyInterval {
-					timeSinceLastNotify := time.Since(n.lastNotifyTime)
+					timeSinceLastNotify := n.app.now().Sub(n.lastNotifyTime)
 					if timeSinceLastNotify >= n.notifyInterval {
-						activeSlot := n.app.GetActiveTimeSlot()
-						if activeSlot != nil {
-							n.SendNotification(
+						if taskName := n.app.GetActiveTaskName(); taskName != "" {
+							n.SendNotificationWithUrgency(
 								"Long Session Alert",
-								"You've been working on '"+activeSlot.TaskName+"' for "+formatDuration(elapsedDuration),
+								"You've been working on '"+taskName+"' for "+formatDuration(elapsedDuration),
+								n.app.settings.Get().NotificationUrgency,
 							)
-							n.lastNotifyTime = time.Now()
+							n.lastNotifyTime = n.app.now()
 						}
 					}
 				}
@@ -64,30 +163,231 @@ func (n *NotificationManager) monitorLongSessions() {
 	}
 }
 
-// SendNotification sends a desktop notification
+// monitorStillWorking periodically asks whether the user is still on the running task
+func (n *NotificationManager) monitorStillWorking() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.checkStillWorking(n.app.now())
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+// monitorPlannedDuration notifies once when the running slot reaches its planned duration
+func (n *NotificationManager) monitorPlannedDuration() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// The finish time moves while paused, so wait for the timer to resume
+			finish := n.app.GetPlannedFinishTime()
+			if finish == nil || finish.RemainingSeconds > 0 || n.app.IsTimerPaused() {
+				continue
+			}
+			// Changing the plan or starting another slot moves the finish time
+			if finish.FinishTime.Equal(n.plannedNotified) {
+				continue
+			}
+			n.plannedNotified = finish.FinishTime
+
+			if taskName := n.app.GetActiveTaskName(); taskName != "" {
+				n.SendNotification(
+					"Planned Time Reached",
+					"The time planned for '"+taskName+"' is up",
+				)
+			}
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+// monitorEstimate notifies once when the running slot takes longer than its estimate
+func (n *NotificationManager) monitorEstimate() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			activeSlot := n.app.GetActiveTimeSlot()
+			if activeSlot == nil || activeSlot.EstimateSeconds == 0 || activeSlot.ID == n.estimateNotified {
+				continue
+			}
+			if n.app.GetElapsedTime() <= activeSlot.EstimateSeconds {
+				continue
+			}
+			n.estimateNotified = activeSlot.ID
+
+			estimate := time.Duration(activeSlot.EstimateSeconds) * time.Second
+			n.SendNotification(
+				"Estimate Exceeded",
+				fmt.Sprintf("'%s' has taken longer than the estimated %s", activeSlot.TaskName, formatDuration(estimate)),
+			)
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkStillWorking sends a confirmation prompt when the interval has passed
+// and stops the timer if a pending prompt wasn't confirmed within the grace period.
+// Starting or stopping the timer changes the active slot, which resets the interval.
+func (n *NotificationManager) checkStillWorking(now time.Time) {
+	settings := n.app.settings.Get()
+	activeSlot := n.app.GetActiveTimeSlot()
+
+	// Notifying and stopping happen without the lock, so a confirmation
+	// arriving meanwhile doesn't wait for them
+	prompt, stopAt := n.stillWorkingStep(now, settings, activeSlot)
+	if !stopAt.IsZero() {
+		n.autoStop(stopAt)
+	}
+	if !prompt {
+		return
+	}
+
+	n.app.emit(EventStillWorkingPrompt, activeSlot)
+	// A forgotten timer is worth interrupting for
+	n.SendNotificationWithUrgency(
+		"Still working?",
+		"Are you still working on '"+activeSlot.TaskName+"'? Confirm in Light Tracking",
+		UrgencyCritical,
+	)
+}
+
+// stillWorkingStep updates the prompt state for now and reports whether to send
+// a prompt, or when the timer should be auto-stopped; stopAt is zero otherwise
+func (n *NotificationManager) stillWorkingStep(now time.Time, settings Settings, activeSlot *models.TimeSlot) (prompt bool, stopAt time.Time) {
+	interval := time.Duration(settings.StillWorkingIntervalMinutes) * time.Minute
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if interval <= 0 || activeSlot == nil {
+		n.confirmSlotID = 0
+		n.promptSentAt = time.Time{}
+		return false, time.Time{}
+	}
+
+	if activeSlot.ID != n.confirmSlotID {
+		// The interval counts from the slot start, also for a slot that was
+		// already running when the app started
+		n.confirmSlotID = activeSlot.ID
+		n.lastConfirmed = activeSlot.StartTime
+		n.promptSentAt = time.Time{}
+	}
+
+	if !n.promptSentAt.IsZero() {
+		grace := time.Duration(settings.StillWorkingGraceMinutes) * time.Minute
+		if settings.AutoStopUnconfirmed && now.Sub(n.promptSentAt) >= grace {
+			stopAt = n.promptSentAt
+			n.confirmSlotID = 0
+			n.promptSentAt = time.Time{}
+		}
+		return false, stopAt
+	}
+
+	if now.Sub(n.lastConfirmed) < interval || !settings.Schedule.IsWithinWorkHours(now) {
+		return false, time.Time{}
+	}
+
+	n.promptSentAt = now
+	return true, time.Time{}
+}
+
+// autoStop stops the timer at the moment the unanswered prompt was sent
+func (n *NotificationManager) autoStop(endTime time.Time) {
+	stoppedSlot, err := n.app.timer.StopWith(endTime, n.app.database.AutoStopTimeSlot)
+	if err != nil {
+		log.Printf("failed to auto-stop timer: %v", err)
+		return
+	}
+	if stoppedSlot == nil {
+		return
+	}
+	stoppedSlot.AutoStopped = true
+
+	n.app.emit(EventTimerAutoStopped, stoppedSlot)
+	n.app.slotsChanged(stoppedSlot)
+	n.SendNotification(
+		"Timer stopped",
+		"'"+stoppedSlot.TaskName+"' was stopped because the prompt wasn't confirmed",
+	)
+}
+
+// ConfirmStillWorking clears a pending prompt and restarts the interval
+func (n *NotificationManager) ConfirmStillWorking() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.lastConfirmed = n.app.now()
+	n.promptSentAt = time.Time{}
+}
+
+// SendNotification sends a desktop notification with normal urgency
 func (n *NotificationManager) SendNotification(title, message string) error {
+	return n.SendNotificationWithUrgency(title, message, UrgencyNormal)
+}
+
+// SendNotificationWithUrgency sends a desktop notification
+// Urgency is only honored by notify-send; other backends show every notification alike.
+// Falls back to an in-app notification event when no desktop backend works
+func (n *NotificationManager) SendNotificationWithUrgency(title, message, urgency string) error {
+	if !isValidUrgency(urgency) {
+		urgency = UrgencyNormal
+	}
+	if n.app.notificationMuted(urgency) {
+		return nil
+	}
+	if !n.backend.Available {
+		n.sendInAppNotification(title, message)
+		return nil
+	}
+
+	var err error
 	switch runtime.GOOS {
 	case "linux":
-		return n.sendLinuxNotification(title, message)
+		err = n.sendLinuxNotification(title, message, urgency)
 	case "darwin":
-		return n.sendMacOSNotification(title, message)
+		err = n.sendMacOSNotification(title, message)
 	case "windows":
-		return n.sendWindowsNotification(title, message)
-	default:
-		return nil
+		err = n.sendWindowsNotification(title, message)
+	}
+
+	if err != nil {
+		n.sendInAppNotification(title, message)
 	}
+	return err
+}
+
+// sendInAppNotification asks the frontend to show the notification inside the window
+func (n *NotificationManager) sendInAppNotification(title, message string) {
+	n.app.emit(EventNotification, map[string]string{
+		"title":   title,
+		"message": message,
+	})
 }
 
 // sendLinuxNotification sends a notification on Linux using notify-send or dbus
-func (n *NotificationManager) sendLinuxNotification(title, message string) error {
+func (n *NotificationManager) sendLinuxNotification(title, message, urgency string) error {
 	// Try notify-send first (most common)
-	cmd := exec.Command("notify-send", title, message, "--app-name=Light Tracking")
-	if err := cmd.Run(); err == nil {
-		return nil
+	if n.backend.Backend == backendNotifySend {
+		cmd := exec.Command("notify-send", title, message, "--app-name=Light Tracking", "--urgency="+urgency)
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
 	}
 
-	// Fallback to dbus-send
-	cmd = exec.Command("dbus-send", "--type=method_call",
+	// Fallback to dbus-send, which can't pass the urgency hint (a variant inside a dict)
+	cmd := exec.Command("dbus-send", "--type=method_call",
 		"--dest=org.freedesktop.Notifications",
 		"/org/freedesktop/Notifications",
 		"org.freedesktop.Notifications.Notify",
@@ -138,6 +438,22 @@ func formatDuration(d time.Duration) string {
 	return formatPlural(minutes, "minute")
 }
 
+// formatShortDuration formats a duration as "2h 30m", rounded to the minute,
+// the notation issue trackers accept for time spent
+func formatShortDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	hours := minutes / 60
+	minutes %= 60
+
+	if hours > 0 {
+		if minutes > 0 {
+			return fmt.Sprintf("%dh %dm", hours, minutes)
+		}
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
 func formatPlural(n int, unit string) string {
 	if n == 1 {
 		return "1 " + unit
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStillWorkingPromptAndAutoStop(t *testing.T) {
+	a, clock, store := newFakeTestApp(t, testDay)
+	if err := a.settings.Update(func(s *Settings) {
+		s.StillWorkingIntervalMinutes = 60
+		s.StillWorkingGraceMinutes = 10
+		s.AutoStopUnconfirmed = true
+	}); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+	slot, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+
+	// The manager first sees the slot 90 minutes in, e.g. after an app restart;
+	// the interval counts from the slot start, so the prompt is due right away
+	n := NewNotificationManager(a)
+	n.backend = NotificationBackendStatus{Backend: backendInApp}
+	clock.Advance(90 * time.Minute)
+	n.checkStillWorking(clock.Now())
+	if !n.promptSentAt.Equal(clock.Now()) {
+		t.Fatalf("prompt sent at %v, want %v", n.promptSentAt, clock.Now())
+	}
+
+	// A confirmation restarts the interval
+	n.ConfirmStillWorking()
+	clock.Advance(30 * time.Minute)
+	n.checkStillWorking(clock.Now())
+	if !n.promptSentAt.IsZero() {
+		t.Fatalf("prompt sent %v after a confirmation 30 minutes ago", n.promptSentAt)
+	}
+
+	clock.Advance(30 * time.Minute)
+	n.checkStillWorking(clock.Now())
+	promptAt := n.promptSentAt
+	if promptAt.IsZero() {
+		t.Fatal("no prompt an interval after the confirmation")
+	}
+
+	// Unconfirmed past the grace period stops the slot when the prompt was sent
+	clock.Advance(10 * time.Minute)
+	n.checkStillWorking(clock.Now())
+	if a.GetTimerState().Running {
+		t.Fatal("timer still runs after the grace period")
+	}
+	stored := store.get(slot.ID)
+	if !stored.AutoStopped || !stored.EndTime.Equal(promptAt) {
+		t.Errorf("stored slot ends %v (auto-stopped %v), want an auto-stop at %v", stored.EndTime, stored.AutoStopped, promptAt)
+	}
+}
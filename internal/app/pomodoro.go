@@ -0,0 +1,252 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+const (
+	// defaultPomodoroMinutes is the classic pomodoro length
+	defaultPomodoroMinutes = 25
+	// maxPomodoroMinutes bounds the length of a single pomodoro
+	maxPomodoroMinutes = 120
+	// pomodoroCheckInterval is how often the running pomodoro is checked
+	pomodoroCheckInterval = 5 * time.Second
+)
+
+// Pomodoro is a finished pomodoro cycle
+type Pomodoro struct {
+	ID        int64     `json:"id"`
+	TaskName  string    `json:"task_name"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// FocusSeconds is the time tracked during the cycle, pauses excluded
+	FocusSeconds int64 `json:"focus_seconds"`
+	// Completed is false when the timer was stopped or switched to another slot
+	// before the pomodoro was over
+	Completed bool `json:"completed"`
+}
+
+// PomodoroStats summarizes the pomodoros finished between two dates
+type PomodoroStats struct {
+	StartDate    string `json:"start_date"`
+	EndDate      string `json:"end_date"`
+	Completed    int    `json:"completed"`
+	Interrupted  int    `json:"interrupted"`
+	FocusSeconds int64  `json:"focus_seconds"`
+}
+
+// pomodoroCycle is the pomodoro currently running on the active slot
+type pomodoroCycle struct {
+	slotID   int64
+	taskName string
+	start    time.Time
+	length   time.Duration
+	// focus is the slot's elapsed time at the last check, so an interrupted
+	// cycle still knows how long it ran after the slot is gone
+	focus      time.Duration
+	focusStart time.Duration // elapsed time of the slot when the cycle began
+}
+
+// PomodoroManager runs pomodoro cycles on top of the timer and records whether
+// each one was completed or interrupted
+type PomodoroManager struct {
+	app *App
+	ctx context.Context
+
+	mu    sync.Mutex
+	cycle *pomodoroCycle // nil when no pomodoro is running
+}
+
+// NewPomodoroManager creates a pomodoro manager for app
+func NewPomodoroManager(app *App) *PomodoroManager {
+	return &PomodoroManager{app: app}
+}
+
+// Start checks the running pomodoro until ctx is cancelled
+func (p *PomodoroManager) Start(ctx context.Context) {
+	p.ctx = ctx
+	p.app.goWorker(p.monitor)
+}
+
+// monitor periodically finishes the running pomodoro
+func (p *PomodoroManager) monitor() {
+	ticker := time.NewTicker(pomodoroCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.check(p.app.now())
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// begin starts a pomodoro of length on slot; a pomodoro still running on
+// another slot counts as interrupted
+func (p *PomodoroManager) begin(slot *models.TimeSlot, length time.Duration, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cycle != nil {
+		p.finish(false, now)
+	}
+	elapsed := p.app.timer.GetElapsedTime()
+	p.cycle = &pomodoroCycle{
+		slotID:     slot.ID,
+		taskName:   slot.TaskName,
+		start:      now,
+		length:     length,
+		focus:      elapsed,
+		focusStart: elapsed,
+	}
+}
+
+// splitActive runs split, which replaces the active slot by one continuing it
+// from the end of a period reassigned to another task, and moves a pomodoro
+// running on the active slot to the new slot; the focus tracked until start is
+// kept, the reassigned period doesn't count
+// The lock is held throughout, so check can't take the new slot for an interruption
+func (p *PomodoroManager) splitActive(start time.Time, split func() (*models.TimeSlot, error)) (*models.TimeSlot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	original := p.app.timer.GetActiveSlot()
+	continued, err := split()
+	if err != nil || p.cycle == nil || original == nil || original.ID != p.cycle.slotID {
+		return continued, err
+	}
+
+	tracked := start.Sub(original.StartTime) - original.PausedUntil(start)
+	focus := max(tracked-p.cycle.focusStart, 0)
+	elapsed := p.app.timer.GetElapsedTime()
+	p.cycle.slotID = continued.ID
+	// focus - focusStart stays the focus of the cycle on the new slot
+	p.cycle.focus = elapsed
+	p.cycle.focusStart = -focus
+	return continued, nil
+}
+
+// check completes the running pomodoro once its length is tracked, or records
+// it as interrupted when its slot is no longer the active one
+func (p *PomodoroManager) check(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cycle == nil {
+		return
+	}
+	activeSlot := p.app.timer.GetActiveSlot()
+	if activeSlot == nil || activeSlot.ID != p.cycle.slotID {
+		p.finish(false, now)
+		return
+	}
+
+	p.cycle.focus = p.app.timer.GetElapsedTime()
+	if p.cycle.focus-p.cycle.focusStart < p.cycle.length {
+		return
+	}
+	taskName := p.cycle.taskName
+	p.finish(true, now)
+	if p.app.notificationManager != nil {
+		p.app.notificationManager.SendNotification(
+			"Pomodoro complete",
+			fmt.Sprintf("Pomodoro on '%s' is done, time for a break", taskName),
+		)
+	}
+}
+
+// finish records the running pomodoro and clears it; p.mu must be held
+func (p *PomodoroManager) finish(completed bool, now time.Time) {
+	cycle := p.cycle
+	p.cycle = nil
+
+	focus := cycle.focus - cycle.focusStart
+	if completed || focus > cycle.length {
+		focus = cycle.length
+	}
+	pomodoro := Pomodoro{
+		TaskName:     cycle.taskName,
+		StartTime:    cycle.start,
+		EndTime:      now,
+		FocusSeconds: int64(focus.Seconds()),
+		Completed:    completed,
+	}
+	id, err := p.app.database.RecordPomodoro(pomodoro)
+	if err != nil {
+		log.Println("Failed to record pomodoro:", err)
+		return
+	}
+	pomodoro.ID = id
+	p.app.emit(EventPomodoroFinished, pomodoro)
+}
+
+// StartPomodoro starts the timer on a task for a pomodoro of minutes (0 for the
+// default 25). The pomodoro is completed once that much time is tracked on the
+// slot and interrupted when the timer is stopped or switched to another task first
+func (a *App) StartPomodoro(taskName string, minutes int) (*models.TimeSlot, error) {
+	if minutes == 0 {
+		minutes = defaultPomodoroMinutes
+	}
+	if minutes < 0 || minutes > maxPomodoroMinutes {
+		return nil, fmt.Errorf("pomodoro must be between 1 and %d minutes", maxPomodoroMinutes)
+	}
+	if a.pomodoroManager == nil {
+		return nil, fmt.Errorf("pomodoro timer is not running yet")
+	}
+
+	slot, started, err := a.startWork(taskName, "")
+	if err != nil || !started {
+		return slot, err
+	}
+	a.pomodoroManager.begin(slot, time.Duration(minutes)*time.Minute, a.now())
+	return slot, nil
+}
+
+// GetPomodoroStats returns the completed and interrupted pomodoros of a day and
+// the time tracked during them
+// Date should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the focus time, see newReportRounding
+func (a *App) GetPomodoroStats(dateStr string, roundToMinutes int, mode string) (*PomodoroStats, error) {
+	return a.pomodoroStats(dateStr, dateStr, roundToMinutes, mode)
+}
+
+// GetWeeklyPomodoroStats is GetPomodoroStats for the Monday-to-Sunday week containing a date
+// Date should be in format "2006-01-02" (YYYY-MM-DD)
+func (a *App) GetWeeklyPomodoroStats(dateStr string, roundToMinutes int, mode string) (*PomodoroStats, error) {
+	date, err := parseDate(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	// time.Weekday starts on Sunday; shift so Monday is 0
+	monday := date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	return a.pomodoroStats(monday.Format("2006-01-02"), monday.AddDate(0, 0, 6).Format("2006-01-02"), roundToMinutes, mode)
+}
+
+// pomodoroStats sums the pomodoros started between two dates (inclusive)
+func (a *App) pomodoroStats(startStr string, endStr string, roundToMinutes int, mode string) (*PomodoroStats, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := a.database.GetPomodoroStats(start, end)
+	if err != nil {
+		return nil, err
+	}
+	stats.StartDate = startStr
+	stats.EndDate = endStr
+	stats.FocusSeconds = rounding.seconds(stats.FocusSeconds)
+	return stats, nil
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPomodoroStatsRounding(t *testing.T) {
+	a, _, store := newFakeTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	for _, pomodoro := range []Pomodoro{
+		{TaskName: "Design", StartTime: start, EndTime: start.Add(25 * time.Minute), FocusSeconds: 25 * 60, Completed: true},
+		{TaskName: "Design", StartTime: start.Add(time.Hour), EndTime: start.Add(70 * time.Minute), FocusSeconds: 9 * 60},
+		{TaskName: "Review", StartTime: start.AddDate(0, 0, 2), EndTime: start.AddDate(0, 0, 2).Add(25 * time.Minute), FocusSeconds: 25 * 60, Completed: true},
+	} {
+		if _, err := store.RecordPomodoro(pomodoro); err != nil {
+			t.Fatalf("RecordPomodoro: %v", err)
+		}
+	}
+
+	// 34 focus minutes round up to 45
+	daily, err := a.GetPomodoroStats("2026-03-10", 15, RoundUp)
+	if err != nil {
+		t.Fatalf("GetPomodoroStats: %v", err)
+	}
+	if daily.Completed != 1 || daily.Interrupted != 1 || daily.FocusSeconds != 45*60 {
+		t.Errorf("daily stats = %+v, want 1 completed, 1 interrupted and 45m of focus", daily)
+	}
+
+	weekly, err := a.GetWeeklyPomodoroStats("2026-03-10", 0, "")
+	if err != nil {
+		t.Fatalf("GetWeeklyPomodoroStats: %v", err)
+	}
+	if weekly.StartDate != "2026-03-09" || weekly.Completed != 2 || weekly.FocusSeconds != 59*60 {
+		t.Errorf("weekly stats = %+v, want 2 completed and the exact 59m from Monday 2026-03-09", weekly)
+	}
+
+	if _, err := a.GetPomodoroStats("2026-03-10", -5, ""); err == nil {
+		t.Error("GetPomodoroStats accepted a negative rounding")
+	}
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// recoveryStateInterval is how often the running state is written to the recovery file
+const recoveryStateInterval = 30 * time.Second
+
+// Actions for a running timer recovered at startup
+const (
+	RecoveryKeep    = "keep"
+	RecoveryStop    = "stop"
+	RecoveryDiscard = "discard"
+)
+
+// RecoveredSlot describes a running timer found in the database at startup
+type RecoveredSlot struct {
+	Slot           *models.TimeSlot `json:"slot"`
+	ElapsedSeconds int64            `json:"elapsed_seconds"`
+}
+
+// validateRecoveryAction checks an action passed to ResolveRecoveredSlot
+func validateRecoveryAction(action string) error {
+	switch action {
+	case RecoveryKeep, RecoveryStop, RecoveryDiscard:
+		return nil
+	default:
+		return fmt.Errorf("unknown recovery action %q", action)
+	}
+}
+
+// RecoveryState is the in-flight timer state written to a JSON file next to the
+// database, so a hard crash leaves a record to check the database against
+type RecoveryState struct {
+	Running       bool       `json:"running"`
+	SlotID        int64      `json:"slot_id,omitempty"`
+	StartTime     *time.Time `json:"start_time,omitempty"`
+	PausedSeconds int64      `json:"paused_seconds,omitempty"`
+	PausedAt      *time.Time `json:"paused_at,omitempty"`
+	SavedAt       time.Time  `json:"saved_at"`
+}
+
+// newRecoveryState captures the state of slot, nil when the timer is stopped
+func newRecoveryState(slot *models.TimeSlot, now time.Time) RecoveryState {
+	state := RecoveryState{SavedAt: now}
+	if slot == nil || !slot.IsActive() {
+		return state
+	}
+
+	start := slot.StartTime
+	state.Running = true
+	state.SlotID = slot.ID
+	state.StartTime = &start
+	state.PausedSeconds = slot.PausedSeconds
+	if slot.PausedAt != nil {
+		pausedAt := *slot.PausedAt
+		state.PausedAt = &pausedAt
+	}
+	return state
+}
+
+// recoveryDiscrepancy describes how the recovery file disagrees with the
+// database's active slot, or returns "" when they agree
+func recoveryDiscrepancy(state RecoveryState, slot *models.TimeSlot) string {
+	switch {
+	case state.Running && slot == nil:
+		return fmt.Sprintf("recovery file shows slot %d running but the database has no active slot", state.SlotID)
+	case !state.Running && slot != nil:
+		return fmt.Sprintf("database has active slot %d but the recovery file shows the timer stopped", slot.ID)
+	case !state.Running:
+		return ""
+	case state.SlotID != slot.ID:
+		return fmt.Sprintf("recovery file shows slot %d running but the database has slot %d active", state.SlotID, slot.ID)
+	case !state.StartTime.Equal(slot.StartTime):
+		return fmt.Sprintf("slot %d starts at %s in the recovery file but at %s in the database",
+			slot.ID, state.StartTime.Format(time.RFC3339), slot.StartTime.Format(time.RFC3339))
+	case state.PausedSeconds != slot.PausedSeconds || (state.PausedAt == nil) != (slot.PausedAt == nil):
+		return fmt.Sprintf("slot %d has a different pause state in the recovery file", slot.ID)
+	}
+	return ""
+}
+
+// recoveryStatePath returns the path of the recovery file in the app data directory
+func recoveryStatePath() (string, error) {
+	appDataDir, err := getAppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appDataDir, "recovery.json"), nil
+}
+
+// recoverState reconciles the recovery file left by the previous session with
+// the active slot loaded from the database. The database wins; a disagreement
+// is only logged. The file is then rewritten with the current state.
+func (a *App) recoverState() {
+	if a.statePath == "" || a.database.IsLocked() {
+		return
+	}
+
+	data, err := os.ReadFile(a.statePath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		log.Println("Failed to read recovery file:", err)
+	default:
+		var state RecoveryState
+		if err := json.Unmarshal(data, &state); err != nil {
+			log.Println("Failed to parse recovery file:", err)
+		} else if msg := recoveryDiscrepancy(state, a.timer.GetActiveSlot()); msg != "" {
+			log.Printf("Recovery file from %s disagrees with the database, keeping the database state: %s",
+				state.SavedAt.Format(time.RFC3339), msg)
+		}
+	}
+
+	a.saveState()
+}
+
+// saveState writes the current timer state to the recovery file
+// The file is replaced atomically so a crash mid-write leaves the previous record.
+// A locked database has no timer state yet, so the file is left alone.
+func (a *App) saveState() {
+	if a.statePath == "" || a.database.IsLocked() {
+		return
+	}
+
+	data, err := json.MarshalIndent(newRecoveryState(a.timer.GetActiveSlot(), a.now()), "", "  ")
+	if err != nil {
+		log.Println("Failed to encode recovery state:", err)
+		return
+	}
+
+	tmpPath := a.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		log.Println("Failed to write recovery file:", err)
+		return
+	}
+	if err := os.Rename(tmpPath, a.statePath); err != nil {
+		log.Println("Failed to write recovery file:", err)
+	}
+}
+
+// persistState saves the recovery file periodically until ctx is cancelled
+func (a *App) persistState(ctx context.Context) {
+	ticker := time.NewTicker(recoveryStateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.saveState()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+func TestRecoveryDiscrepancy(t *testing.T) {
+	start := testDay
+	pausedAt := start.Add(10 * time.Minute)
+	slot := &models.TimeSlot{ID: 7, TaskName: "Design", StartTime: start}
+	paused := &models.TimeSlot{ID: 7, TaskName: "Design", StartTime: start, PausedAt: &pausedAt}
+
+	tests := []struct {
+		name  string
+		state RecoveryState
+		slot  *models.TimeSlot
+		want  string // substring of the discrepancy, "" when they agree
+	}{
+		{"both stopped", newRecoveryState(nil, start), nil, ""},
+		{"same running slot", newRecoveryState(slot, start), slot, ""},
+		{"same paused slot", newRecoveryState(paused, start), paused, ""},
+		{"file running, database stopped", newRecoveryState(slot, start), nil, "no active slot"},
+		{"file stopped, database running", newRecoveryState(nil, start), slot, "timer stopped"},
+		{"different slot", newRecoveryState(&models.TimeSlot{ID: 6, StartTime: start}, start), slot, "slot 7 active"},
+		{"different start", newRecoveryState(&models.TimeSlot{ID: 7, StartTime: start.Add(time.Minute)}, start), slot, "starts at"},
+		{"different pause", newRecoveryState(slot, start), paused, "pause state"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recoveryDiscrepancy(tt.state, tt.slot)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("recoveryDiscrepancy = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecoverStateKeepsDatabaseState(t *testing.T) {
+	a := newTestApp(t)
+	a.statePath = filepath.Join(t.TempDir(), "recovery.json")
+
+	slot, err := a.StartTimer("Design")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	// The previous session crashed while another slot was running
+	stale := newRecoveryState(&models.TimeSlot{ID: slot.ID + 1, StartTime: slot.StartTime.Add(-time.Hour)}, time.Now())
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(a.statePath, data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	a.recoverState()
+
+	if active := a.GetActiveTimeSlot(); active == nil || active.ID != slot.ID {
+		t.Fatalf("active slot = %+v, want the database slot %d", active, slot.ID)
+	}
+	data, err = os.ReadFile(a.statePath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var saved RecoveryState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !saved.Running || saved.SlotID != slot.ID || !saved.StartTime.Equal(slot.StartTime) {
+		t.Errorf("recovery file = %+v, want the running slot %d", saved, slot.ID)
+	}
+}
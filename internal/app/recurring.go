@@ -0,0 +1,247 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// recurringCheckInterval is how often recurring tasks are checked for being due
+const recurringCheckInterval = time.Minute
+
+// Actions taken when a recurring task comes up
+const (
+	// RecurringLog records a completed slot once the task's end time has passed
+	RecurringLog = "log"
+	// RecurringPrompt asks the user to start the task while it is due
+	RecurringPrompt = "prompt"
+)
+
+// allWeekdays is the weekday mask of a daily recurring task
+const allWeekdays = 1<<7 - 1
+
+// RecurringTask is a task that happens at a fixed time, e.g. a 09:15-09:30 daily standup
+type RecurringTask struct {
+	ID       int64  `json:"id"`
+	TaskName string `json:"task_name"`
+	Start    string `json:"start"` // "15:04"
+	End      string `json:"end"`
+	// Weekdays are the days the task happens on as time.Weekday numbers
+	// (Sunday = 0); empty means every day
+	Weekdays []int  `json:"weekdays"`
+	Action   string `json:"action"`
+	// LastRun is the last date ("2006-01-02") the task was logged or prompted for
+	LastRun string `json:"last_run"`
+}
+
+// weekdayMask packs weekdays into a bit mask; no weekdays means every day
+func weekdayMask(weekdays []int) (int, error) {
+	if len(weekdays) == 0 {
+		return allWeekdays, nil
+	}
+	mask := 0
+	for _, day := range weekdays {
+		if day < int(time.Sunday) || day > int(time.Saturday) {
+			return 0, fmt.Errorf("invalid weekday %d, expected 0 (Sunday) to 6 (Saturday)", day)
+		}
+		mask |= 1 << day
+	}
+	return mask, nil
+}
+
+// maskWeekdays unpacks a weekday mask into sorted weekday numbers
+func maskWeekdays(mask int) []int {
+	weekdays := []int{}
+	for day := int(time.Sunday); day <= int(time.Saturday); day++ {
+		if mask&(1<<day) != 0 {
+			weekdays = append(weekdays, day)
+		}
+	}
+	return weekdays
+}
+
+// normalize validates a recurring task and fills in defaults
+// The task name is normalized, weekdays are sorted and deduplicated and an empty
+// action logs the task
+func (r *RecurringTask) normalize() error {
+	r.TaskName = normalizeTaskName(r.TaskName)
+	if r.TaskName == "" {
+		return ErrEmptyTaskName
+	}
+
+	start, err := parseClock(r.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClock(r.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if end <= start {
+		return fmt.Errorf("end must be after start on the same day")
+	}
+
+	mask, err := weekdayMask(r.Weekdays)
+	if err != nil {
+		return err
+	}
+	r.Weekdays = maskWeekdays(mask)
+
+	switch r.Action {
+	case "":
+		r.Action = RecurringLog
+	case RecurringLog, RecurringPrompt:
+	default:
+		return fmt.Errorf("unknown recurring task action %q", r.Action)
+	}
+	return nil
+}
+
+// occurrence returns the start and end of the task on the day of t, and whether
+// it happens that day at all
+func (r RecurringTask) occurrence(t time.Time) (time.Time, time.Time, bool) {
+	mask, err := weekdayMask(r.Weekdays)
+	if err != nil || mask&(1<<int(t.Weekday())) == 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, errStart := parseClock(r.Start)
+	end, errEnd := parseClock(r.End)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// Built from the wall clock so the times stay right on daylight saving days
+	return time.Date(t.Year(), t.Month(), t.Day(), start/60, start%60, 0, 0, t.Location()),
+		time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location()), true
+}
+
+// missed reports whether today's occurrence is already over at t, so creating the
+// task now shouldn't log or prompt for it retroactively
+func (r RecurringTask) missed(t time.Time) bool {
+	_, end, ok := r.occurrence(t)
+	return ok && !t.Before(end)
+}
+
+// RecurringScheduler logs recurring tasks or prompts for them when they come up
+// Each occurrence is claimed in the database first, so it is handled once even
+// when the app was already running or restarts during the task.
+type RecurringScheduler struct {
+	app *App
+	ctx context.Context
+}
+
+// NewRecurringScheduler creates a scheduler for the recurring tasks of app
+func NewRecurringScheduler(app *App) *RecurringScheduler {
+	return &RecurringScheduler{app: app}
+}
+
+// Start checks for due tasks right away and then every minute until ctx is cancelled
+func (s *RecurringScheduler) Start(ctx context.Context) {
+	s.ctx = ctx
+	s.app.goWorker(s.monitor)
+}
+
+// monitor periodically runs due recurring tasks
+func (s *RecurringScheduler) monitor() {
+	ticker := time.NewTicker(recurringCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.check(s.app.now())
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// check runs every recurring task that is due at now
+// Only today's occurrence is considered; days the app wasn't running are skipped
+func (s *RecurringScheduler) check(now time.Time) {
+	if s.app.database.IsLocked() {
+		return
+	}
+
+	tasks, err := s.app.database.GetRecurringTasks()
+	if err != nil {
+		log.Println("Failed to load recurring tasks:", err)
+		return
+	}
+
+	today := now.Format("2006-01-02")
+	for _, task := range tasks {
+		start, end, ok := task.occurrence(now)
+		if !ok || task.LastRun >= today || now.Before(start) {
+			continue
+		}
+
+		if task.Action == RecurringPrompt {
+			if now.Before(end) {
+				s.prompt(task, today)
+			}
+			continue
+		}
+		if !now.Before(end) {
+			s.logOccurrence(task, today, start, end)
+		}
+	}
+}
+
+// prompt asks the user to start a due task
+func (s *RecurringScheduler) prompt(task RecurringTask, today string) {
+	claimed, err := s.app.database.ClaimRecurringRun(task.ID, today)
+	if err != nil {
+		log.Println("Failed to claim recurring task:", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	s.app.emit(EventRecurringDue, task)
+	if s.app.notificationManager != nil {
+		s.app.notificationManager.SendNotification(
+			"Scheduled task",
+			"'"+task.TaskName+"' is scheduled for "+task.Start+"-"+task.End+". Start it in Light Tracking",
+		)
+	}
+}
+
+// logOccurrence records today's occurrence of a task as a completed slot
+// Time that is already tracked isn't tracked twice: an overlapping occurrence is
+// skipped with a notification instead
+func (s *RecurringScheduler) logOccurrence(task RecurringTask, today string, start, end time.Time) {
+	overlapping, err := s.app.database.GetOverlappingTimeSlots(0, start, end)
+	if err != nil {
+		log.Println("Failed to check recurring task overlap:", err)
+		return
+	}
+
+	if len(overlapping) > 0 {
+		claimed, err := s.app.database.ClaimRecurringRun(task.ID, today)
+		if err != nil {
+			log.Println("Failed to claim recurring task:", err)
+			return
+		}
+		if claimed && s.app.notificationManager != nil {
+			s.app.notificationManager.SendNotification(
+				"Scheduled task not logged",
+				"'"+task.TaskName+"' wasn't logged because "+task.Start+"-"+task.End+" is already tracked",
+			)
+		}
+		return
+	}
+
+	slot, err := s.app.database.LogRecurringRun(task.ID, today, task.TaskName, start, end)
+	if err != nil {
+		log.Println("Failed to log recurring task:", err)
+		return
+	}
+	if slot != nil {
+		log.Printf("Logged recurring task '%s' for %s", task.TaskName, today)
+		s.app.slotsChanged(slot)
+	}
+}
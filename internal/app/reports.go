@@ -0,0 +1,404 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// Report periods
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Task comparison statuses
+const (
+	TaskStatusNew     = "new"
+	TaskStatusDropped = "dropped"
+	TaskStatusUp      = "up"
+	TaskStatusDown    = "down"
+	TaskStatusSame    = "same"
+)
+
+// TaskDelta compares the time spent on a task in two periods
+type TaskDelta struct {
+	TaskName        string  `json:"task_name"`
+	CurrentSeconds  int64   `json:"current_seconds"`
+	PreviousSeconds int64   `json:"previous_seconds"`
+	DeltaSeconds    int64   `json:"delta_seconds"`
+	PercentChange   float64 `json:"percent_change"`
+	Status          string  `json:"status"`
+}
+
+// Comparison compares a period (week or month) with the previous one
+type Comparison struct {
+	Period        string      `json:"period"`
+	CurrentStart  time.Time   `json:"current_start"`
+	PreviousStart time.Time   `json:"previous_start"`
+	CurrentTotal  int64       `json:"current_total"`
+	PreviousTotal int64       `json:"previous_total"`
+	DeltaSeconds  int64       `json:"delta_seconds"`
+	PercentChange float64     `json:"percent_change"`
+	Tasks         []TaskDelta `json:"tasks"`
+}
+
+// TopTask is the most tracked task in a period
+type TopTask struct {
+	TaskName     string `json:"task_name"`
+	TotalSeconds int64  `json:"total_seconds"`
+}
+
+// periodBounds returns the start of the period containing date, the start of the
+// next period and the start of the previous period
+func periodBounds(date time.Time, period string) (start, next, previous time.Time, err error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	switch period {
+	case PeriodWeek:
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		start = day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), start.AddDate(0, 0, -7), nil
+	case PeriodMonth:
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0), start.AddDate(0, -1, 0), nil
+	default:
+		return time.Time{}, time.Time{}, time.Time{}, fmt.Errorf("unknown period %q, expected %q or %q", period, PeriodWeek, PeriodMonth)
+	}
+}
+
+// percentChange returns the relative change from previous to current in percent
+// It is 0 when there is nothing to compare against
+func percentChange(previous, current int64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return float64(current-previous) / float64(previous) * 100
+}
+
+// compareTaskStatistics diffs per-task totals of two periods
+// Tasks are sorted by current time, then previous time, then name
+func compareTaskStatistics(current, previous map[string]int64) []TaskDelta {
+	names := make(map[string]struct{})
+	for name := range current {
+		names[name] = struct{}{}
+	}
+	for name := range previous {
+		names[name] = struct{}{}
+	}
+
+	deltas := make([]TaskDelta, 0, len(names))
+	for name := range names {
+		cur, prev := current[name], previous[name]
+		delta := TaskDelta{
+			TaskName:        name,
+			CurrentSeconds:  cur,
+			PreviousSeconds: prev,
+			DeltaSeconds:    cur - prev,
+			PercentChange:   percentChange(prev, cur),
+		}
+
+		_, inCurrent := current[name]
+		_, inPrevious := previous[name]
+		switch {
+		case !inPrevious:
+			delta.Status = TaskStatusNew
+		case !inCurrent:
+			delta.Status = TaskStatusDropped
+		case cur > prev:
+			delta.Status = TaskStatusUp
+		case cur < prev:
+			delta.Status = TaskStatusDown
+		default:
+			delta.Status = TaskStatusSame
+		}
+
+		deltas = append(deltas, delta)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].CurrentSeconds != deltas[j].CurrentSeconds {
+			return deltas[i].CurrentSeconds > deltas[j].CurrentSeconds
+		}
+		if deltas[i].PreviousSeconds != deltas[j].PreviousSeconds {
+			return deltas[i].PreviousSeconds > deltas[j].PreviousSeconds
+		}
+		return deltas[i].TaskName < deltas[j].TaskName
+	})
+
+	return deltas
+}
+
+// sumStatistics returns the total seconds of a statistics map
+func sumStatistics(stats map[string]int64) int64 {
+	var total int64
+	for _, seconds := range stats {
+		total += seconds
+	}
+	return total
+}
+
+// LifetimeStats summarizes everything ever tracked
+type LifetimeStats struct {
+	TotalSeconds int64 `json:"total_seconds"`
+	SlotCount    int64 `json:"slot_count"`
+	// TrackingSince is the date of the first slot ("2006-01-02"), empty without history
+	TrackingSince string `json:"tracking_since"`
+}
+
+// TaskGroup is a task's sessions on a day, in chronological order
+type TaskGroup struct {
+	TaskName     string             `json:"task_name"`
+	Slots        []*models.TimeSlot `json:"slots"`
+	TotalSeconds int64              `json:"total_seconds"`
+}
+
+// groupSlotsByTask groups chronologically ordered slots by task name.
+// Totals count completed slots only, matching GetTaskStatistics; the active slot
+// is listed but not counted. Groups are sorted by rounded total, then by name.
+func groupSlotsByTask(slots []*models.TimeSlot, rounding reportRounding) []TaskGroup {
+	index := make(map[string]int)
+	groups := []TaskGroup{}
+	for _, slot := range slots {
+		i, ok := index[slot.TaskName]
+		if !ok {
+			i = len(groups)
+			index[slot.TaskName] = i
+			groups = append(groups, TaskGroup{TaskName: slot.TaskName})
+		}
+		groups[i].Slots = append(groups[i].Slots, slot)
+		if !slot.IsActive() {
+			groups[i].TotalSeconds += slot.DurationSeconds
+		}
+	}
+	for i := range groups {
+		groups[i].TotalSeconds = rounding.seconds(groups[i].TotalSeconds)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].TotalSeconds != groups[j].TotalSeconds {
+			return groups[i].TotalSeconds > groups[j].TotalSeconds
+		}
+		return groups[i].TaskName < groups[j].TaskName
+	})
+
+	return groups
+}
+
+// coalesceSlots joins consecutive completed slots of the same task and kind that
+// are at most maxGap apart, e.g. after an accidental stop and start, for display
+// Slots must be in chronological order and aren't modified; a joined slot keeps
+// the first slot's id, sums the durations and counts the gap as paused time, so
+// totals don't change. The active slot is never joined; a maxGap of 0 joins nothing.
+func coalesceSlots(slots []*models.TimeSlot, maxGap time.Duration) []*models.TimeSlot {
+	if maxGap <= 0 {
+		return slots
+	}
+	coalesced := make([]*models.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		last := len(coalesced) - 1
+		if last < 0 || !canCoalesce(coalesced[last], slot, maxGap) {
+			coalesced = append(coalesced, slot)
+			continue
+		}
+
+		// Copy the earlier slot so the caller's slots stay untouched
+		merged := *coalesced[last]
+		gap := slot.StartTime.Sub(*merged.EndTime)
+		end := *slot.EndTime
+		merged.EndTime = &end
+		merged.DurationSeconds += slot.DurationSeconds
+		merged.PausedSeconds += int64(gap.Seconds()) + slot.PausedSeconds
+		if merged.Notes != "" && slot.Notes != "" {
+			merged.Notes += "\n"
+		}
+		merged.Notes += slot.Notes
+		merged.AutoStopped = slot.AutoStopped
+		coalesced[last] = &merged
+	}
+	return coalesced
+}
+
+// canCoalesce reports whether next continues prev closely enough to show them as one slot
+func canCoalesce(prev, next *models.TimeSlot, maxGap time.Duration) bool {
+	if prev.IsActive() || next.IsActive() || prev.TaskName != next.TaskName || prev.Kind != next.Kind {
+		return false
+	}
+	gap := next.StartTime.Sub(*prev.EndTime)
+	return gap >= 0 && gap <= maxGap
+}
+
+// TaskTotal is the time tracked on a task in a period
+type TaskTotal struct {
+	TaskName     string `json:"task_name"`
+	TotalSeconds int64  `json:"total_seconds"`
+	Sessions     int    `json:"sessions"`
+}
+
+// DayTotal is the time tracked on one day
+type DayTotal struct {
+	Date         string `json:"date"`
+	TotalSeconds int64  `json:"total_seconds"`
+}
+
+// uncategorizedProject groups tasks without a project in the treemap
+const uncategorizedProject = "Uncategorized"
+
+// TreemapNode is a node of the project → task time hierarchy
+// Percent is the share of the parent node; the root is 100
+type TreemapNode struct {
+	Name     string         `json:"name"`
+	Seconds  int64          `json:"seconds"`
+	Percent  float64        `json:"percent"`
+	Children []*TreemapNode `json:"children,omitempty"`
+}
+
+// buildTreemap groups task totals under their projects
+// Tasks without a project go under "Uncategorized"; children are sorted by time, then name
+func buildTreemap(tasks []TaskTotal, projects map[string]string) *TreemapNode {
+	root := &TreemapNode{Name: "Total", Percent: 100}
+	byProject := make(map[string]*TreemapNode)
+
+	for _, task := range tasks {
+		if task.TotalSeconds <= 0 {
+			continue
+		}
+		project := projects[task.TaskName]
+		if project == "" {
+			project = uncategorizedProject
+		}
+		node, ok := byProject[project]
+		if !ok {
+			node = &TreemapNode{Name: project}
+			byProject[project] = node
+			root.Children = append(root.Children, node)
+		}
+		node.Children = append(node.Children, &TreemapNode{Name: task.TaskName, Seconds: task.TotalSeconds})
+		node.Seconds += task.TotalSeconds
+		root.Seconds += task.TotalSeconds
+	}
+
+	setTreemapPercents(root)
+	return root
+}
+
+// setTreemapPercents fills in the children's share of node and sorts them
+func setTreemapPercents(node *TreemapNode) {
+	sort.Slice(node.Children, func(i, j int) bool {
+		if node.Children[i].Seconds != node.Children[j].Seconds {
+			return node.Children[i].Seconds > node.Children[j].Seconds
+		}
+		return node.Children[i].Name < node.Children[j].Name
+	})
+	for _, child := range node.Children {
+		if node.Seconds > 0 {
+			child.Percent = float64(child.Seconds) * 100 / float64(node.Seconds)
+		}
+		setTreemapPercents(child)
+	}
+}
+
+// MonthlyReport bundles a month's totals, e.g. for invoicing
+// Tasks, days and totals cover work only; breaks are summed separately
+type MonthlyReport struct {
+	Year         int         `json:"year"`
+	Month        int         `json:"month"`
+	Tasks        []TaskTotal `json:"tasks"`
+	Days         []DayTotal  `json:"days"`
+	SessionCount int         `json:"session_count"`
+	TotalSeconds int64       `json:"total_seconds"`
+	BreakSeconds int64       `json:"break_seconds"`
+}
+
+// WorkStatistics is work time per task with the work and break totals of a period
+type WorkStatistics struct {
+	Tasks        map[string]int64 `json:"tasks"`
+	WorkSeconds  int64            `json:"work_seconds"`
+	BreakSeconds int64            `json:"break_seconds"`
+}
+
+// buildMonthlyReport assembles a report with an entry for every day of the month,
+// including days without tracking
+func buildMonthlyReport(monthStart time.Time, tasks []TaskTotal, daily map[string]int64) *MonthlyReport {
+	report := &MonthlyReport{
+		Year:  monthStart.Year(),
+		Month: int(monthStart.Month()),
+		Tasks: tasks,
+		Days:  []DayTotal{},
+	}
+
+	for _, task := range tasks {
+		report.SessionCount += task.Sessions
+		report.TotalSeconds += task.TotalSeconds
+	}
+
+	for day := monthStart; day.Month() == monthStart.Month(); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		report.Days = append(report.Days, DayTotal{Date: date, TotalSeconds: daily[date]})
+	}
+
+	return report
+}
+
+// WeekTotal is the work tracked in one ISO week with its top task
+type WeekTotal struct {
+	Year           int    `json:"year"`
+	Week           int    `json:"week"`
+	StartDate      string `json:"start_date"` // Monday of the week
+	TotalSeconds   int64  `json:"total_seconds"`
+	TopTask        string `json:"top_task"` // empty for a week without work
+	TopTaskSeconds int64  `json:"top_task_seconds"`
+}
+
+// isoWeekStart returns local midnight of the Monday starting ISO week 1 of year
+// Week 1 is the week containing January 4th
+func isoWeekStart(year int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	return jan4.AddDate(0, 0, -offset)
+}
+
+// buildYearlyWeeklyTotals sums completed work slots per ISO week of year
+// Slots count towards the week they start in, by local time. Every week of the
+// year gets an entry, including weeks without tracking, so the series is contiguous.
+func buildYearlyWeeklyTotals(year int, slots []*models.TimeSlot) []WeekTotal {
+	first := isoWeekStart(year)
+	next := isoWeekStart(year + 1)
+	weekCount := int(next.Sub(first).Hours()/24+0.5) / 7
+
+	byWeek := make([]map[string]int64, weekCount)
+	for _, slot := range slots {
+		if slot.IsActive() || slot.IsBreak() {
+			continue
+		}
+		slotYear, week := slot.StartTime.In(time.Local).ISOWeek()
+		if slotYear != year {
+			continue
+		}
+		if byWeek[week-1] == nil {
+			byWeek[week-1] = make(map[string]int64)
+		}
+		byWeek[week-1][slot.TaskName] += slot.DurationSeconds
+	}
+
+	weeks := make([]WeekTotal, 0, weekCount)
+	for i, tasks := range byWeek {
+		week := WeekTotal{
+			Year:      year,
+			Week:      i + 1,
+			StartDate: first.AddDate(0, 0, 7*i).Format("2006-01-02"),
+		}
+		for taskName, seconds := range tasks {
+			week.TotalSeconds += seconds
+			if seconds > week.TopTaskSeconds || (seconds == week.TopTaskSeconds && taskName < week.TopTask) {
+				week.TopTask = taskName
+				week.TopTaskSeconds = seconds
+			}
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
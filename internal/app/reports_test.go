@@ -0,0 +1,145 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// numbered gives slots ids 1, 2, 3, ... in order
+func numbered(slots ...*models.TimeSlot) []*models.TimeSlot {
+	for i, slot := range slots {
+		slot.ID = int64(i + 1)
+	}
+	return slots
+}
+
+// slotIDs lists the ids of slots
+func slotIDs(slots []*models.TimeSlot) []int64 {
+	ids := make([]int64, len(slots))
+	for i, slot := range slots {
+		ids[i] = slot.ID
+	}
+	return ids
+}
+
+func TestCoalesceSlots(t *testing.T) {
+	const gap = 20 * time.Second
+	breakSlot := completedSlot("A", 30*time.Minute+20*time.Second, 10*time.Minute)
+	breakSlot.Kind = models.KindBreak
+
+	tests := []struct {
+		name  string
+		slots []*models.TimeSlot
+		want  []int64
+	}{
+		{"exactly at the gap", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("A", 30*time.Minute+gap, 10*time.Minute),
+		), []int64{1}},
+		{"just over the gap", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("A", 30*time.Minute+gap+time.Second, 10*time.Minute),
+		), []int64{1, 2}},
+		{"back to back", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("A", 30*time.Minute, 10*time.Minute),
+			completedSlot("A", 40*time.Minute+5*time.Second, 10*time.Minute),
+		), []int64{1}},
+		{"another task in between", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("B", 30*time.Minute+5*time.Second, 5*time.Second),
+			completedSlot("A", 30*time.Minute+15*time.Second, 10*time.Minute),
+		), []int64{1, 2, 3}},
+		{"another kind", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			breakSlot,
+		), []int64{1, 2}},
+		{"running slot", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			activeSlot("A", 30*time.Minute+5*time.Second),
+		), []int64{1, 2}},
+		{"overlapping slots", numbered(
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("A", 29*time.Minute, 10*time.Minute),
+		), []int64{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slotIDs(coalesceSlots(tt.slots, gap)); !slices.Equal(got, tt.want) {
+				t.Errorf("coalesced ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoalesceSlotsKeepsTotals(t *testing.T) {
+	first := completedSlot("A", 0, 30*time.Minute)
+	first.Notes = "draft"
+	second := completedSlot("A", 30*time.Minute+15*time.Second, 10*time.Minute)
+	second.PausedSeconds = 60
+	second.DurationSeconds -= 60
+	second.Notes = "review"
+	slots := numbered(first, second)
+
+	got := coalesceSlots(slots, 20*time.Second)
+	if len(got) != 1 {
+		t.Fatalf("coalesced into %d slots, want 1", len(got))
+	}
+	merged := got[0]
+	if merged.ID != 1 || !merged.StartTime.Equal(first.StartTime) || !merged.EndTime.Equal(*second.EndTime) {
+		t.Errorf("merged slot %d spans %v to %v, want slot 1 spanning both", merged.ID, merged.StartTime, merged.EndTime)
+	}
+	if merged.DurationSeconds != 1800+540 || merged.PausedSeconds != 15+60 {
+		t.Errorf("merged duration and pause = %ds, %ds, want 2340s, 75s", merged.DurationSeconds, merged.PausedSeconds)
+	}
+	if merged.Notes != "draft\nreview" {
+		t.Errorf("merged notes = %q", merged.Notes)
+	}
+
+	// The input slots are left alone
+	if first.EndTime.Equal(*second.EndTime) || first.DurationSeconds != 1800 || first.Notes != "draft" {
+		t.Errorf("coalescing modified the first input slot: %+v", first)
+	}
+	if same := coalesceSlots(slots, 0); len(same) != 2 {
+		t.Errorf("a zero gap coalesced into %d slots, want 2", len(same))
+	}
+}
+
+func TestGetTimelineByDateMergesWithoutWriting(t *testing.T) {
+	a := newTestApp(t)
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("A", 0, 30*time.Minute),
+		completedSlot("A", 30*time.Minute+20*time.Second, 10*time.Minute),
+		completedSlot("B", time.Hour, 10*time.Minute),
+	} {
+		if _, err := a.database.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	timeline, err := a.GetTimelineByDate("2026-03-10", 20)
+	if err != nil {
+		t.Fatalf("GetTimelineByDate: %v", err)
+	}
+	if len(timeline) != 2 || timeline[0].DurationSeconds != 2400 {
+		t.Errorf("timeline = %d slots, first %ds, want 2 slots and 2400s", len(timeline), timeline[0].DurationSeconds)
+	}
+
+	groups, err := a.GetGroupedSlotsByDate("2026-03-10", 20, 0, "")
+	if err != nil {
+		t.Fatalf("GetGroupedSlotsByDate: %v", err)
+	}
+	if len(groups) != 2 || groups[0].TaskName != "A" || len(groups[0].Slots) != 1 || groups[0].TotalSeconds != 2400 {
+		t.Errorf("groups = %+v, want A with one joined slot of 2400s first", groups)
+	}
+
+	if n := countSlots(t, a, "2026-03-10"); n != 3 {
+		t.Errorf("%d stored slots after merging for display, want 3", n)
+	}
+	if _, err := a.GetTimelineByDate("2026-03-10", -1); err == nil {
+		t.Error("GetTimelineByDate accepted a negative gap")
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// writeRetries is how many times a busy write is retried before giving up
+	writeRetries = 4
+	// writeRetryDelay is the first backoff delay; it doubles on every retry
+	writeRetryDelay = 50 * time.Millisecond
+)
+
+// isBusyError reports whether err is a transient SQLITE_BUSY or SQLITE_LOCKED error
+func isBusyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	// Extended result codes keep the primary code in the low byte
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	default:
+		return false
+	}
+}
+
+// withRetry runs a write, retrying with exponential backoff while the database is busy
+// busy_timeout covers most contention, this catches what is left (e.g. busy on commit)
+func withRetry(fn func() error) error {
+	delay := writeRetryDelay
+	err := fn()
+	for attempt := 0; attempt < writeRetries && isBusyError(err); attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+		err = fn()
+	}
+	return err
+}
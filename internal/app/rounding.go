@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+)
+
+// Report rounding modes
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// reportRounding rounds the durations shown by statistics methods
+// Stored durations stay exact, so the same data can be viewed raw or rounded
+type reportRounding struct {
+	increment int64 // seconds, 0 disables rounding
+	mode      string
+}
+
+// newReportRounding validates the rounding parameters of a statistics method
+// roundToMinutes 0 keeps exact values; an empty mode rounds to the nearest increment
+func newReportRounding(roundToMinutes int, mode string) (reportRounding, error) {
+	if roundToMinutes < 0 {
+		return reportRounding{}, fmt.Errorf("rounding must not be negative")
+	}
+	switch mode {
+	case "":
+		mode = RoundNearest
+	case RoundNearest, RoundUp, RoundDown:
+	default:
+		return reportRounding{}, fmt.Errorf("unknown rounding mode %q", mode)
+	}
+	return reportRounding{increment: int64(roundToMinutes) * 60, mode: mode}, nil
+}
+
+// seconds rounds a duration in seconds to the increment
+func (r reportRounding) seconds(s int64) int64 {
+	if r.increment <= 0 || s <= 0 {
+		return s
+	}
+
+	remainder := s % r.increment
+	if remainder == 0 {
+		return s
+	}
+	switch r.mode {
+	case RoundUp:
+		return s - remainder + r.increment
+	case RoundDown:
+		return s - remainder
+	default:
+		if remainder*2 >= r.increment {
+			return s - remainder + r.increment
+		}
+		return s - remainder
+	}
+}
+
+// totals rounds every value of a totals map in place and returns it
+func (r reportRounding) totals(totals map[string]int64) map[string]int64 {
+	for key, seconds := range totals {
+		totals[key] = r.seconds(seconds)
+	}
+	return totals
+}
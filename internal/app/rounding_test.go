@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportRoundingSeconds(t *testing.T) {
+	tests := []struct {
+		mode    string
+		seconds int64
+		want    int64
+	}{
+		{RoundNearest, 7 * 60, 0},
+		{RoundNearest, 7*60 + 30, 15 * 60},
+		{RoundNearest, 15 * 60, 15 * 60},
+		{RoundUp, 1, 15 * 60},
+		{RoundUp, 16 * 60, 30 * 60},
+		{RoundDown, 29*60 + 59, 15 * 60},
+		{RoundDown, 0, 0},
+	}
+	for _, tt := range tests {
+		rounding, err := newReportRounding(15, tt.mode)
+		if err != nil {
+			t.Fatalf("newReportRounding(15, %q): %v", tt.mode, err)
+		}
+		if got := rounding.seconds(tt.seconds); got != tt.want {
+			t.Errorf("%s rounding of %ds = %d, want %d", tt.mode, tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestNewReportRoundingRejectsInvalid(t *testing.T) {
+	if _, err := newReportRounding(-1, ""); err == nil {
+		t.Error("negative increment accepted")
+	}
+	if _, err := newReportRounding(15, "sideways"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
+
+func TestStatsMethodsRound(t *testing.T) {
+	a := newTestApp(t)
+	day := time.Now().AddDate(0, 0, -1)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+	if _, err := a.database.CreateCompletedTimeSlot("Write", start, start.Add(20*time.Minute)); err != nil {
+		t.Fatalf("CreateCompletedTimeSlot: %v", err)
+	}
+
+	lifetime, err := a.GetLifetimeStats(15, RoundUp)
+	if err != nil {
+		t.Fatalf("GetLifetimeStats: %v", err)
+	}
+	if want := int64(30 * 60); lifetime.TotalSeconds != want {
+		t.Errorf("lifetime total = %d, want %d", lifetime.TotalSeconds, want)
+	}
+
+	groups, err := a.GetGroupedSlotsByDate(start.Format("2006-01-02"), 0, 15, RoundDown)
+	if err != nil {
+		t.Fatalf("GetGroupedSlotsByDate: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(groups))
+	}
+	if want := int64(15 * 60); groups[0].TotalSeconds != want {
+		t.Errorf("group total = %d, want %d", groups[0].TotalSeconds, want)
+	}
+	if want := int64(20 * 60); groups[0].Slots[0].DurationSeconds != want {
+		t.Errorf("slot duration = %d, want exact %d", groups[0].Slots[0].DurationSeconds, want)
+	}
+
+	trend, err := a.GetTrend("Write", start.Format("2006-01-02"), start.Format("2006-01-02"), TrendDay, 60, RoundNearest)
+	if err != nil {
+		t.Fatalf("GetTrend: %v", err)
+	}
+	if len(trend) != 1 || trend[0].Seconds != 0 {
+		t.Errorf("trend = %+v, want one bucket rounded to 0", trend)
+	}
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkWindow is a working time window for one weekday, with times in "15:04" format
+// An end at or before the start means the window crosses midnight (overnight shift)
+type WorkWindow struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// Schedule holds working hours per weekday, indexed by time.Weekday (Sunday = 0)
+// A disabled schedule means every moment counts as working time
+type Schedule struct {
+	Enabled bool          `json:"enabled"`
+	Days    [7]WorkWindow `json:"days"`
+}
+
+// DefaultSchedule returns a disabled Monday to Friday 09:00-18:00 schedule
+func DefaultSchedule() Schedule {
+	var schedule Schedule
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		schedule.Days[day] = WorkWindow{
+			Enabled: day != time.Saturday && day != time.Sunday,
+			Start:   "09:00",
+			End:     "18:00",
+		}
+	}
+	return schedule
+}
+
+// parseClock parses a "15:04" time into minutes since midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks that all windows have well-formed times
+func (s Schedule) Validate() error {
+	for day, window := range s.Days {
+		if _, err := parseClock(window.Start); err != nil {
+			return fmt.Errorf("%s start: %w", time.Weekday(day), err)
+		}
+		if _, err := parseClock(window.End); err != nil {
+			return fmt.Errorf("%s end: %w", time.Weekday(day), err)
+		}
+	}
+	return nil
+}
+
+// windowOn returns the working hours starting on the day of date, in its location
+// An overnight window ends on the next day; ok is false when the weekday has no
+// working hours
+func (s Schedule) windowOn(date time.Time) (start time.Time, end time.Time, ok bool) {
+	window := s.Days[date.Weekday()]
+	if !window.Enabled {
+		return time.Time{}, time.Time{}, false
+	}
+	startMinute, errStart := parseClock(window.Start)
+	endMinute, errEnd := parseClock(window.End)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	start = dayStart.Add(time.Duration(startMinute) * time.Minute)
+	if endMinute <= startMinute {
+		dayStart = dayStart.AddDate(0, 0, 1)
+	}
+	end = dayStart.Add(time.Duration(endMinute) * time.Minute)
+	return start, end, true
+}
+
+// IsWithinWorkHours reports whether t falls into the working hours
+// Overnight windows are attributed to the weekday on which they start
+func (s Schedule) IsWithinWorkHours(t time.Time) bool {
+	if !s.Enabled {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+
+	// Today's window, or the part of an overnight window before midnight
+	if today := s.Days[t.Weekday()]; today.Enabled {
+		start, errStart := parseClock(today.Start)
+		end, errEnd := parseClock(today.End)
+		if errStart == nil && errEnd == nil {
+			if start < end && minute >= start && minute < end {
+				return true
+			}
+			if end <= start && minute >= start {
+				return true
+			}
+		}
+	}
+
+	// The part of yesterday's overnight window after midnight
+	if yesterday := s.Days[(t.Weekday()+6)%7]; yesterday.Enabled {
+		start, errStart := parseClock(yesterday.Start)
+		end, errEnd := parseClock(yesterday.End)
+		if errStart == nil && errEnd == nil && end <= start && minute < end {
+			return true
+		}
+	}
+
+	return false
+}
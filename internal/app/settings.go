@@ -0,0 +1,246 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Tray click actions
+const (
+	TrayClickNone         = "none"
+	TrayClickToggleWindow = "toggle_window"
+	TrayClickToggleTimer  = "toggle_timer"
+)
+
+// Settings holds user-configurable application settings
+type Settings struct {
+	// StopTimerOnQuit finalizes the running slot when the app quits
+	StopTimerOnQuit bool `json:"stop_timer_on_quit"`
+	// TrayClickAction is the primary tray action: "none", "toggle_window" or "toggle_timer"
+	TrayClickAction string `json:"tray_click_action"`
+	// Schedule limits reminders and alerts to working hours
+	Schedule Schedule `json:"schedule"`
+	// StillWorkingIntervalMinutes is how often to ask whether the running task
+	// is still being worked on; 0 disables the prompt
+	StillWorkingIntervalMinutes int `json:"still_working_interval_minutes"`
+	// StillWorkingGraceMinutes is how long to wait for a confirmation
+	StillWorkingGraceMinutes int `json:"still_working_grace_minutes"`
+	// AutoStopUnconfirmed stops the timer when a prompt isn't confirmed in time
+	AutoStopUnconfirmed bool `json:"auto_stop_unconfirmed"`
+	// MergeGapMinutes is the largest gap MergeWithPrevious smooths over
+	MergeGapMinutes int `json:"merge_gap_minutes"`
+	// RoundStopToMinutes rounds slot durations to this many minutes when the
+	// timer stops; the start is kept and the end moves. 0 disables rounding
+	RoundStopToMinutes int `json:"round_stop_to_minutes"`
+	// NotificationUrgency is the urgency of long-session alerts: "low", "normal" or "critical"
+	NotificationUrgency string `json:"notification_urgency"`
+	// MaxHistoryDays deletes completed slots older than this many days on startup;
+	// 0 keeps the whole history
+	MaxHistoryDays int `json:"max_history_days"`
+	// MaxTaskNameLength is the longest accepted task name in characters; 0 disables the limit
+	MaxTaskNameLength int `json:"max_task_name_length"`
+	// DefaultTaskName is used when the timer is started without a task name; empty disables it
+	DefaultTaskName string `json:"default_task_name"`
+	// RememberWindow restores the window position and size on the next start
+	RememberWindow bool `json:"remember_window"`
+	// Window is the last saved window position and size, nil until saved
+	Window *WindowState `json:"window,omitempty"`
+	// DisableSystray skips creating the tray icon; takes effect after a restart
+	DisableSystray bool `json:"disable_systray"`
+	// ReopenWindowMinutes is how long after stopping ReopenLastStopped may
+	// resume the stopped slot; 0 disables reopening
+	ReopenWindowMinutes int `json:"reopen_window_minutes"`
+	// IdleThresholdMinutes is how long without keyboard or mouse input counts as
+	// being away while the timer runs; 0 disables idle detection
+	IdleThresholdMinutes int `json:"idle_threshold_minutes"`
+	// CountSleepTime keeps the running timer counting while the computer is
+	// suspended; when off, the time asleep is excluded on resume
+	CountSleepTime bool `json:"count_sleep_time"`
+	// ConfirmStopAfterMinutes makes StopTimer ask for confirmation before stopping
+	// a session longer than this; 0 disables the confirmation
+	ConfirmStopAfterMinutes int `json:"confirm_stop_after_minutes"`
+	// UndoStartSeconds is how long after starting UndoStart may take the start
+	// back; 0 disables undoing
+	UndoStartSeconds int `json:"undo_start_seconds"`
+	// ToggleDebounceMs ignores a start or stop this soon after the previous one,
+	// so a double click doesn't leave a near-empty slot; 0 disables it
+	ToggleDebounceMs int `json:"toggle_debounce_ms"`
+	// DailyGoalMinutes is the work time to reach each day, see GetGoalStopTime;
+	// 0 means no goal
+	DailyGoalMinutes int `json:"daily_goal_minutes"`
+	// TrayTooltip is the tray tooltip while the timer runs and TrayTooltipIdle
+	// while it's stopped; {task}, {elapsed} and {today} are replaced with the
+	// running task, its elapsed time and today's work time. Empty shows the app name
+	TrayTooltip     string `json:"tray_tooltip"`
+	TrayTooltipIdle string `json:"tray_tooltip_idle"`
+	// FocusOnStart enters focus mode whenever a work timer starts
+	FocusOnStart bool `json:"focus_on_start"`
+	// FocusMuteNotifications holds back all but critical notifications in focus mode
+	FocusMuteNotifications bool `json:"focus_mute_notifications"`
+	// CurrentContext is recorded with new slots, e.g. "home" or "office"; empty for none
+	CurrentContext string `json:"current_context"`
+	// NetworkContexts maps Wi-Fi network names to contexts that take precedence
+	// over CurrentContext while connected; empty disables network detection
+	NetworkContexts map[string]string `json:"network_contexts,omitempty"`
+	// ExportTimeZone is the time zone of CSV and JSON export timestamps: "local",
+	// "UTC" or an IANA name such as "Europe/Berlin"
+	ExportTimeZone string `json:"export_time_zone"`
+	// ActivityCheckEnabled reminds when the focused window hasn't matched the
+	// tracked task for ActivityCheckMinutes; see ActivityChecker
+	ActivityCheckEnabled bool `json:"activity_check_enabled"`
+	ActivityCheckMinutes int  `json:"activity_check_minutes"`
+	// ActivityKeywords lists per task name the words expected in the focused
+	// window's app or title, e.g. "coding": ["Visual Studio Code", "Terminal"];
+	// tasks without keywords are matched by the words of their name
+	ActivityKeywords map[string][]string `json:"activity_keywords,omitempty"`
+}
+
+// DefaultSettings returns the settings used when no settings file exists
+func DefaultSettings() Settings {
+	return Settings{
+		StopTimerOnQuit: true,
+		TrayClickAction: TrayClickToggleWindow,
+		Schedule:        DefaultSchedule(),
+
+		StillWorkingIntervalMinutes: 0,
+		StillWorkingGraceMinutes:    5,
+		AutoStopUnconfirmed:         false,
+		MergeGapMinutes:             5,
+		RoundStopToMinutes:          0,
+		NotificationUrgency:         UrgencyNormal,
+		MaxHistoryDays:              0,
+		MaxTaskNameLength:           255,
+		RememberWindow:              true,
+		ReopenWindowMinutes:         5,
+		UndoStartSeconds:            5,
+		ToggleDebounceMs:            300,
+		TrayTooltip:                 "{task} — {elapsed} / Today: {today}",
+		TrayTooltipIdle:             "Today: {today} — not tracking",
+		ExportTimeZone:              ExportTimeZoneLocal,
+		ActivityCheckMinutes:        20,
+	}
+}
+
+// clone returns a deep copy of the settings, so a copy handed out or taken in
+// by SettingsManager shares no maps, slices or pointers with the stored settings
+func (s Settings) clone() Settings {
+	if s.Window != nil {
+		window := *s.Window
+		s.Window = &window
+	}
+	s.NetworkContexts = maps.Clone(s.NetworkContexts)
+	if s.ActivityKeywords != nil {
+		keywords := make(map[string][]string, len(s.ActivityKeywords))
+		for taskName, words := range s.ActivityKeywords {
+			keywords[taskName] = slices.Clone(words)
+		}
+		s.ActivityKeywords = keywords
+	}
+	return s
+}
+
+// SettingsManager loads and persists settings in the app data directory
+type SettingsManager struct {
+	mu       sync.RWMutex
+	path     string
+	settings Settings
+}
+
+// NewSettingsManager creates a settings manager and loads settings from disk
+func NewSettingsManager() (*SettingsManager, error) {
+	appDataDir, err := getAppDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	manager := &SettingsManager{
+		path:     filepath.Join(appDataDir, "settings.json"),
+		settings: DefaultSettings(),
+	}
+
+	if err := manager.load(); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// load reads the settings file, keeping defaults for missing values
+func (m *SettingsManager) load() error {
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	m.settings = settings
+	return nil
+}
+
+// save writes the current settings to disk
+// Caller must hold the lock
+func (m *SettingsManager) save() error {
+	data, err := json.MarshalIndent(m.settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a copy of the current settings
+func (m *SettingsManager) Get() Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.settings.clone()
+}
+
+// Set replaces the current settings and persists them
+func (m *SettingsManager) Set(settings Settings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := m.settings
+	m.settings = settings.clone()
+	if err := m.save(); err != nil {
+		m.settings = previous
+		return err
+	}
+
+	return nil
+}
+
+// Update applies fn to the current settings and persists the result
+func (m *SettingsManager) Update(fn func(*Settings)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// fn works on a copy, so a failed save leaves previous untouched, and the result
+	// is copied again in case fn kept a reference to a map it set
+	previous := m.settings
+	updated := m.settings.clone()
+	fn(&updated)
+	m.settings = updated.clone()
+	if err := m.save(); err != nil {
+		m.settings = previous
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"sort"
+	"strings"
+)
+
+// levenshtein returns the edit distance between a and b in runes
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// nameSimilarity returns 1 for names equal up to case and 0 for completely different ones
+func nameSimilarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// groupSimilarNames groups names whose similarity is at least threshold.
+// Grouping is transitive: if a is like b and b is like c, all three end up together.
+// Only groups with two or more names are returned, each sorted, ordered by first name.
+func groupSimilarNames(names []string, threshold float64) [][]string {
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := 0; i < len(names); i++ {
+		for j := i + 1; j < len(names); j++ {
+			if nameSimilarity(names[i], names[j]) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	byRoot := make(map[int][]string)
+	for i, name := range names {
+		root := find(i)
+		byRoot[root] = append(byRoot[root], name)
+	}
+
+	groups := [][]string{}
+	for _, group := range byRoot {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	return groups
+}
@@ -0,0 +1,352 @@
+package app
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+const (
+	// focusBaselineSession is the average session length that earns the full length score
+	focusBaselineSession = 25 * time.Minute
+	// focusSwitchPenalty is the number of points deducted per context switch
+	focusSwitchPenalty = 5
+	// focusInterruptionPenalty is the number of points deducted per interruption marker
+	focusInterruptionPenalty = 2
+)
+
+// computeFocusScore calculates a 0-100 focus score from a day's time slots.
+//
+// The base score is the average session length relative to a 25-minute
+// baseline (a 25-minute average or longer gives 100). Every context switch,
+// i.e. two consecutive sessions for different tasks, costs 5 points, and every
+// interruption marked without leaving the slot costs 2.
+// Active and zero-length slots are ignored; slots must be in chronological order.
+func computeFocusScore(slots []*models.TimeSlot, interruptions int) int {
+	var sessions int64
+	var totalSeconds int64
+	switches := 0
+	lastTask := ""
+
+	for _, slot := range slots {
+		if slot.IsActive() || slot.DurationSeconds <= 0 {
+			continue
+		}
+		if sessions > 0 && slot.TaskName != lastTask {
+			switches++
+		}
+		sessions++
+		totalSeconds += slot.DurationSeconds
+		lastTask = slot.TaskName
+	}
+
+	if sessions == 0 {
+		return 0
+	}
+
+	average := time.Duration(totalSeconds/sessions) * time.Second
+	score := int(100 * average / focusBaselineSession)
+	if score > 100 {
+		score = 100
+	}
+
+	score -= switches*focusSwitchPenalty + interruptions*focusInterruptionPenalty
+	if score < 0 {
+		score = 0
+	}
+
+	return score
+}
+
+// Gap is an untracked period between two time slots
+type Gap struct {
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds int64     `json:"duration_seconds"`
+}
+
+// findGaps returns the untracked periods of at least minGap in [from, until).
+// The running slot covers its time up to now, slots are clipped to the range and
+// overlapping slots are treated as one covered period. With edges the time
+// before the first slot and after the last one counts too; otherwise only time
+// between slots is reported. Nothing after now is reported.
+func findGaps(slots []*models.TimeSlot, from, until, now time.Time, minGap time.Duration, edges bool) []Gap {
+	if now.Before(until) {
+		until = now
+	}
+	sorted := append([]*models.TimeSlot(nil), slots...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+
+	gaps := []Gap{}
+	addGap := func(start, end time.Time) {
+		if end.After(start) && end.Sub(start) >= minGap {
+			gaps = append(gaps, Gap{
+				Start:           start,
+				End:             end,
+				DurationSeconds: int64(end.Sub(start).Seconds()),
+			})
+		}
+	}
+
+	var coveredUntil time.Time
+	if edges {
+		coveredUntil = from
+	}
+	for _, slot := range sorted {
+		start := slot.StartTime
+		if start.Before(from) {
+			start = from
+		}
+		end := now
+		if !slot.IsActive() {
+			end = *slot.EndTime
+		}
+		if end.After(until) {
+			end = until
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		if !coveredUntil.IsZero() && start.After(coveredUntil) {
+			addGap(coveredUntil, start)
+		}
+		if end.After(coveredUntil) {
+			coveredUntil = end
+		}
+	}
+	if edges {
+		addGap(coveredUntil, until)
+	}
+
+	return gaps
+}
+
+// interval is a half-open period of time [start, end)
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// mergeIntervals sorts intervals and joins overlapping or touching ones,
+// so every moment covered by at least one interval appears exactly once
+func mergeIntervals(intervals []interval) []interval {
+	sorted := make([]interval, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start.Before(sorted[j].start)
+	})
+
+	merged := []interval{}
+	for _, iv := range sorted {
+		if !iv.end.After(iv.start) {
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && !iv.start.After(merged[last].end) {
+			if iv.end.After(merged[last].end) {
+				merged[last].end = iv.end
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// netCoverage returns the seconds of [dayStart, dayEnd) covered by at least one
+// completed slot. Overlapping and nested slots are counted once. A slot covers
+// its whole span from start to end: individual pauses aren't stored, so time
+// paused inside a slot counts as covered.
+func netCoverage(slots []*models.TimeSlot, dayStart, dayEnd time.Time) int64 {
+	intervals := make([]interval, 0, len(slots))
+	for _, slot := range slots {
+		if slot.IsActive() {
+			continue
+		}
+		start := slot.StartTime
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		end := *slot.EndTime
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		intervals = append(intervals, interval{start: start, end: end})
+	}
+
+	var covered time.Duration
+	for _, iv := range mergeIntervals(intervals) {
+		covered += iv.end.Sub(iv.start)
+	}
+	return int64(covered.Seconds())
+}
+
+// windowTotals returns the seconds per task that completed slots spent inside
+// [windowStart, windowEnd). A slot reaching past the window counts with the share
+// of its duration that falls inside, in proportion to its wall-clock span, so
+// its paused time is spread evenly over the slot.
+func windowTotals(slots []*models.TimeSlot, windowStart, windowEnd time.Time) map[string]int64 {
+	totals := make(map[string]int64)
+	for _, slot := range slots {
+		if slot.IsActive() {
+			continue
+		}
+
+		span := slot.EndTime.Sub(slot.StartTime)
+		start := slot.StartTime
+		if start.Before(windowStart) {
+			start = windowStart
+		}
+		end := *slot.EndTime
+		if end.After(windowEnd) {
+			end = windowEnd
+		}
+		if span <= 0 || !end.After(start) {
+			continue
+		}
+
+		inside := end.Sub(start)
+		totals[slot.TaskName] += int64(float64(slot.DurationSeconds) * float64(inside) / float64(span))
+	}
+
+	return totals
+}
+
+// trailingSeconds sums the time tracked between windowStart and now
+// An active slot counts up to now, its pauses excluded, so the total stays live
+func trailingSeconds(slots []*models.TimeSlot, windowStart, now time.Time) int64 {
+	closed := make([]*models.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.IsActive() {
+			live := *slot
+			end := now
+			live.EndTime = &end
+			live.CalculateDuration()
+			slot = &live
+		}
+		closed = append(closed, slot)
+	}
+
+	var total int64
+	for _, seconds := range windowTotals(closed, windowStart, now) {
+		total += seconds
+	}
+	return total
+}
+
+// GoalStop is when continued tracking reaches the daily goal
+type GoalStop struct {
+	StopTime         time.Time `json:"stop_time"`
+	GoalSeconds      int64     `json:"goal_seconds"`
+	TrackedSeconds   int64     `json:"tracked_seconds"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+}
+
+// goalStop sums the work tracked since dayStart, the running slot up to now, and
+// returns when tracking from now on reaches goal; nil once the goal is met
+func goalStop(slots []*models.TimeSlot, dayStart, now time.Time, goal time.Duration) *GoalStop {
+	work := make([]*models.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		if !slot.IsBreak() {
+			work = append(work, slot)
+		}
+	}
+
+	tracked := trailingSeconds(work, dayStart, now)
+	remaining := int64(goal.Seconds()) - tracked
+	if remaining <= 0 {
+		return nil
+	}
+	return &GoalStop{
+		StopTime:         now.Add(time.Duration(remaining) * time.Second),
+		GoalSeconds:      int64(goal.Seconds()),
+		TrackedSeconds:   tracked,
+		RemainingSeconds: remaining,
+	}
+}
+
+// longSlots returns the completed slots lasting longer than threshold, longest first
+func longSlots(slots []*models.TimeSlot, threshold time.Duration) []*models.TimeSlot {
+	long := []*models.TimeSlot{}
+	for _, slot := range slots {
+		if !slot.IsActive() && time.Duration(slot.DurationSeconds)*time.Second > threshold {
+			long = append(long, slot)
+		}
+	}
+	sort.SliceStable(long, func(i, j int) bool {
+		return long[i].DurationSeconds > long[j].DurationSeconds
+	})
+	return long
+}
+
+// LeftRunningTask counts how often a task's slots were likely left running
+type LeftRunningTask struct {
+	TaskName string `json:"task_name"`
+	Sessions int    `json:"sessions"`
+	// AutoStopped is how many slots the app stopped on its own
+	AutoStopped int `json:"auto_stopped"`
+	// LongSessions is how many slots lasted longer than the anomaly threshold
+	LongSessions int `json:"long_sessions"`
+	// Flagged is how many slots were auto-stopped, too long or both
+	Flagged int `json:"flagged"`
+	// FlaggedPercent is Flagged relative to Sessions
+	FlaggedPercent float64 `json:"flagged_percent"`
+}
+
+// rankLeftRunning fills in FlaggedPercent and orders tasks by flagged slots,
+// then by how large a share of their slots was flagged
+func rankLeftRunning(tasks []LeftRunningTask) []LeftRunningTask {
+	for i := range tasks {
+		tasks[i].FlaggedPercent = math.Round(float64(tasks[i].Flagged)*1000/float64(tasks[i].Sessions)) / 10
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Flagged != tasks[j].Flagged {
+			return tasks[i].Flagged > tasks[j].Flagged
+		}
+		if tasks[i].FlaggedPercent != tasks[j].FlaggedPercent {
+			return tasks[i].FlaggedPercent > tasks[j].FlaggedPercent
+		}
+		return tasks[i].TaskName < tasks[j].TaskName
+	})
+	return tasks
+}
+
+// weekdayTotals sums completed slot durations into Monday-first weekday buckets.
+// Slots are clipped to [rangeStart, rangeEnd) and split at local midnights, so a
+// slot running from Friday evening into Saturday counts towards both days.
+func weekdayTotals(slots []*models.TimeSlot, rangeStart, rangeEnd time.Time) [7]int64 {
+	var totals [7]int64
+	for _, slot := range slots {
+		if slot.IsActive() {
+			continue
+		}
+
+		start := slot.StartTime
+		if start.Before(rangeStart) {
+			start = rangeStart
+		}
+		end := *slot.EndTime
+		if end.After(rangeEnd) {
+			end = rangeEnd
+		}
+
+		for start.Before(end) {
+			nextMidnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+			partEnd := end
+			if nextMidnight.Before(partEnd) {
+				partEnd = nextMidnight
+			}
+
+			// time.Weekday starts on Sunday; shift so Monday is 0
+			day := (int(start.Weekday()) + 6) % 7
+			totals[day] += int64(partEnd.Sub(start).Seconds())
+			start = partEnd
+		}
+	}
+
+	return totals
+}
@@ -0,0 +1,449 @@
+package app
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// testDay is 09:00 local time on the day most slot tests use
+var testDay = time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+// completedSlot returns a completed work slot for task starting offset after
+// testDay and lasting length
+func completedSlot(task string, offset time.Duration, length time.Duration) *models.TimeSlot {
+	start := testDay.Add(offset)
+	end := start.Add(length)
+	return &models.TimeSlot{
+		TaskName:        task,
+		Kind:            models.KindWork,
+		StartTime:       start,
+		EndTime:         &end,
+		DurationSeconds: int64(length.Seconds()),
+	}
+}
+
+// activeSlot returns a running work slot for task starting offset after testDay
+func activeSlot(task string, offset time.Duration) *models.TimeSlot {
+	return &models.TimeSlot{TaskName: task, Kind: models.KindWork, StartTime: testDay.Add(offset)}
+}
+
+func TestComputeFocusScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		slots         []*models.TimeSlot
+		interruptions int
+		want          int
+	}{
+		{"no slots", nil, 0, 0},
+		{"only an active slot", []*models.TimeSlot{activeSlot("A", 0)}, 0, 0},
+		{"one baseline session", []*models.TimeSlot{completedSlot("A", 0, 25*time.Minute)}, 0, 100},
+		{"long sessions are capped", []*models.TimeSlot{completedSlot("A", 0, 3*time.Hour)}, 0, 100},
+		{"short session", []*models.TimeSlot{completedSlot("A", 0, 10*time.Minute)}, 0, 40},
+		{"same task twice has no switch", []*models.TimeSlot{
+			completedSlot("A", 0, 25*time.Minute),
+			completedSlot("A", time.Hour, 25*time.Minute),
+		}, 0, 100},
+		{"two switches", []*models.TimeSlot{
+			completedSlot("A", 0, 30*time.Minute),
+			completedSlot("B", time.Hour, 30*time.Minute),
+			completedSlot("A", 2*time.Hour, 30*time.Minute),
+		}, 0, 90},
+		{"interruptions", []*models.TimeSlot{completedSlot("A", 0, 25*time.Minute)}, 3, 94},
+		{"zero-length slots are ignored", []*models.TimeSlot{
+			completedSlot("A", 0, 25*time.Minute),
+			completedSlot("B", time.Hour, 0),
+			activeSlot("C", 2*time.Hour),
+		}, 0, 100},
+		{"never below zero", []*models.TimeSlot{
+			completedSlot("A", 0, time.Minute),
+			completedSlot("B", time.Hour, time.Minute),
+		}, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeFocusScore(tt.slots, tt.interruptions); got != tt.want {
+				t.Errorf("computeFocusScore = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekdayTotals(t *testing.T) {
+	// testDay is a Tuesday; the range covers Tuesday 10 March to Monday 16 March
+	rangeStart := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	rangeEnd := rangeStart.AddDate(0, 0, 7)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		slots []*models.TimeSlot
+		want  [7]int64
+	}{
+		{"single slot", []*models.TimeSlot{completedSlot("A", 0, 2*time.Hour)}, [7]int64{1: 7200}},
+		{"Friday evening into Saturday", []*models.TimeSlot{
+			completedSlot("A", 3*day+14*time.Hour, 150*time.Minute),
+		}, [7]int64{4: 3600, 5: 5400}},
+		{"several days and tasks", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", 2*time.Hour, 30*time.Minute),
+			completedSlot("A", day, time.Hour),
+			completedSlot("A", 5*day, 45*time.Minute),
+		}, [7]int64{1: 5400, 2: 3600, 6: 2700}},
+		{"clipped to the range", []*models.TimeSlot{
+			// Monday 23:00 before the range into Tuesday
+			completedSlot("A", -10*time.Hour, 90*time.Minute),
+			// The last Monday into the Tuesday after the range
+			completedSlot("A", 6*day+14*time.Hour+30*time.Minute, 90*time.Minute),
+		}, [7]int64{0: 1800, 1: 1800}},
+		{"active slots are skipped", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			activeSlot("B", 2*time.Hour),
+		}, [7]int64{1: 3600}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weekdayTotals(tt.slots, rangeStart, rangeEnd); got != tt.want {
+				t.Errorf("weekdayTotals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetWeekdayTotals(t *testing.T) {
+	a := newTestApp(t)
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("Late", -10*time.Hour-30*time.Minute, 2*time.Hour), // Monday 22:30 to Tuesday 00:30
+		completedSlot("Report", 4*24*time.Hour, 2*time.Hour),             // Saturday
+		completedSlot("Report", 7*24*time.Hour, time.Hour),               // Tuesday after the range
+	} {
+		if _, err := a.database.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	got, err := a.GetWeekdayTotals("2026-03-09", "2026-03-15", 0, "")
+	if err != nil {
+		t.Fatalf("GetWeekdayTotals: %v", err)
+	}
+	if want := [7]int64{0: 5400, 1: 1800, 5: 7200}; got != want {
+		t.Errorf("GetWeekdayTotals = %v, want %v", got, want)
+	}
+
+	if _, err := a.GetWeekdayTotals("2026-03-15", "2026-03-09", 0, ""); err == nil {
+		t.Error("GetWeekdayTotals accepted a reversed range")
+	}
+}
+
+func TestNetCoverage(t *testing.T) {
+	dayStart := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name  string
+		slots []*models.TimeSlot
+		want  time.Duration
+	}{
+		{"no slots", nil, 0},
+		{"disjoint", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", 2*time.Hour, 30*time.Minute),
+		}, 90 * time.Minute},
+		{"overlapping", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", 30*time.Minute, time.Hour),
+		}, 90 * time.Minute},
+		{"nested", []*models.TimeSlot{
+			completedSlot("A", 0, 2*time.Hour),
+			completedSlot("B", 30*time.Minute, 30*time.Minute),
+		}, 2 * time.Hour},
+		{"touching", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", time.Hour, 30*time.Minute),
+		}, 90 * time.Minute},
+		{"overlapping, nested and disjoint together", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", 45*time.Minute, 45*time.Minute),
+			completedSlot("C", 50*time.Minute, 10*time.Minute),
+			completedSlot("D", 3*time.Hour, 20*time.Minute),
+		}, 110 * time.Minute},
+		{"active slots are skipped", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			activeSlot("B", 30*time.Minute),
+		}, time.Hour},
+		{"clipped at midnight", []*models.TimeSlot{
+			completedSlot("A", 14*time.Hour+30*time.Minute, 90*time.Minute),
+		}, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := netCoverage(tt.slots, dayStart, dayEnd); got != int64(tt.want.Seconds()) {
+				t.Errorf("netCoverage = %ds, want %ds", got, int64(tt.want.Seconds()))
+			}
+		})
+	}
+}
+
+func TestGetNetDailyTotal(t *testing.T) {
+	a := newTestApp(t)
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("Meeting", 0, time.Hour),
+		completedSlot("Notes", 30*time.Minute, time.Hour),       // overlaps the meeting
+		completedSlot("Call", 40*time.Minute, 10*time.Minute),   // nested in both
+		completedSlot("Review", 4*time.Hour, 25*time.Minute),    // disjoint
+		completedSlot("Tomorrow", 24*time.Hour, 10*time.Minute), // another day
+	} {
+		if _, err := a.database.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	got, err := a.GetNetDailyTotal("2026-03-10", 0, "")
+	if err != nil {
+		t.Fatalf("GetNetDailyTotal: %v", err)
+	}
+	if want := int64((115 * time.Minute).Seconds()); got != want {
+		t.Errorf("GetNetDailyTotal = %ds, want %ds", got, want)
+	}
+
+	// The plain sum counts the overlap twice
+	stats, err := a.GetTaskStatistics("2026-03-10", 0, "")
+	if err != nil {
+		t.Fatalf("GetTaskStatistics: %v", err)
+	}
+	var sum int64
+	for _, seconds := range stats {
+		sum += seconds
+	}
+	if want := int64((155 * time.Minute).Seconds()); sum != want {
+		t.Errorf("summed statistics = %ds, want %ds", sum, want)
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	// testDay is the 09:00 start of a 09:00-18:00 working window
+	from, until := testDay, testDay.Add(9*time.Hour)
+	evening := testDay.Add(11 * time.Hour)
+
+	tests := []struct {
+		name   string
+		slots  []*models.TimeSlot
+		now    time.Time
+		minGap time.Duration
+		edges  bool
+		want   [][2]time.Duration // gaps as offsets from testDay
+	}{
+		{"no slots", nil, evening, time.Minute, true, [][2]time.Duration{{0, 9 * time.Hour}}},
+		{"between slots and at the edges", []*models.TimeSlot{
+			completedSlot("A", time.Hour, time.Hour),
+			completedSlot("B", 3*time.Hour, time.Hour),
+		}, evening, time.Minute, true, [][2]time.Duration{{0, time.Hour}, {2 * time.Hour, 3 * time.Hour}, {4 * time.Hour, 9 * time.Hour}}},
+		{"overlapping slots are one period", []*models.TimeSlot{
+			completedSlot("A", 0, 2*time.Hour),
+			completedSlot("B", time.Hour, 2*time.Hour),
+		}, evening, time.Minute, true, [][2]time.Duration{{3 * time.Hour, 9 * time.Hour}}},
+		{"the running slot covers up to now", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			activeSlot("B", 2*time.Hour),
+		}, testDay.Add(6 * time.Hour), time.Minute, true, [][2]time.Duration{{time.Hour, 2 * time.Hour}}},
+		{"nothing after now", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+		}, testDay.Add(3 * time.Hour), time.Minute, true, [][2]time.Duration{{time.Hour, 3 * time.Hour}}},
+		{"slots are clipped to the window", []*models.TimeSlot{
+			completedSlot("A", -time.Hour, 90*time.Minute),
+			completedSlot("B", 8*time.Hour+30*time.Minute, 90*time.Minute),
+		}, evening, time.Minute, true, [][2]time.Duration{{30 * time.Minute, 8*time.Hour + 30*time.Minute}}},
+		{"short gaps are skipped", []*models.TimeSlot{
+			completedSlot("A", 0, time.Hour),
+			completedSlot("B", time.Hour+20*time.Minute, 7*time.Hour+40*time.Minute),
+		}, evening, 30 * time.Minute, true, nil},
+		{"without edges only time between slots", []*models.TimeSlot{
+			completedSlot("A", time.Hour, time.Hour),
+			completedSlot("B", 3*time.Hour, time.Hour),
+		}, evening, time.Minute, false, [][2]time.Duration{{2 * time.Hour, 3 * time.Hour}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findGaps(tt.slots, from, until, tt.now, tt.minGap, tt.edges)
+			if len(got) != len(tt.want) {
+				t.Fatalf("findGaps = %+v, want %d gaps", got, len(tt.want))
+			}
+			for i, gap := range got {
+				start, end := testDay.Add(tt.want[i][0]), testDay.Add(tt.want[i][1])
+				if !gap.Start.Equal(start) || !gap.End.Equal(end) || gap.DurationSeconds != int64(end.Sub(start).Seconds()) {
+					t.Errorf("gap %d = %v-%v (%ds), want %v-%v", i, gap.Start, gap.End, gap.DurationSeconds, start, end)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUntrackedGapsWithinWorkHours(t *testing.T) {
+	a, _, store := newFakeTestApp(t, testDay.Add(11*time.Hour))
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("Standup", time.Hour, 30*time.Minute),
+		completedSlot("Review", 3*time.Hour, time.Hour),
+	} {
+		if _, err := store.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	// Without a schedule the edges of the day don't count
+	gaps, err := a.GetUntrackedGaps("2026-03-10", 15)
+	if err != nil {
+		t.Fatalf("GetUntrackedGaps: %v", err)
+	}
+	if len(gaps) != 1 || gaps[0].DurationSeconds != int64((90 * time.Minute).Seconds()) {
+		t.Errorf("gaps without a schedule = %+v, want the 90m between the slots", gaps)
+	}
+
+	if err := a.settings.Update(func(s *Settings) { s.Schedule.Enabled = true }); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+	gaps, err = a.GetUntrackedGaps("2026-03-10", 15)
+	if err != nil {
+		t.Fatalf("GetUntrackedGaps: %v", err)
+	}
+	want := []time.Duration{time.Hour, 90 * time.Minute, 5 * time.Hour}
+	if len(gaps) != len(want) {
+		t.Fatalf("gaps within work hours = %+v, want %v", gaps, want)
+	}
+	for i, gap := range gaps {
+		if gap.DurationSeconds != int64(want[i].Seconds()) {
+			t.Errorf("gap %d = %ds, want %ds", i, gap.DurationSeconds, int64(want[i].Seconds()))
+		}
+	}
+
+	// Saturday has no working hours in the default schedule
+	gaps, err = a.GetUntrackedGaps("2026-03-14", 15)
+	if err != nil {
+		t.Fatalf("GetUntrackedGaps: %v", err)
+	}
+	if len(gaps) != 0 {
+		t.Errorf("gaps on a day off = %+v, want none", gaps)
+	}
+}
+
+func TestWindowTotals(t *testing.T) {
+	// 12:00 to 15:00 on testDay
+	windowStart := testDay.Add(3 * time.Hour)
+	windowEnd := testDay.Add(6 * time.Hour)
+
+	paused := completedSlot("Paused", 2*time.Hour, 2*time.Hour)
+	paused.PausedSeconds = 1200
+	paused.DurationSeconds -= 1200
+
+	tests := []struct {
+		name  string
+		slots []*models.TimeSlot
+		want  map[string]int64
+	}{
+		{"inside", []*models.TimeSlot{completedSlot("A", 4*time.Hour, time.Hour)}, map[string]int64{"A": 3600}},
+		{"over the start", []*models.TimeSlot{completedSlot("A", 2*time.Hour, 2*time.Hour)}, map[string]int64{"A": 3600}},
+		{"over the end", []*models.TimeSlot{completedSlot("A", 5*time.Hour, 2*time.Hour)}, map[string]int64{"A": 3600}},
+		{"over both edges", []*models.TimeSlot{completedSlot("A", time.Hour, 7*time.Hour)}, map[string]int64{"A": 10800}},
+		{"over each edge and inside", []*models.TimeSlot{
+			completedSlot("A", 2*time.Hour+30*time.Minute, time.Hour),
+			completedSlot("B", 4*time.Hour, 30*time.Minute),
+			completedSlot("A", 5*time.Hour+45*time.Minute, time.Hour),
+		}, map[string]int64{"A": 2700, "B": 1800}},
+		{"pauses are spread over the slot", []*models.TimeSlot{paused}, map[string]int64{"Paused": 3000}},
+		{"touching the edges", []*models.TimeSlot{
+			completedSlot("A", 2*time.Hour, time.Hour),
+			completedSlot("B", 6*time.Hour, time.Hour),
+		}, map[string]int64{}},
+		{"active slots are skipped", []*models.TimeSlot{activeSlot("A", 4*time.Hour)}, map[string]int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowTotals(tt.slots, windowStart, windowEnd); !maps.Equal(got, tt.want) {
+				t.Errorf("windowTotals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStatisticsForWindow(t *testing.T) {
+	a := newTestApp(t)
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("Email", 2*time.Hour, 2*time.Hour),              // 11:00-13:00, half inside
+		completedSlot("Design", 4*time.Hour, time.Hour),               // 13:00-14:00, inside
+		completedSlot("Email", 5*time.Hour+30*time.Minute, time.Hour), // 14:30-15:30, half inside
+		completedSlot("Lunch", 6*time.Hour, time.Hour),                // starts at the window end
+	} {
+		if _, err := a.database.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	start := testDay.Add(3 * time.Hour).Format(time.RFC3339)
+	end := testDay.Add(6 * time.Hour).Format(time.RFC3339)
+	got, err := a.GetStatisticsForWindow(start, end, 0, "")
+	if err != nil {
+		t.Fatalf("GetStatisticsForWindow: %v", err)
+	}
+	if want := map[string]int64{"Email": 5400, "Design": 3600}; !maps.Equal(got, want) {
+		t.Errorf("GetStatisticsForWindow = %v, want %v", got, want)
+	}
+
+	if _, err := a.GetStatisticsForWindow(end, start, 0, ""); err == nil {
+		t.Error("GetStatisticsForWindow accepted a window ending before it starts")
+	}
+	if _, err := a.GetStatisticsForWindow("2026-03-10", end, 0, ""); err == nil {
+		t.Error("GetStatisticsForWindow accepted a start without a time")
+	}
+}
+
+func TestGetSecondsInLast(t *testing.T) {
+	// Now is 12:00 on testDay
+	a, clock := newClockedTestApp(t, testDay.Add(3*time.Hour))
+	for _, slot := range []*models.TimeSlot{
+		completedSlot("Old", -time.Hour, time.Hour),                      // 08:00-09:00, before the window
+		completedSlot("Straddling", time.Hour+30*time.Minute, time.Hour), // 10:30-11:30
+	} {
+		if _, err := a.database.CreateCompletedTimeSlot(slot.TaskName, slot.StartTime, *slot.EndTime); err != nil {
+			t.Fatalf("CreateCompletedTimeSlot: %v", err)
+		}
+	}
+
+	check := func(minutes int, roundToMinutes int, mode string, want time.Duration) {
+		t.Helper()
+		got, err := a.GetSecondsInLast(minutes, roundToMinutes, mode)
+		if err != nil {
+			t.Fatalf("GetSecondsInLast: %v", err)
+		}
+		if got != int64(want.Seconds()) {
+			t.Errorf("GetSecondsInLast(%d, %d, %q) = %ds, want %ds", minutes, roundToMinutes, mode, got, int64(want.Seconds()))
+		}
+	}
+
+	// 11:00-12:00 holds the second half of the straddling slot
+	check(60, 0, "", 30*time.Minute)
+	check(240, 0, "", 2*time.Hour)
+
+	// An active slot from 11:40 counts up to now
+	clock.Advance(-20 * time.Minute)
+	if _, err := a.StartTimer("Live"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(20 * time.Minute)
+	check(60, 0, "", 50*time.Minute)
+	check(10, 0, "", 10*time.Minute)
+
+	// Its pauses don't count
+	if err := a.PauseTimer(); err != nil {
+		t.Fatalf("PauseTimer: %v", err)
+	}
+	clock.Advance(5 * time.Minute)
+	check(60, 0, "", 45*time.Minute)
+	check(60, 15, RoundUp, 45*time.Minute)
+	check(65, 0, "", 50*time.Minute)
+	check(65, 15, RoundDown, 45*time.Minute)
+
+	if _, err := a.GetSecondsInLast(0, 0, ""); err == nil {
+		t.Error("GetSecondsInLast accepted a zero window")
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// TimeSlotStore is the storage used by Timer to persist the running slot
+type TimeSlotStore interface {
+	CreateTimeSlot(taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error)
+	StopTimeSlot(id int64, endTime time.Time) error
+	SwitchTimeSlot(stopID int64, endTime time.Time, taskName string, kind string, externalRef string, slotContext string, startTime time.Time) (*models.TimeSlot, error)
+	AutoStopTimeSlot(id int64, endTime time.Time) error
+	UpdateTimeSlot(id int64, taskName string, startTime time.Time, endTime *time.Time) error
+	RetimeTimeSlots(edits []SlotRetime, now time.Time) error
+	RenameTimeSlot(id int64, taskName string) error
+	SetTimeSlotStart(id int64, startTime time.Time) error
+	SetTimeSlotPause(id int64, pausedSeconds int64, pausedAt *time.Time) error
+	SetTimeSlotNotes(id int64, notes string) error
+	SetTimeSlotRef(id int64, externalRef string) error
+	SetTimeSlotEstimate(id int64, estimateSeconds int64) error
+	MergeIntoActiveSlot(previousID int64, activeID int64, startTime time.Time) error
+	ReopenSlot(id int64) error
+	SplitActiveSlot(id int64, start time.Time, end time.Time, taskName string) (*models.TimeSlot, error)
+	DeleteTimeSlot(id int64) error
+	AddInterruption(slotID int64, at time.Time, note string) (*Interruption, error)
+	GetActiveTimeSlot() (*models.TimeSlot, error)
+	GetLastCompletedSlot() (*models.TimeSlot, error)
+}
+
+// SlotStore holds the time slots and what is recorded against them
+type SlotStore interface {
+	TimeSlotStore
+
+	CreateCompletedTimeSlot(taskName string, startTime time.Time, endTime time.Time) (*models.TimeSlot, error)
+	CreateCompletedTimeSlots(entries []ImportEntry) (int, error)
+	StopTimeSlotAndGetStatistics(id int64, endTime time.Time, start time.Time, end time.Time) (map[string]int64, error)
+	GetTimeSlot(id int64) (*models.TimeSlot, error)
+	GetTimeSlotsByDate(date time.Time) ([]*models.TimeSlot, error)
+	GetTimeSlotsInRange(start time.Time, end time.Time) ([]*models.TimeSlot, error)
+	GetOverlappingTimeSlots(excludeID int64, start time.Time, end time.Time) ([]*models.TimeSlot, error)
+	GetTaskNames() ([]string, error)
+	GetRecentTaskNames() ([]string, error)
+
+	RecordPomodoro(pomodoro Pomodoro) (int64, error)
+	GetInterruptions(slotID int64) ([]Interruption, error)
+}
+
+// StatsStore aggregates the tracked time for statistics and reports
+type StatsStore interface {
+	GetTaskStatistics(date time.Time) (map[string]int64, error)
+	GetTaskStatisticsForRange(start time.Time, end time.Time) (map[string]int64, error)
+	GetTaskStatisticsForKind(start time.Time, end time.Time, kind string) (map[string]int64, error)
+	GetTaskTotals(start time.Time, end time.Time, kind string) ([]TaskTotal, error)
+	GetDailyTotals(start time.Time, end time.Time, kind string) (map[string]int64, error)
+	GetTaskDailyTotals(taskName string, start time.Time, end time.Time) (map[string]int64, error)
+	GetTrendTotals(taskName string, start time.Time, end time.Time, bucketKey func(time.Time) string) (map[string]int64, error)
+	GetTimeByRef(externalRef string, start time.Time, end time.Time) (map[string]int64, error)
+	GetContextTotals(start time.Time, end time.Time) (map[string]int64, error)
+	GetEstimateTotals(start time.Time, end time.Time) ([]EstimateAccuracy, error)
+	GetLeftRunningCounts(start time.Time, end time.Time, longDuration time.Duration) ([]LeftRunningTask, error)
+	GetTrackedDates(start time.Time, end time.Time) ([]string, error)
+	GetTopTask(start time.Time, end time.Time) (string, int64, error)
+	GetGrandTotal() (int64, int64, error)
+	GetFirstStartTime() (*time.Time, error)
+	GetPomodoroStats(start time.Time, end time.Time) (*PomodoroStats, error)
+	CountInterruptions(start time.Time, end time.Time) (int, error)
+}
+
+// TaskSettingsStore holds the per-task settings tables and the recurring tasks
+type TaskSettingsStore interface {
+	SetTaskColor(taskName string, color string) error
+	GetTaskColors() (map[string]string, error)
+	SetTaskProject(taskName string, project string) error
+	GetTaskProjects() (map[string]string, error)
+	SetTaskRate(taskName string, ratePerHour float64, currency string) error
+	DeleteTaskRate(taskName string) error
+	GetTaskRates() (map[string]TaskRate, error)
+
+	CreateRecurringTask(task RecurringTask) (int64, error)
+	UpdateRecurringTask(task RecurringTask) error
+	DeleteRecurringTask(id int64) error
+	GetRecurringTasks() ([]RecurringTask, error)
+	ClaimRecurringRun(id int64, date string) (bool, error)
+	LogRecurringRun(id int64, date string, taskName string, start time.Time, end time.Time) (*models.TimeSlot, error)
+}
+
+// MaintenanceStore covers the database as a whole: pruning, archives, copies and encryption
+type MaintenanceStore interface {
+	PruneOlderThan(cutoff time.Time) (int64, error)
+	RecalculateDurations() (int64, error)
+	ArchiveBefore(cutoff time.Time, archivePath string) (int, error)
+	QueryArchive(archivePath string, start time.Time, end time.Time) ([]*models.TimeSlot, error)
+	ExportFile(destPath string) error
+	Info() (*DBInfo, error)
+
+	IsLocked() bool
+	Unlock(passphrase string) error
+	EnableEncryption(passphrase string) error
+
+	Close() error
+}
+
+// Store is the storage used by App
+// Database is the production implementation; tests can substitute an in-memory one
+// for the slot and statistics parts, see fakeStore
+type Store interface {
+	SlotStore
+	StatsStore
+	TaskSettingsStore
+	MaintenanceStore
+}
+
+// Database must keep satisfying Store
+var _ Store = (*Database)(nil)
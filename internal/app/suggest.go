@@ -0,0 +1,175 @@
+package app
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+const (
+	// defaultSuggestionLimit is used when the caller doesn't pass a positive limit
+	defaultSuggestionLimit = 10
+
+	// Match scores, from the strongest to the weakest kind of match
+	scorePrefixMatch     = 100
+	scoreWordPrefixMatch = 70
+	scoreSubstringMatch  = 50
+	scoreFuzzyMatch      = 20
+	// scoreRecencyMax is added to the most recently used task and decays
+	// linearly to zero for the least recently used one
+	scoreRecencyMax = 25
+)
+
+type taskSuggestion struct {
+	name  string
+	score int
+	rank  int
+}
+
+// rankTaskSuggestions scores taskNames against query and returns the best limit names.
+// taskNames must be ordered by most recent use. Matching is case-insensitive and
+// names that don't contain the query characters in order are dropped.
+func rankTaskSuggestions(taskNames []string, query string, limit int) []string {
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	query = strings.ToLower(query)
+
+	suggestions := make([]taskSuggestion, 0, len(taskNames))
+	for rank, name := range taskNames {
+		score := matchScore(strings.ToLower(name), query)
+		if score == 0 {
+			continue
+		}
+		score += scoreRecencyMax * (len(taskNames) - rank) / len(taskNames)
+		suggestions = append(suggestions, taskSuggestion{name: name, score: score, rank: rank})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score > suggestions[j].score
+		}
+		return suggestions[i].rank < suggestions[j].rank
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	result := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = suggestion.name
+	}
+	return result
+}
+
+// matchScore rates how well a lowercased name matches a lowercased query; 0 means no match
+func matchScore(name, query string) int {
+	if strings.HasPrefix(name, query) {
+		return scorePrefixMatch
+	}
+	for _, word := range strings.Fields(name) {
+		if strings.HasPrefix(word, query) {
+			return scoreWordPrefixMatch
+		}
+	}
+	if strings.Contains(name, query) {
+		return scoreSubstringMatch
+	}
+	if isSubsequence(name, query) {
+		return scoreFuzzyMatch
+	}
+	return 0
+}
+
+// isSubsequence reports whether all runes of query appear in name in order
+func isSubsequence(name, query string) bool {
+	queryRunes := []rune(query)
+	i := 0
+	for _, r := range name {
+		if i < len(queryRunes) && r == queryRunes[i] {
+			i++
+		}
+	}
+	return i == len(queryRunes)
+}
+
+const (
+	// predictionDays is how many past days PredictNextTask looks at
+	predictionDays = 28
+	// predictionWindow is how far around the current time of day slots are considered
+	predictionWindow = 30 * time.Minute
+	// minPredictionDays is how often a task must have been the day's top task
+	// in the window before it is predicted
+	minPredictionDays = 2
+	// minPredictionConfidence is the lowest confidence at which a task is predicted
+	minPredictionConfidence = 0.4
+)
+
+// TaskPrediction is the task probably worked on at this time of day
+// TaskName is empty when no task is likely enough
+type TaskPrediction struct {
+	TaskName   string  `json:"task_name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// predictTask finds the task most often worked on around now's time of day.
+// slots should cover the predictionDays days before now. Every day with work in
+// the window around the time of day votes for the task it spent most time on;
+// confidence is the share of those days won by the top task.
+func predictTask(slots []*models.TimeSlot, now time.Time) TaskPrediction {
+	votes := make(map[string]int)
+	votingDays := 0
+
+	for days := 1; days <= predictionDays; days++ {
+		day := now.AddDate(0, 0, -days)
+		center := time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), 0, 0, now.Location())
+		windowStart, windowEnd := center.Add(-predictionWindow), center.Add(predictionWindow)
+
+		overlap := make(map[string]time.Duration)
+		for _, slot := range slots {
+			if slot.IsActive() || slot.IsBreak() {
+				continue
+			}
+			start, end := slot.StartTime, *slot.EndTime
+			if start.Before(windowStart) {
+				start = windowStart
+			}
+			if end.After(windowEnd) {
+				end = windowEnd
+			}
+			if end.After(start) {
+				overlap[slot.TaskName] += end.Sub(start)
+			}
+		}
+
+		winner := ""
+		for name, d := range overlap {
+			if winner == "" || d > overlap[winner] || (d == overlap[winner] && name < winner) {
+				winner = name
+			}
+		}
+		if winner != "" {
+			votes[winner]++
+			votingDays++
+		}
+	}
+
+	top := ""
+	for name, count := range votes {
+		if top == "" || count > votes[top] || (count == votes[top] && name < top) {
+			top = name
+		}
+	}
+	if top == "" || votes[top] < minPredictionDays {
+		return TaskPrediction{}
+	}
+
+	confidence := float64(votes[top]) / float64(votingDays)
+	if confidence < minPredictionConfidence {
+		return TaskPrediction{}
+	}
+	return TaskPrediction{TaskName: top, Confidence: confidence}
+}
@@ -1,39 +1,55 @@
 package app
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"image"
-	"image/color"
-	"image/png"
-	"math"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/getlantern/systray"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"light-tracking/internal/models"
 )
 
 type SystrayManager struct {
-	app          *App
-	ctx          context.Context
-	mu           sync.RWMutex
-	isRunning    bool
-	showItem     *systray.MenuItem
-	hideItem     *systray.MenuItem
-	quitItem     *systray.MenuItem
-	statusItem   *systray.MenuItem
-	iconActive   []byte
-	iconInactive []byte
+	app           *App
+	ctx           context.Context
+	mu            sync.RWMutex
+	statusMu      sync.Mutex // serializes updateStatus between the monitor, events and clicks
+	state         trayState
+	windowVisible bool
+	clickAction   string
+	actionItem    *systray.MenuItem
+	showItem      *systray.MenuItem
+	hideItem      *systray.MenuItem
+	quitItem      *systray.MenuItem
+	statusItem    *systray.MenuItem
+	pauseItem     *systray.MenuItem
+	undoItem      *systray.MenuItem
+	undoSlotID    int64 // slot the undo item is shown for, 0 while hidden
+	goalItem      *systray.MenuItem
+	goalTitle     string    // shown goal hint, empty while hidden
+	goalChecked   time.Time // the goal hint is recomputed at most every goalCheckInterval
+	focusItem     *systray.MenuItem
+	trayTitle     string    // elapsed time shown next to the icon in focus mode
+	tooltip       string    // current tooltip text
+	todayBase     int64     // work seconds of today's completed slots, for the tooltip
+	todayChecked  time.Time // todayBase is re-read at most every goalCheckInterval
+	todaySlotID   int64     // running slot when todayBase was read
+	icons         map[trayState][]byte
 }
 
 // NewSystrayManager creates a new systray manager
 func NewSystrayManager(app *App) *SystrayManager {
 	return &SystrayManager{
-		app: app,
+		app:           app,
+		windowVisible: true,
+		clickAction:   app.settings.Get().TrayClickAction,
 	}
 }
 
@@ -43,6 +59,7 @@ func (s *SystrayManager) Run(ctx context.Context) {
 	// Load icons before starting systray
 	s.loadIcons()
 	// Start systray in a goroutine (required for Wails)
+	// The native loop isn't a worker: it runs until the process exits
 	go systray.Run(s.onReady, s.onExit)
 }
 
@@ -83,10 +100,17 @@ func (s *SystrayManager) loadIcons() {
 		inactiveBytes = nil
 	}
 
-	// If both icons found, use them
+	// If both icons found, use them; the paused icon is optional
 	if activeBytes != nil && inactiveBytes != nil {
-		s.iconActive = activeBytes
-		s.iconInactive = inactiveBytes
+		pausedBytes, err := os.ReadFile(filepath.Join(filepath.Dir(activePath), "icon-paused.png"))
+		if err != nil {
+			pausedBytes = activeBytes
+		}
+		s.icons = map[trayState][]byte{
+			trayStopped: inactiveBytes,
+			trayRunning: activeBytes,
+			trayPaused:  pausedBytes,
+		}
 		return
 	}
 
@@ -106,77 +130,25 @@ func (s *SystrayManager) loadIcons() {
 	iconBytes, err := os.ReadFile(iconPath)
 	if err != nil {
 		// Use default icons if file not found
-		s.iconActive = s.createDefaultIcon(true)
-		s.iconInactive = s.createDefaultIcon(false)
-	} else {
-		// Use same icon for both states (will be updated when separate icons are added)
-		s.iconActive = iconBytes
-		s.iconInactive = iconBytes
-	}
-}
-
-// createDefaultIcon creates a visual PNG icon with a circle
-func (s *SystrayManager) createDefaultIcon(active bool) []byte {
-	const size = 32
-	const center = size / 2
-	const radius = 12.0
-
-	// Create RGBA image with transparent background
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
-
-	// Define colors
-	var circleColor color.RGBA
-	if active {
-		// Green color for active timer: RGB(76, 175, 80)
-		circleColor = color.RGBA{76, 175, 80, 255}
-	} else {
-		// Dark gray for inactive timer outline
-		circleColor = color.RGBA{100, 100, 100, 255}
-	}
-
-	// Draw circle
-	for y := 0; y < size; y++ {
-		for x := 0; x < size; x++ {
-			dx := float64(x) - center
-			dy := float64(y) - center
-			distance := math.Sqrt(dx*dx + dy*dy)
-
-			if active {
-				// Filled circle for active state
-				if distance <= radius {
-					img.Set(x, y, circleColor)
-				}
-			} else {
-				// Outline circle for inactive state
-				// Draw pixels that are on the circle outline (with some thickness)
-				if distance >= radius-1.5 && distance <= radius+0.5 {
-					img.Set(x, y, circleColor)
-				}
-			}
+		s.icons = map[trayState][]byte{
+			trayStopped: createDefaultIcon(trayStopped),
+			trayRunning: createDefaultIcon(trayRunning),
+			trayPaused:  createDefaultIcon(trayPaused),
 		}
-	}
-
-	// Encode to PNG
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		// Fallback to minimal PNG if encoding fails
-		return []byte{
-			0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00, 0x00, 0x0D,
-			0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
-			0x08, 0x06, 0x00, 0x00, 0x00, 0x1F, 0x15, 0xC4, 0x89, 0x00, 0x00, 0x00,
-			0x0A, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9C, 0x63, 0x00, 0x01, 0x00, 0x00,
-			0x05, 0x00, 0x01, 0x0D, 0x0A, 0x2D, 0xB4, 0x00, 0x00, 0x00, 0x00, 0x49,
-			0x45, 0x4E, 0x44, 0xAE, 0x42, 0x60, 0x82,
+	} else {
+		// Use same icon for all states (will be updated when separate icons are added)
+		s.icons = map[trayState][]byte{
+			trayStopped: iconBytes,
+			trayRunning: iconBytes,
+			trayPaused:  iconBytes,
 		}
 	}
-
-	return buf.Bytes()
 }
 
 // onReady is called when systray is ready
 func (s *SystrayManager) onReady() {
 	s.mu.RLock()
-	icon := s.iconInactive
+	icon := s.icons[trayStopped]
 	s.mu.RUnlock()
 
 	// Set icon and tooltip
@@ -185,12 +157,53 @@ func (s *SystrayManager) onReady() {
 	}
 	systray.SetTooltip("Light Tracking")
 
-	// Create menu items
+	s.addMenuItems()
+
+	// Refresh the status text right away when the active slot is edited
+	runtime.EventsOn(s.ctx, EventTimerRenamed, func(optionalData ...interface{}) {
+		s.updateStatus()
+	})
+	runtime.EventsOn(s.ctx, EventTimerAdjusted, func(optionalData ...interface{}) {
+		s.updateStatus()
+	})
+	runtime.EventsOn(s.ctx, EventFocusModeChanged, func(optionalData ...interface{}) {
+		s.updateFocus()
+	})
+
+	// Start monitoring timer status
+	s.app.goWorker(s.monitorTimerStatus)
+
+	// Handle menu clicks
+	s.app.goWorker(s.handleMenuClicks)
+}
+
+// addMenuItems creates the tray menu
+func (s *SystrayManager) addMenuItems() {
+	// getlantern/systray doesn't report clicks on the icon itself on any platform
+	// (a click opens the menu), so the configured click action is offered as
+	// the first menu item instead
+	switch s.clickAction {
+	case TrayClickToggleWindow:
+		s.actionItem = systray.AddMenuItem("Toggle Window", "Show or hide the main window")
+		systray.AddSeparator()
+	case TrayClickToggleTimer:
+		s.actionItem = systray.AddMenuItem("Start Timer", "Start or stop the timer")
+		systray.AddSeparator()
+	}
+
 	s.statusItem = systray.AddMenuItem("Timer: Stopped", "Current timer status")
 	s.statusItem.Disable()
+	s.pauseItem = systray.AddMenuItem("Pause", "Pause or resume the timer")
+	s.pauseItem.Hide()
+	s.undoItem = systray.AddMenuItem("Undo Start", "Take back the timer that was just started")
+	s.undoItem.Hide()
+	s.goalItem = systray.AddMenuItem("", "When today's work reaches the daily goal")
+	s.goalItem.Disable()
+	s.goalItem.Hide()
 
 	systray.AddSeparator()
 
+	s.focusItem = systray.AddMenuItem("Enter Focus Mode", "Hide the window and keep only the tray")
 	s.showItem = systray.AddMenuItem("Show Window", "Show the main window")
 	s.hideItem = systray.AddMenuItem("Hide Window", "Hide the main window")
 	s.hideItem.Hide()
@@ -198,12 +211,6 @@ func (s *SystrayManager) onReady() {
 	systray.AddSeparator()
 
 	s.quitItem = systray.AddMenuItem("Quit", "Quit the application")
-
-	// Start monitoring timer status
-	go s.monitorTimerStatus()
-
-	// Handle menu clicks
-	go s.handleMenuClicks()
 }
 
 // onExit is called when systray exits
@@ -227,73 +234,376 @@ func (s *SystrayManager) monitorTimerStatus() {
 }
 
 // updateStatus updates the systray icon and status based on timer state
+// It runs from the monitor, event callbacks and menu clicks; without statusMu
+// two calls could detect the same transition or apply titles out of order
 func (s *SystrayManager) updateStatus() {
-	isRunning := s.app.IsTimerRunning()
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	timerState := s.app.GetTimerState()
+	state := trayStopped
+	switch {
+	case timerState.Paused:
+		state = trayPaused
+	case timerState.Running:
+		state = trayRunning
+	}
 
 	s.mu.Lock()
-	wasRunning := s.isRunning
-	s.isRunning = isRunning
+	previous := s.state
+	s.state = state
 	s.mu.Unlock()
 
-	if wasRunning != isRunning {
+	// Icons and menu items only change on transitions to avoid flicker
+	if previous != state {
 		s.mu.RLock()
-		var icon []byte
-		if isRunning {
-			icon = s.iconActive
-		} else {
-			icon = s.iconInactive
-		}
+		icon := s.icons[state]
 		s.mu.RUnlock()
 
 		if len(icon) > 0 {
 			systray.SetIcon(icon)
 		}
 
-		if isRunning {
-			activeSlot := s.app.GetActiveTimeSlot()
-			if activeSlot != nil {
-				s.statusItem.SetTitle("Timer: Running - " + activeSlot.TaskName)
+		if s.actionItem != nil && s.clickAction == TrayClickToggleTimer {
+			if state == trayStopped {
+				s.actionItem.SetTitle("Start Timer")
 			} else {
-				s.statusItem.SetTitle("Timer: Running")
+				s.actionItem.SetTitle("Stop Timer")
 			}
+		}
+
+		switch state {
+		case trayStopped:
+			s.pauseItem.Hide()
+		case trayRunning:
+			s.pauseItem.SetTitle("Pause")
+			s.pauseItem.Show()
+		case trayPaused:
+			s.pauseItem.SetTitle("Resume")
+			s.pauseItem.Show()
+		}
+	}
+
+	// Offered for the few seconds a start can still be undone
+	var undoSlotID int64
+	if timerState.Slot != nil && s.app.CanUndoStart() {
+		undoSlotID = timerState.Slot.ID
+	}
+	if undoSlotID != s.undoSlotID {
+		s.undoSlotID = undoSlotID
+		if undoSlotID != 0 {
+			s.undoItem.SetTitle("Undo Start: " + truncateTaskName(timerState.Slot.TaskName))
+			s.undoItem.Show()
 		} else {
+			s.undoItem.Hide()
+		}
+	}
+
+	if previous != state || s.app.now().Sub(s.goalChecked) >= goalCheckInterval {
+		s.updateGoal(state)
+	}
+
+	s.updateTitle(state, timerState.ElapsedSeconds)
+	s.updateTooltip(timerState)
+
+	if state == trayStopped {
+		if previous != state {
 			s.statusItem.SetTitle("Timer: Stopped")
 		}
-	} else if isRunning {
-		// Update elapsed time in status
-		activeSlot := s.app.GetActiveTimeSlot()
-		if activeSlot != nil {
-			elapsed := s.app.GetElapsedTime()
-			hours := elapsed / 3600
-			minutes := (elapsed % 3600) / 60
-			seconds := elapsed % 60
-			s.statusItem.SetTitle("Timer: " + activeSlot.TaskName +
-				" (" + formatTime(hours, minutes, seconds) + ")")
+		return
+	}
+
+	taskName := truncateTaskName(timerState.Slot.TaskName)
+	if state == trayPaused {
+		s.statusItem.SetTitle("Timer: Paused - " + taskName)
+		return
+	}
+
+	// Time-boxed slots count down to the planned finish
+	if finish := s.app.GetPlannedFinishTime(); finish != nil && finish.RemainingSeconds > 0 {
+		remaining := finish.RemainingSeconds
+		s.statusItem.SetTitle("Timer: " + taskName +
+			" (" + formatTime(remaining/3600, (remaining%3600)/60, remaining%60) + " left)")
+		return
+	}
+
+	elapsed := timerState.ElapsedSeconds
+	hours := elapsed / 3600
+	minutes := (elapsed % 3600) / 60
+	seconds := elapsed % 60
+	s.statusItem.SetTitle("Timer: " + taskName + " (" + formatTime(hours, minutes, seconds) + ")")
+}
+
+// goalCheckInterval is how often the tray recomputes the daily goal hint
+const goalCheckInterval = 30 * time.Second
+
+// updateGoal shows when to stop to reach the daily goal while the timer runs
+// Caller must hold statusMu
+func (s *SystrayManager) updateGoal(state trayState) {
+	now := s.app.now()
+	s.goalChecked = now
+
+	title := ""
+	if state == trayRunning {
+		goal, err := s.app.GetGoalStopTime(now.Format("2006-01-02"))
+		if err != nil {
+			log.Println("Failed to compute the daily goal stop time:", err)
+		} else if goal != nil {
+			title = "Stop at " + goal.StopTime.Format("15:04") + " to hit " +
+				formatShortDuration(time.Duration(goal.GoalSeconds)*time.Second)
+		}
+	}
+	if title == s.goalTitle {
+		return
+	}
+
+	s.goalTitle = title
+	if title == "" {
+		s.goalItem.Hide()
+		return
+	}
+	s.goalItem.SetTitle(title)
+	s.goalItem.Show()
+}
+
+// updateTitle shows the elapsed time next to the icon while in focus mode
+// Caller must hold statusMu
+func (s *SystrayManager) updateTitle(state trayState, elapsed int64) {
+	title := ""
+	if s.app.IsFocusMode() {
+		switch state {
+		case trayRunning:
+			title = formatTime(elapsed/3600, (elapsed%3600)/60, elapsed%60)
+		case trayPaused:
+			title = "Paused"
 		}
 	}
+	if title == s.trayTitle {
+		return
+	}
+
+	s.trayTitle = title
+	systray.SetTitle(title)
+}
+
+// maxTooltipLength is the longest tooltip in characters; Windows cuts tooltips at 127
+const maxTooltipLength = 127
+
+// updateTooltip fills the tooltip template from the settings with live stats
+// Today's completed time is re-read when the running slot changes and every
+// goalCheckInterval, the running slot's time since midnight is added on every update
+// Caller must hold statusMu
+func (s *SystrayManager) updateTooltip(timerState *TimerState) {
+	now := s.app.now()
+	var slotID int64
+	if timerState.Slot != nil {
+		slotID = timerState.Slot.ID
+	}
+	if slotID != s.todaySlotID || now.Sub(s.todayChecked) >= goalCheckInterval {
+		s.todaySlotID = slotID
+		s.todayChecked = now
+		today, err := s.app.completedWorkToday()
+		if err != nil {
+			log.Println("Failed to read today's total for the tray tooltip:", err)
+		} else {
+			s.todayBase = today
+		}
+	}
+
+	settings := s.app.settings.Get()
+	today := s.todayBase
+	template := settings.TrayTooltipIdle
+	taskName, elapsed := "", ""
+	if timerState.Slot != nil {
+		template = settings.TrayTooltip
+		taskName = truncateTaskName(timerState.Slot.TaskName)
+		seconds := timerState.ElapsedSeconds
+		elapsed = formatTime(seconds/3600, (seconds%3600)/60, seconds%60)
+		if !timerState.Slot.IsBreak() {
+			dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			today += elapsedSince(timerState.Slot, seconds, dayStart, now)
+		}
+	}
+
+	tooltip := formatTooltip(template, taskName, elapsed, formatShortDuration(time.Duration(today)*time.Second))
+	if tooltip == s.tooltip {
+		return
+	}
+	s.tooltip = tooltip
+	systray.SetTooltip(tooltip)
+}
+
+// elapsedSince returns the part of the running slot's elapsed seconds after from
+// Only a pause still in progress is known to lie after from, so a slot started
+// before from counts the wall time since from minus that pause, at most elapsed
+func elapsedSince(slot *models.TimeSlot, elapsed int64, from, now time.Time) int64 {
+	if !slot.StartTime.Before(from) {
+		return elapsed
+	}
+	since := now.Sub(from)
+	if slot.PausedAt != nil && now.After(*slot.PausedAt) {
+		pausedFrom := *slot.PausedAt
+		if pausedFrom.Before(from) {
+			pausedFrom = from
+		}
+		since -= now.Sub(pausedFrom)
+	}
+	return max(min(int64(since.Seconds()), elapsed), 0)
+}
+
+// formatTooltip fills the {task}, {elapsed} and {today} placeholders of template
+// An empty template gives the app name
+func formatTooltip(template, taskName, elapsed, today string) string {
+	if strings.TrimSpace(template) == "" {
+		return "Light Tracking"
+	}
+	tooltip := strings.NewReplacer("{task}", taskName, "{elapsed}", elapsed, "{today}", today).Replace(template)
+	if runes := []rune(tooltip); len(runes) > maxTooltipLength {
+		tooltip = string(runes[:maxTooltipLength-1]) + "…"
+	}
+	return tooltip
+}
+
+// updateFocus matches the menu to the focus mode after it changed
+func (s *SystrayManager) updateFocus() {
+	focus := s.app.IsFocusMode()
+	if focus {
+		s.focusItem.SetTitle("Exit Focus Mode")
+	} else {
+		s.focusItem.SetTitle("Enter Focus Mode")
+	}
+	s.setWindowVisible(!focus)
+	s.updateStatus()
 }
 
 // handleMenuClicks handles clicks on systray menu items
 func (s *SystrayManager) handleMenuClicks() {
+	// A nil channel never fires, so no action item means no action case
+	var actionCh chan struct{}
+	if s.actionItem != nil {
+		actionCh = s.actionItem.ClickedCh
+	}
+
 	for {
 		select {
+		case <-actionCh:
+			s.performClickAction()
+		case <-s.pauseItem.ClickedCh:
+			s.togglePause()
+		case <-s.undoItem.ClickedCh:
+			if _, err := s.app.UndoStart(); err != nil {
+				log.Println("Failed to undo start from tray:", err)
+			}
+			s.updateStatus()
+		case <-s.focusItem.ClickedCh:
+			s.toggleFocus()
 		case <-s.showItem.ClickedCh:
-			runtime.WindowShow(s.ctx)
-			s.showItem.Hide()
-			s.hideItem.Show()
+			s.showWindow()
 		case <-s.hideItem.ClickedCh:
-			runtime.WindowHide(s.ctx)
-			s.hideItem.Hide()
-			s.showItem.Show()
+			s.setWindowVisible(false)
 		case <-s.quitItem.ClickedCh:
 			systray.Quit()
 			runtime.Quit(s.ctx)
+			// Shutdown waits for this goroutine, so don't keep it busy
+			return
 		case <-s.ctx.Done():
 			return
 		}
 	}
 }
 
+// performClickAction runs the configured tray click action
+func (s *SystrayManager) performClickAction() {
+	switch s.clickAction {
+	case TrayClickToggleWindow:
+		s.mu.RLock()
+		visible := s.windowVisible
+		s.mu.RUnlock()
+		if visible {
+			s.setWindowVisible(false)
+		} else {
+			s.showWindow()
+		}
+	case TrayClickToggleTimer:
+		if _, err := s.app.QuickToggle(); err != nil {
+			log.Println("Failed to toggle timer from tray:", err)
+		}
+		s.updateStatus()
+	}
+}
+
+// toggleFocus enters or leaves focus mode
+func (s *SystrayManager) toggleFocus() {
+	var err error
+	if s.app.IsFocusMode() {
+		err = s.app.ExitFocusMode()
+	} else {
+		err = s.app.EnterFocusMode()
+	}
+	if err != nil {
+		log.Println("Failed to toggle focus mode from tray:", err)
+	}
+}
+
+// showWindow shows the main window, which also ends focus mode
+func (s *SystrayManager) showWindow() {
+	if s.app.IsFocusMode() {
+		if err := s.app.ExitFocusMode(); err != nil {
+			log.Println("Failed to exit focus mode from tray:", err)
+		}
+		return
+	}
+	s.setWindowVisible(true)
+}
+
+// togglePause pauses a running timer or resumes a paused one
+func (s *SystrayManager) togglePause() {
+	var err error
+	if s.app.IsTimerPaused() {
+		err = s.app.ResumeTimer()
+	} else {
+		err = s.app.PauseTimer()
+	}
+	if err != nil {
+		log.Println("Failed to pause or resume timer from tray:", err)
+	}
+	s.updateStatus()
+}
+
+// setWindowVisible shows or hides the main window and swaps the menu items
+func (s *SystrayManager) setWindowVisible(visible bool) {
+	if visible {
+		runtime.WindowShow(s.ctx)
+		s.showItem.Hide()
+		s.hideItem.Show()
+	} else {
+		runtime.WindowHide(s.ctx)
+		s.hideItem.Hide()
+		s.showItem.Show()
+	}
+
+	s.mu.Lock()
+	s.windowVisible = visible
+	s.mu.Unlock()
+}
+
+// trayTaskNameLength is the most characters of a task name shown in the tray menu
+const trayTaskNameLength = 40
+
+// truncateTaskName shortens long task names with an ellipsis to keep the menu readable
+func truncateTaskName(taskName string) string {
+	return truncateRunes(taskName, trayTaskNameLength)
+}
+
+// truncateRunes cuts text to at most length characters, ending it with an ellipsis when cut
+func truncateRunes(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-1]) + "…"
+}
+
 // formatTime formats hours, minutes, seconds as HH:MM:SS
 func formatTime(hours, minutes, seconds int64) string {
 	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"light-tracking/internal/models"
+)
+
+// newTestSystray returns a tray for a with its menu built and icons loaded, but
+// without the native loop or Wails events
+func newTestSystray(t *testing.T, a *App, ctx context.Context) *SystrayManager {
+	t.Helper()
+
+	s := NewSystrayManager(a)
+	s.ctx = ctx
+	s.loadIcons()
+	s.addMenuItems()
+	return s
+}
+
+// TestSystrayConcurrentUpdates changes the timer from many goroutines while the
+// monitor, menu clicks and event callbacks refresh the tray; run it with -race
+func TestSystrayConcurrentUpdates(t *testing.T) {
+	a := newTestApp(t)
+	if err := a.settings.Update(func(s *Settings) {
+		s.ToggleDebounceMs = 0
+		s.TrayClickAction = TrayClickToggleTimer
+	}); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestSystray(t, a, ctx)
+
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		s.handleMenuClicks()
+	}()
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				switch (w + i) % 5 {
+				case 0:
+					a.StartTimer("Task")
+				case 1:
+					a.StopTimerConfirmed()
+				case 2:
+					a.PauseTimer()
+				case 3:
+					a.ResumeTimer()
+				default:
+					a.RenameActiveSlot("Renamed")
+				}
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				// The monitor tick and the rename and adjust event callbacks
+				s.updateStatus()
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 20 {
+			if i%2 == 0 {
+				s.actionItem.ClickedCh <- struct{}{}
+			} else {
+				s.pauseItem.ClickedCh <- struct{}{}
+			}
+		}
+	}()
+	wg.Wait()
+
+	cancel()
+	background.Wait()
+
+	// Once things settle the tray matches the timer
+	s.updateStatus()
+	s.mu.RLock()
+	state := s.state
+	s.mu.RUnlock()
+	want := trayStopped
+	switch timerState := a.GetTimerState(); {
+	case timerState.Paused:
+		want = trayPaused
+	case timerState.Running:
+		want = trayRunning
+	}
+	if state != want {
+		t.Errorf("tray state = %v, want %v", state, want)
+	}
+}
+
+func TestSystrayStatusTitle(t *testing.T) {
+	a, clock := newClockedTestApp(t, time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	s := newTestSystray(t, a, context.Background())
+
+	if _, err := a.StartTimer("Write report"); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	clock.Advance(90 * time.Second)
+	s.updateStatus()
+	if got := s.statusItem.String(); !strings.Contains(got, "Timer: Write report (00:01:30)") {
+		t.Errorf("status item = %q, want the task and elapsed time", got)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := a.StopTimerConfirmed(); err != nil {
+		t.Fatalf("StopTimerConfirmed: %v", err)
+	}
+	s.updateStatus()
+	if got := s.statusItem.String(); !strings.Contains(got, "Timer: Stopped") {
+		t.Errorf("status item = %q, want stopped", got)
+	}
+}
+
+func TestTruncateTaskName(t *testing.T) {
+	atLimit := strings.Repeat("ж", trayTaskNameLength)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"short", "Design", "Design"},
+		{"empty", "", ""},
+		{"at the limit", atLimit, atLimit},
+		{"one over the limit", atLimit + "x", strings.Repeat("ж", trayTaskNameLength-1) + "…"},
+		{"far over the limit", strings.Repeat("ab", 300), strings.Repeat("ab", 300)[:trayTaskNameLength-1] + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateTaskName(tt.in)
+			if got != tt.want {
+				t.Errorf("truncateTaskName = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > trayTaskNameLength {
+				t.Errorf("truncated name has %d characters, want at most %d", n, trayTaskNameLength)
+			}
+		})
+	}
+}
+
+func TestElapsedSince(t *testing.T) {
+	midnight := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	now := midnight.Add(2 * time.Hour)
+	at := func(offset time.Duration) *time.Time {
+		ts := midnight.Add(offset)
+		return &ts
+	}
+
+	tests := []struct {
+		name    string
+		slot    *models.TimeSlot
+		elapsed int64
+		want    int64
+	}{
+		{"started today", &models.TimeSlot{StartTime: midnight.Add(time.Hour)}, 3600, 3600},
+		{"started yesterday", &models.TimeSlot{StartTime: midnight.Add(-3 * time.Hour)}, 5 * 3600, 2 * 3600},
+		{"paused yesterday", &models.TimeSlot{StartTime: midnight.Add(-3 * time.Hour), PausedAt: at(-time.Hour)}, 2 * 3600, 0},
+		{"paused today", &models.TimeSlot{StartTime: midnight.Add(-3 * time.Hour), PausedAt: at(90 * time.Minute)}, 4*3600 + 1800, 5400},
+		{"paused most of the night", &models.TimeSlot{StartTime: midnight.Add(-3 * time.Hour), PausedSeconds: 4 * 3600}, 3600, 3600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elapsedSince(tt.slot, tt.elapsed, midnight, now); got != tt.want {
+				t.Errorf("elapsedSince = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystrayStatusTruncatesTaskName(t *testing.T) {
+	a := newTestApp(t)
+	s := newTestSystray(t, a, context.Background())
+
+	long := strings.Repeat("Quarterly planning ", 10) + "Q1"
+	if _, err := a.StartTimer(long); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	s.updateStatus()
+	got := s.statusItem.String()
+	if strings.Contains(got, long) || !strings.Contains(got, truncateTaskName(long)) {
+		t.Errorf("status item = %q, want the task name truncated", got)
+	}
+}
+
+// TestSystrayReadsActiveSlotWhileMutated renames, retimes and pauses the running
+// slot while the tray and other callers read it and scribble on the returned
+// slots; run it with -race
+func TestSystrayReadsActiveSlotWhileMutated(t *testing.T) {
+	a := newTestApp(t)
+	if err := a.settings.Update(func(s *Settings) { s.ToggleDebounceMs = 0 }); err != nil {
+		t.Fatalf("Update settings: %v", err)
+	}
+	s := newTestSystray(t, a, context.Background())
+	started, err := a.StartTimer("Task")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			switch i % 4 {
+			case 0:
+				a.RenameActiveSlot(fmt.Sprintf("Task %d", i))
+			case 1:
+				a.AdjustActiveStart(started.StartTime.Add(-time.Duration(i) * time.Second).Format(time.RFC3339))
+			case 2:
+				a.PauseTimer()
+			default:
+				a.ResumeTimer()
+			}
+		}
+	}()
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				s.updateStatus()
+				if slot := a.GetActiveTimeSlot(); slot != nil {
+					slot.TaskName = "Scribbled"
+					slot.StartTime = time.Time{}
+					now := time.Now()
+					slot.PausedAt = &now
+				}
+				if state := a.GetTimerState(); state.Slot != nil {
+					state.Slot.TaskName = "Scribbled"
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	slot := a.GetActiveTimeSlot()
+	if slot == nil {
+		t.Fatal("the timer stopped")
+	}
+	if slot.TaskName != "Task 96" || slot.StartTime.IsZero() {
+		t.Errorf("active slot = %q from %v, want the last rename and a real start", slot.TaskName, slot.StartTime)
+	}
+	if a.timer.IsPaused() {
+		t.Error("the timer is paused after the last resume")
+	}
+}
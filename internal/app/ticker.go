@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTickInterval = time.Second
+	maxTickInterval     = time.Minute
+)
+
+// TickEmitter sends timer:tick events with the elapsed seconds while the timer runs,
+// so the frontend counter doesn't depend on its own (throttled) setInterval
+type TickEmitter struct {
+	app      *App
+	ctx      context.Context
+	mu       sync.Mutex
+	interval time.Duration
+	cancel   context.CancelFunc // stops the running tick loop, nil when idle
+}
+
+// NewTickEmitter creates a tick emitter with one-second ticks
+func NewTickEmitter(app *App) *TickEmitter {
+	return &TickEmitter{
+		app:      app,
+		interval: defaultTickInterval,
+	}
+}
+
+// Start begins ticking if the timer is running and follows later starts and stops
+func (e *TickEmitter) Start(ctx context.Context) {
+	e.ctx = ctx
+	e.restart()
+	e.app.goWorker(e.watchTimer)
+}
+
+// watchTimer restarts the tick loop whenever the timer starts or stops
+func (e *TickEmitter) watchTimer() {
+	for {
+		select {
+		case <-e.app.timer.Changes():
+			e.restart()
+		case <-e.ctx.Done():
+			e.mu.Lock()
+			e.stopLocked()
+			e.mu.Unlock()
+			return
+		}
+	}
+}
+
+// SetInterval changes the tick granularity and applies it immediately
+func (e *TickEmitter) SetInterval(interval time.Duration) {
+	e.mu.Lock()
+	e.interval = interval
+	e.mu.Unlock()
+
+	e.restart()
+}
+
+// restart cancels the current tick loop and starts a new one if the timer is running
+func (e *TickEmitter) restart() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopLocked()
+	if e.ctx == nil || e.ctx.Err() != nil || !e.app.timer.IsRunning() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(e.ctx)
+	e.cancel = cancel
+	interval := e.interval
+	e.app.goWorker(func() { e.tick(ctx, interval) })
+}
+
+// stopLocked cancels the current tick loop
+// Caller must hold the lock
+func (e *TickEmitter) stopLocked() {
+	if e.cancel != nil {
+		e.cancel()
+		e.cancel = nil
+	}
+}
+
+// tick emits the elapsed time right away and then once per interval until ctx is cancelled
+// A paused timer gets a single tick with the frozen elapsed time
+func (e *TickEmitter) tick(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !e.app.timer.IsRunning() {
+			return
+		}
+		e.app.emit(EventTimerTick, e.app.GetElapsedTime())
+		if e.app.timer.IsPaused() {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// validateTickInterval checks a tick interval requested by the frontend
+func validateTickInterval(seconds int) (time.Duration, error) {
+	interval := time.Duration(seconds) * time.Second
+	if interval < defaultTickInterval || interval > maxTickInterval {
+		return 0, fmt.Errorf("tick interval must be between 1 and %d seconds", int(maxTickInterval.Seconds()))
+	}
+	return interval, nil
+}
@@ -1,77 +1,280 @@
 package app
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"light-tracking/internal/models"
 )
 
+// Timer tracks the running slot
+// State transitions are sent as commands to the goroutine started by NewTimer,
+// which applies them one at a time with their store calls, see run. Starts, stops
+// and pauses from the UI, the tray and background workers are therefore
+// linearized: two concurrent starts can't both see no running slot and create two.
+// mu guards the fields for the read methods, which don't go through run.
 type Timer struct {
+	store         TimeSlotStore
+	now           func() time.Time // clock, time.Now outside tests
+	commands      chan timerRequest
+	done          chan struct{} // closed by Close
+	closeOnce     sync.Once
 	mu            sync.RWMutex
 	activeSlot    *models.TimeSlot
 	isRunning     bool
 	startTime     time.Time
 	notifyChannel chan bool
+	stopRounding  time.Duration // durations are rounded to this increment on stop, 0 disables
+	planned       time.Duration // planned length of the running slot, 0 when there is no plan
+	lastEnd       time.Time     // end of the last stopped slot, after now when stop rounding went up
+
+	// Last Start, so UndoStart can take it back
+	startedID int64            // slot created by the last Start
+	startedAt time.Time        // when the last Start happened
+	replaced  *models.TimeSlot // slot the last Start stopped, nil if none was running
+}
+
+// timerRequest is a state transition sent to run
+type timerRequest struct {
+	apply    func() (*models.TimeSlot, error)
+	response chan timerResponse
+}
+
+// timerResponse is the result of a timerRequest
+type timerResponse struct {
+	slot *models.TimeSlot
+	err  error
 }
 
-func NewTimer() *Timer {
-	return &Timer{
+// NewTimer creates a timer persisting its slots in store and starts its
+// command goroutine; Close stops it
+func NewTimer(store TimeSlotStore) *Timer {
+	t := &Timer{
+		store:         store,
+		now:           time.Now,
+		commands:      make(chan timerRequest),
+		done:          make(chan struct{}),
 		notifyChannel: make(chan bool, 1),
 	}
+	go t.run()
+	return t
 }
 
-// Start starts the timer with a task name
-func (t *Timer) Start(taskName string, db *Database) (*models.TimeSlot, error) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	// If there's an active slot, stop it first
-	if t.activeSlot != nil && t.activeSlot.IsActive() {
-		err := db.StopTimeSlot(t.activeSlot.ID, time.Now())
-		if err != nil {
-			return nil, err
+// run applies the commands sent by do until Close is called
+func (t *Timer) run() {
+	for {
+		select {
+		case req := <-t.commands:
+			slot, err := req.apply()
+			req.response <- timerResponse{slot: slot, err: err}
+		case <-t.done:
+			return
 		}
 	}
+}
 
-	// Create new time slot
-	now := time.Now()
-	slot, err := db.CreateTimeSlot(taskName, now)
-	if err != nil {
-		return nil, err
+// do runs apply on the command goroutine and waits for its result
+// apply must not call other state transitions of the timer, they would deadlock
+func (t *Timer) do(apply func() (*models.TimeSlot, error)) (*models.TimeSlot, error) {
+	req := timerRequest{apply: apply, response: make(chan timerResponse, 1)}
+	select {
+	case t.commands <- req:
+	case <-t.done:
+		return nil, ErrTimerClosed
 	}
+	resp := <-req.response
+	return resp.slot, resp.err
+}
 
-	t.activeSlot = slot
-	t.isRunning = true
-	t.startTime = now
+// doErr is do for state transitions that only return an error
+func (t *Timer) doErr(apply func() error) error {
+	_, err := t.do(func() (*models.TimeSlot, error) {
+		return nil, apply()
+	})
+	return err
+}
 
-	// Notify that timer started
-	select {
-	case t.notifyChannel <- true:
-	default:
-	}
+// Close stops the command goroutine; later state transitions fail with ErrTimerClosed
+func (t *Timer) Close() {
+	t.closeOnce.Do(func() { close(t.done) })
+}
 
-	return slot, nil
+// Start starts the timer with a task name, slot kind, external reference and context
+func (t *Timer) Start(taskName string, kind string, externalRef string, slotContext string) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		now := t.now()
+		running := t.activeSlot != nil && t.activeSlot.IsActive()
+		endTime := t.lastEnd
+		if running {
+			endTime = t.stopEnd(now)
+		}
+		// The new slot starts after the previous one, whose end stop rounding may have moved past now
+		startTime := now
+		if endTime.After(startTime) {
+			startTime = endTime
+		}
+
+		var replaced *models.TimeSlot
+		var slot *models.TimeSlot
+		var err error
+		if running {
+			// Stop the running slot and create the new one in one transaction, so a
+			// failure leaves the running slot as it was
+			slot, err = t.store.SwitchTimeSlot(t.activeSlot.ID, endTime, taskName, kind, externalRef, slotContext, startTime)
+			if err != nil {
+				return nil, err
+			}
+			replaced = t.activeSlot
+			t.lastEnd = endTime
+		} else {
+			slot, err = t.store.CreateTimeSlot(taskName, kind, externalRef, slotContext, startTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		t.activeSlot = slot
+		t.isRunning = true
+		t.startTime = startTime
+		t.planned = 0
+		t.startedID = slot.ID
+		t.startedAt = now
+		t.replaced = replaced
+
+		// Notify that timer started
+		select {
+		case t.notifyChannel <- true:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
 }
 
 // Stop stops the current timer
-func (t *Timer) Stop(db *Database) (*models.TimeSlot, error) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+func (t *Timer) Stop() (*models.TimeSlot, error) {
+	return t.StopAt(t.now())
+}
+
+// StopAt stops the current timer with the given end time
+func (t *Timer) StopAt(endTime time.Time) (*models.TimeSlot, error) {
+	return t.StopWith(endTime, t.store.StopTimeSlot)
+}
+
+// StopWith stops the current timer, persisting the stop through stop
+// stop receives the end time after rounding. It runs on the command goroutine,
+// so no other start or stop can interleave, and must not use the timer's state
+// transitions. A paused slot ends when its pause began.
+func (t *Timer) StopWith(endTime time.Time, stop func(id int64, endTime time.Time) error) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, nil
+		}
+
+		endTime = t.stopEnd(endTime)
+		if err := stop(t.activeSlot.ID, endTime); err != nil {
+			return nil, err
+		}
+
+		stoppedSlot := t.activeSlot
+		stoppedSlot.EndTime = &endTime
+		t.lastEnd = endTime
+		stoppedSlot.PausedSeconds = int64(stoppedSlot.PausedUntil(endTime).Seconds())
+		stoppedSlot.PausedAt = nil
+		stoppedSlot.CalculateDuration()
+		t.activeSlot = nil
+		t.isRunning = false
+		t.planned = 0
+
+		// Notify that timer stopped
+		select {
+		case t.notifyChannel <- false:
+		default:
+		}
+
+		return stoppedSlot, nil
+	})
+}
+
+// UpdateSlot saves an edit of any time slot and keeps the timer in sync when the
+// edited slot is the running one: a new name or start applies immediately and an
+// end time stops the timer. The running slot is returned if it is still active
+// after the edit, nil otherwise.
+func (t *Timer) UpdateSlot(id int64, taskName string, startTime time.Time, endTime *time.Time) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if err := t.store.UpdateTimeSlot(id, taskName, startTime, endTime); err != nil {
+			return nil, err
+		}
+
+		if t.activeSlot == nil || t.activeSlot.ID != id {
+			return nil, nil
+		}
+
+		t.activeSlot.TaskName = taskName
+		return t.retimeActive(startTime, endTime), nil
+	})
+}
+
+// RetimeSlots applies a batch of time edits atomically and keeps the timer in
+// sync like UpdateSlot when the running slot is among them
+func (t *Timer) RetimeSlots(edits []SlotRetime) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
 
-	if t.activeSlot == nil || !t.activeSlot.IsActive() {
+		// Only the running slot may stay without an end time
+		for _, e := range edits {
+			if e.EndTime == nil && (t.activeSlot == nil || t.activeSlot.ID != e.ID) {
+				return nil, fmt.Errorf("time slot %d: end time is required for a completed slot", e.ID)
+			}
+		}
+
+		if err := t.store.RetimeTimeSlots(edits, t.now()); err != nil {
+			return nil, err
+		}
+
+		if t.activeSlot == nil {
+			return nil, nil
+		}
+		for _, e := range edits {
+			if e.ID == t.activeSlot.ID {
+				return t.retimeActive(e.StartTime, e.EndTime), nil
+			}
+		}
 		return nil, nil
-	}
+	})
+}
 
-	now := time.Now()
-	err := db.StopTimeSlot(t.activeSlot.ID, now)
-	if err != nil {
-		return nil, err
+// retimeActive applies a saved time edit to the running slot; an end time stops
+// the timer. It returns the running slot, or nil if it was stopped.
+// Caller must hold the lock
+func (t *Timer) retimeActive(startTime time.Time, endTime *time.Time) *models.TimeSlot {
+	t.activeSlot.StartTime = startTime
+	t.startTime = startTime
+	if endTime == nil {
+		return t.activeCopy()
 	}
 
-	stoppedSlot := t.activeSlot
+	t.activeSlot.EndTime = endTime
+	t.activeSlot.PausedSeconds = int64(t.activeSlot.PausedUntil(*endTime).Seconds())
+	t.activeSlot.PausedAt = nil
+	t.activeSlot.CalculateDuration()
+	if t.activeSlot.DurationSeconds < 0 {
+		t.activeSlot.DurationSeconds = 0
+	}
 	t.activeSlot = nil
 	t.isRunning = false
+	t.planned = 0
 
 	// Notify that timer stopped
 	select {
@@ -79,14 +282,543 @@ func (t *Timer) Stop(db *Database) (*models.TimeSlot, error) {
 	default:
 	}
 
-	return stoppedSlot, nil
+	return nil
+}
+
+// SetPlannedDuration sets how long the running slot is planned to take
+// 0 clears the plan; the plan is also cleared when the timer stops
+func (t *Timer) SetPlannedDuration(planned time.Duration) error {
+	return t.doErr(func() error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return fmt.Errorf("%w, nothing to plan", ErrTimerNotRunning)
+		}
+		t.planned = planned
+		return nil
+	})
+}
+
+// GetPlannedDuration returns the planned length of the running slot, 0 if there is no plan
+func (t *Timer) GetPlannedDuration() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if !t.isRunning {
+		return 0
+	}
+	return t.planned
+}
+
+// setClock replaces the clock the timer reads the current time from, for tests
+func (t *Timer) setClock(now func() time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+// SetStopRounding sets the increment slot durations are rounded to when stopped
+// 0 keeps the exact end time
+func (t *Timer) SetStopRounding(increment time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopRounding = increment
+}
+
+// stopEnd returns the end time for stopping the active slot at end: a paused
+// slot ends when its pause began, and the end moves so the duration, paused
+// time excluded, is rounded to the nearest stop rounding increment
+// Caller must hold the lock
+func (t *Timer) stopEnd(end time.Time) time.Time {
+	slot := t.activeSlot
+	if slot.PausedAt != nil && slot.PausedAt.Before(end) {
+		end = *slot.PausedAt
+	}
+	if end.Before(slot.StartTime) {
+		end = slot.StartTime
+	}
+	if t.stopRounding <= 0 {
+		return end
+	}
+
+	paused := time.Duration(slot.PausedSeconds) * time.Second
+	active := end.Sub(slot.StartTime) - paused
+	return slot.StartTime.Add(paused + active.Round(t.stopRounding))
+}
+
+// Pause pauses the running slot; paused time doesn't count towards its duration
+func (t *Timer) Pause() (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, fmt.Errorf("%w, nothing to pause", ErrTimerNotRunning)
+		}
+		if t.activeSlot.IsPaused() {
+			return nil, fmt.Errorf("timer is already paused")
+		}
+
+		now := t.now()
+		if err := t.store.SetTimeSlotPause(t.activeSlot.ID, t.activeSlot.PausedSeconds, &now); err != nil {
+			return nil, err
+		}
+		t.activeSlot.PausedAt = &now
+
+		// Notify that timer paused
+		select {
+		case t.notifyChannel <- false:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
+}
+
+// Resume continues a paused slot, adding the pause to its paused time
+func (t *Timer) Resume() (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsPaused() {
+			return nil, fmt.Errorf("no paused timer to resume")
+		}
+
+		pausedSeconds := int64(t.activeSlot.PausedUntil(t.now()).Seconds())
+		if err := t.store.SetTimeSlotPause(t.activeSlot.ID, pausedSeconds, nil); err != nil {
+			return nil, err
+		}
+		t.activeSlot.PausedSeconds = pausedSeconds
+		t.activeSlot.PausedAt = nil
+
+		// Notify that timer resumed
+		select {
+		case t.notifyChannel <- true:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
+}
+
+// IsPaused returns whether the running slot is paused
+func (t *Timer) IsPaused() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.activeSlot != nil && t.activeSlot.IsPaused()
+}
+
+// Discard deletes the active slot without recording it
+func (t *Timer) Discard() (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, nil
+		}
+
+		if err := t.store.DeleteTimeSlot(t.activeSlot.ID); err != nil {
+			return nil, err
+		}
+
+		discardedSlot := t.activeSlot
+		t.activeSlot = nil
+		t.isRunning = false
+		t.planned = 0
+
+		// Notify that timer stopped
+		select {
+		case t.notifyChannel <- false:
+		default:
+		}
+
+		return discardedSlot, nil
+	})
+}
+
+// CanUndoStart reports whether the running slot was started at most grace ago
+func (t *Timer) CanUndoStart(grace time.Duration) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.canUndoStart(grace)
+}
+
+// canUndoStart is CanUndoStart for callers holding the lock
+func (t *Timer) canUndoStart(grace time.Duration) bool {
+	return grace > 0 && t.activeSlot != nil && t.activeSlot.IsActive() &&
+		t.activeSlot.ID == t.startedID && t.now().Sub(t.startedAt) <= grace
+}
+
+// UndoStart deletes the running slot if Start created it at most grace ago
+// A slot that Start stopped is resumed, so starting the wrong task by mistake
+// leaves no trace. Returns the resumed slot, nil when none was running before.
+func (t *Timer) UndoStart(grace time.Duration) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, ErrTimerNotRunning
+		}
+		if grace <= 0 {
+			return nil, fmt.Errorf("undoing a start is disabled")
+		}
+		if !t.canUndoStart(grace) {
+			return nil, fmt.Errorf("only a timer started within the last %d seconds can be undone", int(grace.Seconds()))
+		}
+
+		if err := t.store.DeleteTimeSlot(t.activeSlot.ID); err != nil {
+			return nil, err
+		}
+		t.activeSlot = nil
+		t.isRunning = false
+		t.planned = 0
+		t.startedID = 0
+
+		replaced := t.replaced
+		t.replaced = nil
+		if replaced == nil {
+			select {
+			case t.notifyChannel <- false:
+			default:
+			}
+			return nil, nil
+		}
+
+		if err := t.store.ReopenSlot(replaced.ID); err != nil {
+			return nil, fmt.Errorf("failed to resume the previous time slot: %w", err)
+		}
+		// Reload it, stopping may have folded a pause into its paused time
+		resumed, err := t.store.GetActiveTimeSlot()
+		if err != nil {
+			return nil, err
+		}
+		if resumed == nil {
+			return nil, fmt.Errorf("resumed time slot %w", ErrNotFound)
+		}
+		t.activeSlot = resumed
+		t.isRunning = true
+		t.startTime = resumed.StartTime
+
+		select {
+		case t.notifyChannel <- true:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
+}
+
+// Rename changes the task name of the active slot without stopping it
+func (t *Timer) Rename(taskName string) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, fmt.Errorf("%w, nothing to rename", ErrTimerNotRunning)
+		}
+
+		if err := t.store.RenameTimeSlot(t.activeSlot.ID, taskName); err != nil {
+			return nil, err
+		}
+
+		t.activeSlot.TaskName = taskName
+		return t.activeCopy(), nil
+	})
+}
+
+// AddInterruption records an interruption of the active slot at the current time
+// It runs as a transition, so the slot can't stop between the check and the write
+func (t *Timer) AddInterruption(note string) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, ErrTimerNotRunning
+		}
+
+		if _, err := t.store.AddInterruption(t.activeSlot.ID, t.now(), note); err != nil {
+			return nil, err
+		}
+		return t.activeCopy(), nil
+	})
+}
+
+// AppendNote adds a line to the notes of the active slot without stopping it
+func (t *Timer) AppendNote(line string) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, ErrTimerNotRunning
+		}
+
+		notes := line
+		if t.activeSlot.Notes != "" {
+			notes = t.activeSlot.Notes + "\n" + line
+		}
+		if err := t.store.SetTimeSlotNotes(t.activeSlot.ID, notes); err != nil {
+			return nil, err
+		}
+
+		t.activeSlot.Notes = notes
+		return t.activeCopy(), nil
+	})
+}
+
+// SetExternalRef sets the external reference of any time slot and keeps the
+// running slot in sync when it is the one changed
+func (t *Timer) SetExternalRef(id int64, externalRef string) error {
+	return t.doErr(func() error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if err := t.store.SetTimeSlotRef(id, externalRef); err != nil {
+			return err
+		}
+		if t.activeSlot != nil && t.activeSlot.ID == id {
+			t.activeSlot.ExternalRef = externalRef
+		}
+		return nil
+	})
+}
+
+// SetEstimate sets how long a time slot is expected to take
+func (t *Timer) SetEstimate(id int64, estimate time.Duration) error {
+	return t.doErr(func() error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		estimateSeconds := int64(estimate.Seconds())
+		if err := t.store.SetTimeSlotEstimate(id, estimateSeconds); err != nil {
+			return err
+		}
+		if t.activeSlot != nil && t.activeSlot.ID == id {
+			t.activeSlot.EstimateSeconds = estimateSeconds
+		}
+		return nil
+	})
+}
+
+// AdjustStart moves the start time of the active slot so elapsed time recomputes
+func (t *Timer) AdjustStart(startTime time.Time) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, fmt.Errorf("%w, nothing to adjust", ErrTimerNotRunning)
+		}
+
+		if err := t.store.SetTimeSlotStart(t.activeSlot.ID, startTime); err != nil {
+			return nil, err
+		}
+
+		t.activeSlot.StartTime = startTime
+		t.startTime = startTime
+		return t.activeCopy(), nil
+	})
 }
 
-// GetActiveSlot returns the currently active time slot
+// MergeWithPrevious folds the previous completed slot into the active one when it
+// is for the same task and ended at most maxGap before the active slot started
+func (t *Timer) MergeWithPrevious(maxGap time.Duration) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || !t.activeSlot.IsActive() {
+			return nil, fmt.Errorf("%w, nothing to merge", ErrTimerNotRunning)
+		}
+
+		previous, err := t.store.GetLastCompletedSlot()
+		if err != nil {
+			return nil, err
+		}
+		if previous == nil {
+			return nil, fmt.Errorf("no previous time slot to merge with")
+		}
+		if previous.TaskName != t.activeSlot.TaskName {
+			return nil, fmt.Errorf("previous time slot is for '%s', not '%s'", previous.TaskName, t.activeSlot.TaskName)
+		}
+
+		gap := t.activeSlot.StartTime.Sub(*previous.EndTime)
+		if gap < 0 {
+			return nil, fmt.Errorf("%w: the previous time slot ends after the active one starts", ErrOverlap)
+		}
+		if gap > maxGap {
+			return nil, fmt.Errorf("gap of %s since the previous time slot exceeds %s", formatDuration(gap), formatDuration(maxGap))
+		}
+
+		if err := t.store.MergeIntoActiveSlot(previous.ID, t.activeSlot.ID, previous.StartTime); err != nil {
+			return nil, err
+		}
+
+		t.activeSlot.StartTime = previous.StartTime
+		t.startTime = previous.StartTime
+		return t.activeCopy(), nil
+	})
+}
+
+// Reopen resumes the most recently stopped slot if it ended at most maxAge ago
+// The slot keeps its start time and pauses; the time since the stop counts as tracked
+func (t *Timer) Reopen(maxAge time.Duration) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot != nil && t.activeSlot.IsActive() {
+			return nil, fmt.Errorf("stop the running timer before reopening a time slot")
+		}
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("reopening stopped time slots is disabled")
+		}
+
+		last, err := t.store.GetLastCompletedSlot()
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			return nil, fmt.Errorf("stopped time slot %w", ErrNotFound)
+		}
+		if since := t.now().Sub(*last.EndTime); since > maxAge {
+			return nil, fmt.Errorf("the last time slot was stopped %s ago, only slots stopped within %s can be reopened",
+				formatDuration(since), formatDuration(maxAge))
+		}
+
+		if err := t.store.ReopenSlot(last.ID); err != nil {
+			return nil, err
+		}
+
+		last.EndTime = nil
+		last.DurationSeconds = 0
+		last.PausedAt = nil
+		last.AutoStopped = false
+		t.activeSlot = last
+		t.isRunning = true
+		t.startTime = last.StartTime
+		t.planned = 0
+
+		// Notify that timer started
+		select {
+		case t.notifyChannel <- true:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
+}
+
+// ExcludeIdle stops counting [start, end) towards the running slot with the given id
+// by adding it to the slot's paused time
+func (t *Timer) ExcludeIdle(id int64, start time.Time, end time.Time) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || t.activeSlot.ID != id {
+			return nil, fmt.Errorf("%w, the idle time belongs to a stopped time slot", ErrTimerNotRunning)
+		}
+
+		pausedSeconds := t.activeSlot.PausedSeconds + int64(end.Sub(start).Seconds())
+		if err := t.store.SetTimeSlotPause(id, pausedSeconds, t.activeSlot.PausedAt); err != nil {
+			return nil, err
+		}
+
+		t.activeSlot.PausedSeconds = pausedSeconds
+		return t.activeCopy(), nil
+	})
+}
+
+// ReassignIdle moves [start, end) of the running slot with the given id to another
+// task: the slot ends at start, [start, end) is recorded for taskName and the
+// original task continues in a new slot from end
+func (t *Timer) ReassignIdle(id int64, start time.Time, end time.Time, taskName string) (*models.TimeSlot, error) {
+	return t.do(func() (*models.TimeSlot, error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if t.activeSlot == nil || t.activeSlot.ID != id {
+			return nil, fmt.Errorf("%w, the idle time belongs to a stopped time slot", ErrTimerNotRunning)
+		}
+		if t.activeSlot.IsPaused() {
+			return nil, fmt.Errorf("resume the timer before reassigning idle time")
+		}
+
+		continued, err := t.store.SplitActiveSlot(id, start, end, taskName)
+		if err != nil {
+			return nil, err
+		}
+
+		// The plan counts the time tracked on the task, so the continued slot gets
+		// what is left of it; a plan already reached stays reached
+		if t.planned > 0 {
+			tracked := start.Sub(t.activeSlot.StartTime) - t.activeSlot.PausedUntil(start)
+			t.planned = max(t.planned-tracked, time.Second)
+		}
+
+		continued.TaskName = t.activeSlot.TaskName
+		t.activeSlot = continued
+		t.startTime = end
+
+		// Notify that a new slot is running
+		select {
+		case t.notifyChannel <- true:
+		default:
+		}
+
+		return t.activeCopy(), nil
+	})
+}
+
+// Changes signals timer starts and resumes (true) and stops and pauses (false)
+// Signals are coalesced when nobody is listening, so receivers should
+// re-read the timer state instead of relying on the value alone
+func (t *Timer) Changes() <-chan bool {
+	return t.notifyChannel
+}
+
+// GetActiveSlot returns a copy of the currently active time slot
+// The timer keeps changing its own slot, so callers never share it
 func (t *Timer) GetActiveSlot() *models.TimeSlot {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	return t.activeSlot
+	return t.activeCopy()
+}
+
+// activeCopy returns a deep copy of the active slot, nil when there is none
+// Every method handing out the active slot returns a copy, since callers such as
+// the tray and event listeners read it on other goroutines while the timer changes it
+// Caller must hold the lock
+func (t *Timer) activeCopy() *models.TimeSlot {
+	if t.activeSlot == nil {
+		return nil
+	}
+	slot := *t.activeSlot
+	if slot.EndTime != nil {
+		endTime := *slot.EndTime
+		slot.EndTime = &endTime
+	}
+	if slot.PausedAt != nil {
+		pausedAt := *slot.PausedAt
+		slot.PausedAt = &pausedAt
+	}
+	return &slot
+}
+
+// GetActiveTaskName returns the task name of the running slot, or "" when nothing runs
+func (t *Timer) GetActiveTaskName() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.activeSlot == nil {
+		return ""
+	}
+	return t.activeSlot.TaskName
 }
 
 // IsRunning returns whether the timer is currently running
@@ -96,35 +828,41 @@ func (t *Timer) IsRunning() bool {
 	return t.isRunning
 }
 
-// GetElapsedTime returns the elapsed time since the timer started
+// GetElapsedTime returns the elapsed time since the timer started, pauses excluded
 func (t *Timer) GetElapsedTime() time.Duration {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 	if !t.isRunning || t.activeSlot == nil {
 		return 0
 	}
-	return time.Since(t.startTime)
+	now := t.now()
+	if now.Before(t.startTime) {
+		// The slot starts at the rounded end of the previous one
+		return 0
+	}
+	return now.Sub(t.startTime) - t.activeSlot.PausedUntil(now)
 }
 
-// LoadActiveSlot loads the active slot from database
-func (t *Timer) LoadActiveSlot(db *Database) error {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+// LoadActiveSlot loads the active slot from the store
+func (t *Timer) LoadActiveSlot() error {
+	return t.doErr(func() error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
 
-	slot, err := db.GetActiveTimeSlot()
-	if err != nil {
-		return err
-	}
+		slot, err := t.store.GetActiveTimeSlot()
+		if err != nil {
+			return err
+		}
 
-	if slot != nil {
-		t.activeSlot = slot
-		t.isRunning = true
-		t.startTime = slot.StartTime
-	} else {
-		t.activeSlot = nil
-		t.isRunning = false
-	}
+		if slot != nil {
+			t.activeSlot = slot
+			t.isRunning = true
+			t.startTime = slot.StartTime
+		} else {
+			t.activeSlot = nil
+			t.isRunning = false
+		}
 
-	return nil
+		return nil
+	})
 }
-
@@ -0,0 +1,500 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"light-tracking/internal/models"
+)
+
+// newTestTimer returns a timer on an empty fake store, closed when the test ends
+func newTestTimer(t *testing.T) (*Timer, *fakeStore) {
+	t.Helper()
+
+	store := newFakeStore()
+	timer := NewTimer(store)
+	t.Cleanup(timer.Close)
+	return timer, store
+}
+
+func TestTimerStartStopsRunningSlot(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	first, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	second, err := timer.Start("Review", models.KindWork, "PROJ-1", "office")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if stored := store.get(first.ID); stored.IsActive() {
+		t.Error("first slot is still active after the second start")
+	}
+	if active := store.active(); len(active) != 1 || active[0].ID != second.ID {
+		t.Fatalf("active slots = %v, want only %d", active, second.ID)
+	}
+	if got := timer.GetActiveTaskName(); got != "Review" {
+		t.Errorf("active task = %q, want %q", got, "Review")
+	}
+	if got := timer.GetActiveSlot(); got.ExternalRef != "PROJ-1" || got.Context != "office" {
+		t.Errorf("active slot ref and context = %q, %q", got.ExternalRef, got.Context)
+	}
+}
+
+func TestTimerStopWithoutRunningSlot(t *testing.T) {
+	timer, _ := newTestTimer(t)
+
+	slot, err := timer.Stop()
+	if err != nil || slot != nil {
+		t.Fatalf("Stop = %v, %v, want nil, nil", slot, err)
+	}
+}
+
+func TestTimerStoreErrorKeepsState(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	running, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	failure := errors.New("disk full")
+	store.failNext = failure
+	if _, err := timer.Stop(); !errors.Is(err, failure) {
+		t.Fatalf("Stop error = %v, want %v", err, failure)
+	}
+	if !timer.IsRunning() || timer.GetActiveSlot().ID != running.ID {
+		t.Error("a failed stop changed the timer state")
+	}
+
+	store.failNext = failure
+	if _, err := timer.Start("Review", models.KindWork, "", ""); !errors.Is(err, failure) {
+		t.Fatalf("Start error = %v, want %v", err, failure)
+	}
+	if got := timer.GetActiveSlot().TaskName; got != "Design" {
+		t.Errorf("active task after a failed start = %q, want %q", got, "Design")
+	}
+	// Stopping the running slot and creating the next one fail together
+	if !store.get(running.ID).IsActive() || store.get(running.ID+1) != nil {
+		t.Error("a failed start stopped the running slot or created a new one")
+	}
+}
+
+func TestTimerPauseResume(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	if _, err := timer.Pause(); !errors.Is(err, ErrTimerNotRunning) {
+		t.Fatalf("Pause without a running slot = %v, want ErrTimerNotRunning", err)
+	}
+
+	slot, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timer.Pause(); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if !timer.IsPaused() || store.get(slot.ID).PausedAt == nil {
+		t.Fatal("the pause wasn't recorded")
+	}
+	if _, err := timer.Pause(); err == nil {
+		t.Error("pausing twice succeeded")
+	}
+
+	if _, err := timer.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if timer.IsPaused() || store.get(slot.ID).PausedAt != nil {
+		t.Error("the slot is still paused after Resume")
+	}
+}
+
+func TestTimerRenameAndNotes(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	if _, err := timer.Rename("Review"); !errors.Is(err, ErrTimerNotRunning) {
+		t.Fatalf("Rename without a running slot = %v, want ErrTimerNotRunning", err)
+	}
+
+	slot, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timer.Rename("Review"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if _, err := timer.AppendNote("first"); err != nil {
+		t.Fatalf("AppendNote: %v", err)
+	}
+	if _, err := timer.AppendNote("second"); err != nil {
+		t.Fatalf("AppendNote: %v", err)
+	}
+
+	stored := store.get(slot.ID)
+	if stored.TaskName != "Review" || stored.Notes != "first\nsecond" {
+		t.Errorf("stored slot = %q with notes %q", stored.TaskName, stored.Notes)
+	}
+	if got := timer.GetActiveSlot(); got.TaskName != stored.TaskName || got.Notes != stored.Notes {
+		t.Errorf("timer slot %q %q differs from the stored one", got.TaskName, got.Notes)
+	}
+}
+
+func TestTimerUndoStartResumesReplacedSlot(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	first, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	mistake, err := timer.Start("Review", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	resumed, err := timer.UndoStart(time.Minute)
+	if err != nil {
+		t.Fatalf("UndoStart: %v", err)
+	}
+	if resumed == nil || resumed.ID != first.ID {
+		t.Fatalf("resumed slot = %v, want %d", resumed, first.ID)
+	}
+	if store.get(mistake.ID) != nil {
+		t.Error("the undone slot was not deleted")
+	}
+	if !store.get(first.ID).IsActive() {
+		t.Error("the replaced slot was not reopened")
+	}
+	if timer.CanUndoStart(time.Minute) {
+		t.Error("a resumed slot can be undone again")
+	}
+}
+
+func TestTimerUndoStartDisabled(t *testing.T) {
+	timer, _ := newTestTimer(t)
+	if _, err := timer.Start("Design", models.KindWork, "", ""); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timer.UndoStart(0); err == nil {
+		t.Error("UndoStart with no grace period succeeded")
+	}
+}
+
+func TestTimerReopen(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	slot, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timer.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	reopened, err := timer.Reopen(time.Minute)
+	if err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if reopened.ID != slot.ID || !timer.IsRunning() || !store.get(slot.ID).IsActive() {
+		t.Errorf("slot %d wasn't reopened", slot.ID)
+	}
+	if _, err := timer.Reopen(time.Minute); err == nil {
+		t.Error("Reopen succeeded while a slot is running")
+	}
+}
+
+func TestTimerReassignIdle(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	slot, err := timer.Start("Design", models.KindWork, "PROJ-1", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	idleStart := slot.StartTime.Add(time.Millisecond)
+	idleEnd := idleStart.Add(time.Millisecond)
+
+	continued, err := timer.ReassignIdle(slot.ID, idleStart, idleEnd, "Meeting")
+	if err != nil {
+		t.Fatalf("ReassignIdle: %v", err)
+	}
+	if continued.ID == slot.ID || continued.TaskName != "Design" || continued.ExternalRef != "PROJ-1" {
+		t.Errorf("continued slot = %+v", continued)
+	}
+	if !continued.StartTime.Equal(idleEnd) {
+		t.Errorf("continued slot starts at %v, want %v", continued.StartTime, idleEnd)
+	}
+	if stored := store.get(slot.ID); stored.IsActive() || !stored.EndTime.Equal(idleStart) {
+		t.Errorf("original slot wasn't ended at the idle start")
+	}
+	if _, err := timer.ReassignIdle(slot.ID, idleStart, idleEnd, "Meeting"); !errors.Is(err, ErrTimerNotRunning) {
+		t.Errorf("reassigning idle time of a stopped slot = %v, want ErrTimerNotRunning", err)
+	}
+}
+
+func TestTimerLoadActiveSlot(t *testing.T) {
+	timer, store := newTestTimer(t)
+	running, err := store.CreateTimeSlot("Design", models.KindWork, "", "", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CreateTimeSlot: %v", err)
+	}
+
+	if err := timer.LoadActiveSlot(); err != nil {
+		t.Fatalf("LoadActiveSlot: %v", err)
+	}
+	if !timer.IsRunning() || timer.GetActiveSlot().ID != running.ID {
+		t.Fatal("the stored active slot wasn't loaded")
+	}
+	if elapsed := timer.GetElapsedTime(); elapsed < time.Hour {
+		t.Errorf("elapsed = %v, want at least an hour", elapsed)
+	}
+}
+
+func TestTimerGetActiveSlotReturnsCopy(t *testing.T) {
+	timer, _ := newTestTimer(t)
+	if _, err := timer.Start("Design", models.KindWork, "", ""); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	slot := timer.GetActiveSlot()
+	slot.TaskName = "Changed"
+	if got := timer.GetActiveTaskName(); got != "Design" {
+		t.Errorf("changing a returned slot renamed the running one to %q", got)
+	}
+}
+
+func TestTimerClosed(t *testing.T) {
+	timer, _ := newTestTimer(t)
+	timer.Close()
+	timer.Close()
+
+	if _, err := timer.Start("Design", models.KindWork, "", ""); !errors.Is(err, ErrTimerClosed) {
+		t.Errorf("Start after Close = %v, want ErrTimerClosed", err)
+	}
+	if err := timer.LoadActiveSlot(); !errors.Is(err, ErrTimerClosed) {
+		t.Errorf("LoadActiveSlot after Close = %v, want ErrTimerClosed", err)
+	}
+}
+
+// TestTimerConcurrentTransitions hammers the timer from many goroutines; run it
+// with -race. However the transitions interleave, at most one slot may be active
+// and the timer must agree with the store about which one.
+func TestTimerConcurrentTransitions(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	const workers = 16
+	const rounds = 200
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				switch (w + i) % 6 {
+				case 0, 1:
+					if _, err := timer.Start(fmt.Sprintf("Task %d", w), models.KindWork, "", ""); err != nil {
+						t.Errorf("Start: %v", err)
+						return
+					}
+				case 2:
+					if _, err := timer.Stop(); err != nil {
+						t.Errorf("Stop: %v", err)
+						return
+					}
+				case 3:
+					// Pausing a stopped or paused timer is an expected failure
+					timer.Pause()
+					timer.Resume()
+				case 4:
+					timer.Rename(fmt.Sprintf("Renamed %d", w))
+				default:
+					timer.GetActiveSlot()
+					timer.GetElapsedTime()
+					timer.IsRunning()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	active := store.active()
+	if len(active) > 1 {
+		t.Fatalf("%d slots are active, want at most one", len(active))
+	}
+	running := timer.GetActiveSlot()
+	switch {
+	case len(active) == 0 && running != nil:
+		t.Errorf("timer runs slot %d, the store has none active", running.ID)
+	case len(active) == 1 && (running == nil || running.ID != active[0].ID):
+		t.Errorf("timer runs %v, the store's active slot is %d", running, active[0].ID)
+	case len(active) == 1 && running.TaskName != active[0].TaskName:
+		t.Errorf("timer task %q differs from the stored %q", running.TaskName, active[0].TaskName)
+	}
+	if timer.IsRunning() != (running != nil) {
+		t.Errorf("IsRunning = %v with active slot %v", timer.IsRunning(), running)
+	}
+}
+
+func TestTimerStopRounding(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{"rounds down below the midpoint", 7*time.Minute + 29*time.Second, 5 * time.Minute},
+		{"rounds up at the midpoint", 7*time.Minute + 30*time.Second, 10 * time.Minute},
+		{"rounds up above the midpoint", 13 * time.Minute, 15 * time.Minute},
+		{"short slots round up", 3 * time.Minute, 5 * time.Minute},
+		{"exact increments are kept", 20 * time.Minute, 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer, store := newTestTimer(t)
+			timer.SetStopRounding(5 * time.Minute)
+
+			slot, err := timer.Start("Invoice", models.KindWork, "", "")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			start := slot.StartTime
+			if _, err := timer.StopAt(start.Add(tt.elapsed)); err != nil {
+				t.Fatalf("StopAt: %v", err)
+			}
+
+			stored := store.get(slot.ID)
+			if !stored.StartTime.Equal(start) {
+				t.Errorf("start = %v, want the raw start %v", stored.StartTime, start)
+			}
+			if got := stored.EndTime.Sub(start); got != tt.want {
+				t.Errorf("end is %v after the start, want %v", got, tt.want)
+			}
+			if got := time.Duration(stored.DurationSeconds) * time.Second; got != tt.want {
+				t.Errorf("duration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimerStopRoundingExcludesPauses(t *testing.T) {
+	timer, store := newTestTimer(t)
+	clock := newFakeClock(time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	timer.setClock(clock.Now)
+	timer.SetStopRounding(5 * time.Minute)
+
+	slot, err := timer.Start("Invoice", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	clock.Advance(4 * time.Minute)
+	if _, err := timer.Pause(); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	clock.Advance(2 * time.Minute)
+	if _, err := timer.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	clock.Advance(4 * time.Minute)
+	if _, err := timer.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	// 8 minutes of work round to 10, the 2 minute pause stays on top
+	stored := store.get(slot.ID)
+	if got := stored.EndTime.Sub(slot.StartTime); got != 12*time.Minute {
+		t.Errorf("end is %v after the start, want 12m", got)
+	}
+	if stored.DurationSeconds != 600 {
+		t.Errorf("duration = %ds, want 600s", stored.DurationSeconds)
+	}
+}
+
+func TestTimerStartAfterRoundedStopDoesNotOverlap(t *testing.T) {
+	for _, switchTask := range []bool{false, true} {
+		name := "stop then start"
+		if switchTask {
+			name = "start while running"
+		}
+		t.Run(name, func(t *testing.T) {
+			timer, store := newTestTimer(t)
+			clock := newFakeClock(time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+			timer.setClock(clock.Now)
+			timer.SetStopRounding(5 * time.Minute)
+
+			first, err := timer.Start("Invoice", models.KindWork, "", "")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			// 13 minutes round up to 15, past the current time
+			clock.Advance(13 * time.Minute)
+			if !switchTask {
+				if _, err := timer.Stop(); err != nil {
+					t.Fatalf("Stop: %v", err)
+				}
+			}
+			second, err := timer.Start("Review", models.KindWork, "", "")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+
+			end := *store.get(first.ID).EndTime
+			if !end.Equal(first.StartTime.Add(15 * time.Minute)) {
+				t.Fatalf("first slot ends %v, want 15 minutes after its start", end)
+			}
+			if second.StartTime.Before(end) {
+				t.Errorf("second slot starts %v, before the first one ends at %v", second.StartTime, end)
+			}
+			if got := timer.GetElapsedTime(); got != 0 {
+				t.Errorf("elapsed = %v before the second slot starts, want 0", got)
+			}
+			clock.Advance(5 * time.Minute)
+			if got := timer.GetElapsedTime(); got != 3*time.Minute {
+				t.Errorf("elapsed = %v, want 3m", got)
+			}
+		})
+	}
+}
+
+func TestTimerStopRoundingDisabled(t *testing.T) {
+	timer, store := newTestTimer(t)
+
+	slot, err := timer.Start("Invoice", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := timer.StopAt(slot.StartTime.Add(7*time.Minute + 29*time.Second)); err != nil {
+		t.Fatalf("StopAt: %v", err)
+	}
+	if got := store.get(slot.ID).DurationSeconds; got != 449 {
+		t.Errorf("duration = %ds, want the raw 449s", got)
+	}
+}
+
+func TestTimerAddInterruption(t *testing.T) {
+	timer, store := newTestTimer(t)
+	clock := newFakeClock(time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local))
+	timer.setClock(clock.Now)
+
+	if _, err := timer.AddInterruption("question"); !errors.Is(err, ErrTimerNotRunning) {
+		t.Fatalf("AddInterruption without a running slot = %v, want ErrTimerNotRunning", err)
+	}
+
+	slot, err := timer.Start("Design", models.KindWork, "", "")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	clock.Advance(10 * time.Minute)
+	if _, err := timer.AddInterruption("question"); err != nil {
+		t.Fatalf("AddInterruption: %v", err)
+	}
+
+	interruptions, err := store.GetInterruptions(slot.ID)
+	if err != nil {
+		t.Fatalf("GetInterruptions: %v", err)
+	}
+	if len(interruptions) != 1 || !interruptions[0].Time.Equal(clock.Now()) || interruptions[0].Note != "question" {
+		t.Errorf("interruptions = %+v, want one at %v", interruptions, clock.Now())
+	}
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// Trend bucket sizes accepted by GetTrend
+const (
+	TrendHour  = "hour"
+	TrendDay   = "day"
+	TrendWeek  = "week"
+	TrendMonth = "month"
+)
+
+// maxTrendBuckets bounds the series length, e.g. hourly buckets over a year are about 8800
+const maxTrendBuckets = 10000
+
+// TrendPoint is the time tracked in one bucket of a trend series
+type TrendPoint struct {
+	// Bucket is "2006-01-02 15:00", "2006-01-02", "2006-W01" (ISO week) or "2006-01"
+	Bucket  string `json:"bucket"`
+	Seconds int64  `json:"seconds"`
+}
+
+// trendBucket returns the key and the start of the next bucket for the given size
+func trendBucket(bucket string) (key func(time.Time) string, next func(time.Time) time.Time, err error) {
+	switch bucket {
+	case TrendHour:
+		return func(t time.Time) string { return t.Format("2006-01-02 15:00") },
+			func(t time.Time) time.Time { return t.Truncate(time.Hour).Add(time.Hour) }, nil
+	case TrendDay:
+		return dayBucket,
+			func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			}, nil
+	case TrendWeek:
+		return func(t time.Time) string {
+				year, week := t.ISOWeek()
+				return fmt.Sprintf("%04d-W%02d", year, week)
+			}, func(t time.Time) time.Time {
+				daysToMonday := 7 - (int(t.Weekday())+6)%7
+				return time.Date(t.Year(), t.Month(), t.Day()+daysToMonday, 0, 0, 0, 0, t.Location())
+			}, nil
+	case TrendMonth:
+		return func(t time.Time) string { return t.Format("2006-01") },
+			func(t time.Time) time.Time { return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location()) }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trend bucket %q, expected %q, %q, %q or %q",
+			bucket, TrendHour, TrendDay, TrendWeek, TrendMonth)
+	}
+}
+
+// trendSeries lists every bucket touching [start, end) in order with its total,
+// so buckets without time show up as zero
+func trendSeries(totals map[string]int64, start, end time.Time, key func(time.Time) string, next func(time.Time) time.Time) ([]TrendPoint, error) {
+	series := []TrendPoint{}
+	for t := start; t.Before(end); t = next(t) {
+		bucket := key(t)
+		// The hour repeated when clocks go back has one key
+		if len(series) > 0 && series[len(series)-1].Bucket == bucket {
+			continue
+		}
+		if len(series) == maxTrendBuckets {
+			return nil, fmt.Errorf("too many buckets, use a larger bucket or a shorter range")
+		}
+		series = append(series, TrendPoint{Bucket: bucket, Seconds: totals[bucket]})
+	}
+	return series, nil
+}
+
+// GetTrend returns the seconds tracked on a task per hour, day, week or month
+// between two dates (inclusive), oldest first, with empty buckets as zero so
+// charts stay continuous; an empty task name sums all work time
+// Slots count towards the bucket they start in, by local time
+// Dates should be in format "2006-01-02" (YYYY-MM-DD)
+// roundToMinutes and mode round the reported durations, see newReportRounding
+func (a *App) GetTrend(taskName string, startStr string, endStr string, bucket string, roundToMinutes int, mode string) ([]TrendPoint, error) {
+	rounding, err := newReportRounding(roundToMinutes, mode)
+	if err != nil {
+		return nil, err
+	}
+	key, next, err := trendBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := parseDateRange(startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := a.database.GetTrendTotals(normalizeTaskName(taskName), start, end, key)
+	if err != nil {
+		return nil, err
+	}
+	return trendSeries(rounding.totals(totals), start, end, key, next)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// ValidationResult describes whether a time slot edit can be applied
+// Errors block the edit; warnings are informational
+type ValidationResult struct {
+	Valid          bool     `json:"valid"`
+	Errors         []string `json:"errors"`
+	Warnings       []string `json:"warnings"`
+	OverlappingIDs []int64  `json:"overlapping_ids"`
+}
+
+func (r *ValidationResult) addError(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+	r.Valid = false
+}
+
+func (r *ValidationResult) addWarning(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// validateTimeSlotEdit checks a proposed edit of slot id without writing anything
+// endTime is nil when the slot should stay active
+func (a *App) validateTimeSlotEdit(id int64, startTime time.Time, endTime *time.Time) (*ValidationResult, error) {
+	result := &ValidationResult{
+		Valid:          true,
+		Errors:         []string{},
+		Warnings:       []string{},
+		OverlappingIDs: []int64{},
+	}
+
+	slot, err := a.database.GetTimeSlot(id)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		result.addError("time slot %d not found", id)
+		return result, nil
+	}
+
+	now := a.now()
+	if startTime.After(now) {
+		result.addError("start time is in the future")
+	}
+
+	overlapEnd := now
+	if endTime != nil {
+		if !endTime.After(startTime) {
+			result.addError("end time must be after start time")
+		}
+		if endTime.After(now) {
+			result.addError("end time is in the future")
+		}
+		overlapEnd = *endTime
+	}
+
+	if !overlapEnd.After(startTime) {
+		return result, nil
+	}
+
+	overlapping, err := a.database.GetOverlappingTimeSlots(id, startTime, overlapEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range overlapping {
+		result.OverlappingIDs = append(result.OverlappingIDs, other.ID)
+		result.addWarning("overlaps with '%s' started at %s", other.TaskName, other.StartTime.Format("15:04"))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ssidTimeout bounds the network tool run on every start
+const ssidTimeout = 2 * time.Second
+
+// currentSSID returns the name of the connected Wi-Fi network, empty when there is none
+// macOS asks networksetup about en0; Linux needs NetworkManager's nmcli
+func currentSSID() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ssidTimeout)
+	defer cancel()
+
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.CommandContext(ctx, "networksetup", "-getairportnetwork", "en0").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run networksetup: %w", err)
+		}
+		_, ssid, found := strings.Cut(strings.TrimSpace(string(out)), "Current Wi-Fi Network: ")
+		if !found {
+			return "", nil
+		}
+		return ssid, nil
+	case "linux":
+		out, err := exec.CommandContext(ctx, "nmcli", "-t", "-f", "active,ssid", "dev", "wifi").Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run nmcli: %w", err)
+		}
+		return parseNmcliSSID(string(out)), nil
+	default:
+		return "", fmt.Errorf("detecting the Wi-Fi network is not supported on %s", runtime.GOOS)
+	}
+}
+
+// parseNmcliSSID picks the active network from "active:ssid" lines of nmcli
+// Colons inside the name are escaped as "\:"
+func parseNmcliSSID(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		ssid, found := strings.CutPrefix(line, "yes:")
+		if found {
+			return strings.ReplaceAll(strings.TrimSpace(ssid), `\:`, ":")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ssidTimeout bounds the network tool run on every start
+const ssidTimeout = 2 * time.Second
+
+// currentSSID returns the name of the connected Wi-Fi network, empty when there is none
+func currentSSID() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ssidTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "netsh", "wlan", "show", "interfaces")
+	// Don't flash a console window
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run netsh: %w", err)
+	}
+	return parseNetshSSID(string(out)), nil
+}
+
+// parseNetshSSID extracts the "SSID : name" line of netsh output, skipping BSSID
+func parseNetshSSID(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if found && strings.TrimSpace(key) == "SSID" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Window geometry limits in logical pixels
+const (
+	defaultWindowWidth  = 800
+	defaultWindowHeight = 600
+	minWindowWidth      = 400
+	minWindowHeight     = 300
+)
+
+// WindowState is the position and size of the main window
+type WindowState struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// validateWindowState checks window bounds reported by the frontend
+func validateWindowState(state WindowState) error {
+	if state.Width < minWindowWidth || state.Height < minWindowHeight {
+		return fmt.Errorf("window must be at least %dx%d", minWindowWidth, minWindowHeight)
+	}
+	return nil
+}
+
+// clampWindowState fits state onto a screen of the given size, shrinking the
+// window if needed and moving it so it isn't partly off-screen
+func clampWindowState(state WindowState, screenWidth, screenHeight int) WindowState {
+	state.Width = min(state.Width, screenWidth)
+	state.Height = min(state.Height, screenHeight)
+	state.X = max(min(state.X, screenWidth-state.Width), 0)
+	state.Y = max(min(state.Y, screenHeight-state.Height), 0)
+	return state
+}
+
+// currentScreenSize returns the logical size of the screen showing the window
+func currentScreenSize(ctx context.Context) (int, int, error) {
+	screens, err := runtime.ScreenGetAll(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(screens) == 0 {
+		return 0, 0, fmt.Errorf("no screens found")
+	}
+
+	screen := screens[0]
+	for _, s := range screens {
+		if s.IsCurrent {
+			screen = s
+			break
+		}
+		if s.IsPrimary {
+			screen = s
+		}
+	}
+	return screen.Size.Width, screen.Size.Height, nil
+}
+
+// GetWindowState returns the saved window bounds, or nil if none are saved
+// or remembering the window is disabled
+func (a *App) GetWindowState() *WindowState {
+	settings := a.settings.Get()
+	if !settings.RememberWindow || settings.Window == nil {
+		return nil
+	}
+	state := *settings.Window
+	return &state
+}
+
+// InitialWindowSize returns the size to create the window with
+func (a *App) InitialWindowSize() *WindowState {
+	if state := a.GetWindowState(); state != nil {
+		return state
+	}
+	return &WindowState{Width: defaultWindowWidth, Height: defaultWindowHeight}
+}
+
+// SaveWindowState stores the window bounds so the next start restores them
+// Called by the frontend after the window is moved or resized
+func (a *App) SaveWindowState(x int, y int, width int, height int) error {
+	if !a.settings.Get().RememberWindow {
+		return nil
+	}
+
+	state := WindowState{X: x, Y: y, Width: width, Height: height}
+	if err := validateWindowState(state); err != nil {
+		return err
+	}
+	return a.settings.Update(func(s *Settings) {
+		s.Window = &state
+	})
+}
+
+// restoreWindowState moves and resizes the window to the saved bounds,
+// clamped to the current screen in case the monitor setup changed
+func (a *App) restoreWindowState() {
+	state := a.GetWindowState()
+	if state == nil {
+		return
+	}
+
+	screenWidth, screenHeight, err := currentScreenSize(a.ctx)
+	if err != nil {
+		log.Println("Failed to get screen size:", err)
+		return
+	}
+
+	clamped := clampWindowState(*state, screenWidth, screenHeight)
+	runtime.WindowSetSize(a.ctx, clamped.Width, clamped.Height)
+	runtime.WindowSetPosition(a.ctx, clamped.X, clamped.Y)
+}
+
+// BeforeClose saves the window bounds while the window still exists
+// Maximised windows keep the bounds saved before maximising. Closing is never prevented.
+func (a *App) BeforeClose(ctx context.Context) bool {
+	if !a.settings.Get().RememberWindow || runtime.WindowIsMaximised(ctx) {
+		return false
+	}
+
+	x, y := runtime.WindowGetPosition(ctx)
+	width, height := runtime.WindowGetSize(ctx)
+	if err := a.SaveWindowState(x, y, width, height); err != nil {
+		log.Println("Failed to save window state:", err)
+	}
+	return false
+}
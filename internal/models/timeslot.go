@@ -2,6 +2,12 @@ package models
 
 import "time"
 
+// Slot kinds: work is the default, breaks are tracked apart so net work time stays accurate
+const (
+	KindWork  = "work"
+	KindBreak = "break"
+)
+
 // TimeSlot represents a time tracking entry
 type TimeSlot struct {
 	ID              int64     `json:"id"`
@@ -9,6 +15,22 @@ type TimeSlot struct {
 	StartTime       time.Time `json:"start_time"`
 	EndTime         *time.Time `json:"end_time,omitempty"`
 	DurationSeconds int64     `json:"duration_seconds"`
+	// PausedSeconds is the total time spent paused, excluded from DurationSeconds
+	PausedSeconds int64 `json:"paused_seconds"`
+	// PausedAt is set while an active slot is paused
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	// Kind is KindWork or KindBreak
+	Kind string `json:"kind"`
+	// Notes holds free-form notes, one timestamped line per note
+	Notes string `json:"notes"`
+	// ExternalRef links the slot to an issue or ticket, e.g. "PROJ-123"; empty if none
+	ExternalRef string `json:"external_ref"`
+	// Context is where the slot was tracked, e.g. "home" or "office"; empty if unknown
+	Context string `json:"context"`
+	// EstimateSeconds is how long the slot was expected to take; 0 if there was no estimate
+	EstimateSeconds int64 `json:"estimate_seconds"`
+	// AutoStopped is set when the app stopped the slot, e.g. after an unanswered "still working?" prompt
+	AutoStopped bool `json:"auto_stopped"`
 }
 
 // IsActive returns true if the time slot is currently active (no end time)
@@ -16,10 +38,29 @@ func (ts *TimeSlot) IsActive() bool {
 	return ts.EndTime == nil
 }
 
+// IsBreak returns true if the time slot tracks a break rather than work
+func (ts *TimeSlot) IsBreak() bool {
+	return ts.Kind == KindBreak
+}
+
+// IsPaused returns true if the time slot is active and currently paused
+func (ts *TimeSlot) IsPaused() bool {
+	return ts.IsActive() && ts.PausedAt != nil
+}
+
+// PausedUntil returns the total paused time as of t, including a pause still in progress
+func (ts *TimeSlot) PausedUntil(t time.Time) time.Duration {
+	paused := time.Duration(ts.PausedSeconds) * time.Second
+	if ts.PausedAt != nil && t.After(*ts.PausedAt) {
+		paused += t.Sub(*ts.PausedAt)
+	}
+	return paused
+}
+
 // CalculateDuration calculates and sets the duration in seconds
+// Paused time doesn't count towards the duration
 func (ts *TimeSlot) CalculateDuration() {
 	if ts.EndTime != nil {
-		ts.DurationSeconds = int64(ts.EndTime.Sub(ts.StartTime).Seconds())
+		ts.DurationSeconds = int64((ts.EndTime.Sub(ts.StartTime) - ts.PausedUntil(*ts.EndTime)).Seconds())
 	}
 }
-
@@ -22,16 +22,22 @@ func main() {
 	}
 	defer appInstance.Close()
 
+	windowSize := appInstance.InitialWindowSize()
+
 	// Create application with options
 	err = wails.Run(&options.App{
 		Title:  "Light Tracking",
-		Width:  800,
-		Height: 600,
+		Width:  windowSize.Width,
+		Height: windowSize.Height,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 255, G: 255, B: 255, A: 1},
 		OnStartup:        appInstance.Startup,
+		OnDomReady:       appInstance.DomReady,
+		OnBeforeClose:    appInstance.BeforeClose,
+		OnShutdown:       appInstance.Shutdown,
+		ErrorFormatter:   app.FormatError,
 		Bind: []interface{}{
 			appInstance,
 		},